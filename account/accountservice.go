@@ -1,6 +1,7 @@
 package account
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/aergoio/aergo-actor/actor"
@@ -75,6 +76,12 @@ func (as *AccountService) Receive(context actor.Context) {
 	case *message.CreateAccount:
 		account, _ := as.createAccount(msg.Passphrase)
 		context.Respond(&message.CreateAccountRsp{Account: account})
+	case *message.CreateAccountMnemonic:
+		account, mnemonic, err := as.createAccountMnemonic(msg.Passphrase)
+		context.Respond(&message.HDAccountsRsp{Mnemonic: mnemonic, Accounts: []*types.Account{account}, Err: err})
+	case *message.RecoverAccounts:
+		accounts, err := as.recoverAccounts(msg.Mnemonic, msg.Account, msg.Index, msg.Count, msg.Passphrase)
+		context.Respond(&message.HDAccountsRsp{Accounts: accounts, Err: err})
 	case *message.LockAccount:
 		actualAddress := msg.Account.Address
 		var err error
@@ -107,7 +114,7 @@ func (as *AccountService) Receive(context actor.Context) {
 		account, err := as.importAccount(msg.Wif, msg.OldPass, msg.NewPass)
 		context.Respond(&message.ImportAccountRsp{Account: account, Err: err})
 	case *message.ExportAccount:
-		wif, err := as.exportAccount(msg.Account.Address, msg.Pass)
+		wif, err := as.exportAccount(msg.Account.Address, msg.Pass, msg.Legacy)
 		context.Respond(&message.ExportAccountRsp{Wif: wif, Err: err})
 	case *message.SignTx:
 		var err error
@@ -155,6 +162,47 @@ func (as *AccountService) createAccount(passphrase string) (*types.Account, erro
 	return account, nil
 }
 
+func (as *AccountService) createAccountMnemonic(passphrase string) (*types.Account, string, error) {
+	address, mnemonic, err := as.ks.CreateKeyWithMnemonic(passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+	account := types.NewAccount(address)
+
+	as.accountLock.Lock()
+	as.ks.SaveAddress(address)
+	as.accounts = append(as.accounts, account)
+	as.accountLock.Unlock()
+	return account, mnemonic, nil
+}
+
+func (as *AccountService) recoverAccounts(mnemonic string, account, index, count uint32,
+	passphrase string) ([]*types.Account, error) {
+	addresses, err := as.ks.RecoverKeys(mnemonic, account, index, count, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*types.Account, len(addresses))
+	as.accountLock.Lock()
+	for i, address := range addresses {
+		accounts[i] = types.NewAccount(address)
+		if !as.hasAccount(address) {
+			as.accounts = append(as.accounts, accounts[i])
+		}
+	}
+	as.accountLock.Unlock()
+	return accounts, nil
+}
+
+func (as *AccountService) hasAccount(address []byte) bool {
+	for _, account := range as.accounts {
+		if bytes.Equal(account.Address, address) {
+			return true
+		}
+	}
+	return false
+}
+
 func (as *AccountService) importAccount(wif []byte, old string, new string) (*types.Account, error) {
 	address, err := as.ks.ImportKey(wif, old, new)
 	if err != nil {
@@ -170,7 +218,10 @@ func (as *AccountService) importAccount(wif []byte, old string, new string) (*ty
 	return account, nil
 }
 
-func (as *AccountService) exportAccount(address []byte, pass string) ([]byte, error) {
+func (as *AccountService) exportAccount(address []byte, pass string, legacy bool) ([]byte, error) {
+	if legacy {
+		return as.ks.ExportLegacyKey(address, pass)
+	}
 	wif, err := as.ks.ExportKey(address, pass)
 	if err != nil {
 		return nil, err
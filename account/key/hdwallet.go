@@ -0,0 +1,219 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package key
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+// The keys of a HD wallet are derived from the seed of a BIP-39 mnemonic by
+// BIP-32, along the BIP-44 path m/44'/441'/account'/0/index, where 441 is the
+// coin type of Aergo. The seed is made with the empty BIP-39 passphrase, so
+// that the mnemonic alone is enough to recover the keys.
+const (
+	// CoinType is the coin type of Aergo registered in SLIP-0044.
+	CoinType = 441
+
+	// MaxDeriveKeys is the maximum number of the keys derived at once.
+	MaxDeriveKeys = 100
+
+	mnemonicBits = 256
+	purpose      = 44
+)
+
+var (
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrTooManyKeys           = fmt.Errorf("too many keys to derive at once (max %d)", MaxDeriveKeys)
+)
+
+// NewMnemonic generates a new mnemonic of 24 words.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// IsMnemonicValid reports whether the mnemonic has the valid words and
+// checksum.
+func IsMnemonicValid(mnemonic string) bool {
+	return bip39.IsMnemonicValid(mnemonic)
+}
+
+// DerivationPath returns the BIP-44 path of the key at the index of the
+// account.
+func DerivationPath(account, index uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/0/%d", purpose, CoinType, account, index)
+}
+
+// ParseDerivationPath parses the path such as m/44'/441'/0'/0/0 into the
+// indexes of the children, where the hardened ones are marked by ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	elems := strings.Split(strings.TrimSpace(path), "/")
+	if len(elems) < 2 || elems[0] != "m" {
+		return nil, ErrInvalidDerivationPath
+	}
+	var indexes []uint32
+	for _, elem := range elems[1:] {
+		var hardened uint32
+		if strings.HasSuffix(elem, "'") || strings.HasSuffix(elem, "h") {
+			hardened = hdkeychain.HardenedKeyStart
+			elem = elem[:len(elem)-1]
+		}
+		index, err := strconv.ParseUint(elem, 10, 31)
+		if err != nil {
+			return nil, ErrInvalidDerivationPath
+		}
+		indexes = append(indexes, uint32(index)+hardened)
+	}
+	return indexes, nil
+}
+
+// DeriveKey derives the private key at the path from the mnemonic.
+func DeriveKey(mnemonic string, path string) (*aergokey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	extKey, err := masterKey(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if extKey, err = extKey.Derive(index); err != nil {
+			return nil, err
+		}
+	}
+	return extKey.ECPrivKey()
+}
+
+// DeriveAddresses returns the addresses of the keys from the index to
+// index+count-1 of the account, without storing the keys.
+func DeriveAddresses(mnemonic string, account, index, count uint32) ([]Address, error) {
+	keys, err := deriveKeys(mnemonic, account, index, count)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]Address, len(keys))
+	for i, key := range keys {
+		addresses[i] = GenerateAddress(&key.PublicKey)
+	}
+	return addresses, nil
+}
+
+func masterKey(mnemonic string) (*hdkeychain.ExtendedKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	// the network only determines the version of the serialized keys, which
+	// aren't used
+	return hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+}
+
+// deriveKeys derives the keys from the index to index+count-1 of the account.
+// The keys share the parent of the path m/44'/441'/account'/0.
+func deriveKeys(mnemonic string, account, index, count uint32) ([]*aergokey, error) {
+	if count > MaxDeriveKeys {
+		return nil, ErrTooManyKeys
+	}
+	if account >= hdkeychain.HardenedKeyStart || index+count < index || index+count > hdkeychain.HardenedKeyStart {
+		return nil, ErrInvalidDerivationPath
+	}
+	parent, err := masterKey(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range []uint32{purpose + hdkeychain.HardenedKeyStart, CoinType + hdkeychain.HardenedKeyStart,
+		account + hdkeychain.HardenedKeyStart, 0} {
+		if parent, err = parent.Derive(i); err != nil {
+			return nil, err
+		}
+	}
+	var keys []*aergokey
+	for i := index; i < index+count; i++ {
+		child, err := parent.Derive(i)
+		if err == hdkeychain.ErrInvalidChild {
+			// the invalid key is very unlikely, but it isn't skipped so that
+			// the keys are always at the requested indexes
+			return nil, fmt.Errorf("%s: index %d of account %d", err.Error(), i, account)
+		} else if err != nil {
+			return nil, err
+		}
+		key, err := child.ECPrivKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// CreateKeyWithMnemonic makes a new HD wallet and stores the first key of it
+// in keystore. It returns the address of the key and the mnemonic, which must
+// be kept by the user to recover the keys.
+func (ks *Store) CreateKeyWithMnemonic(pass string) (Address, string, error) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		return nil, "", err
+	}
+	keys, err := deriveKeys(mnemonic, 0, 0, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	address, err := ks.addKey(keys[0], pass)
+	if err != nil {
+		return nil, "", err
+	}
+	return address, mnemonic, nil
+}
+
+// RecoverKeys derives the keys from the index to index+count-1 of the account
+// from the mnemonic and stores them in keystore. The keys already in keystore
+// are stored again with the passphrase.
+func (ks *Store) RecoverKeys(mnemonic string, account, index, count uint32, pass string) ([]Address, error) {
+	keys, err := deriveKeys(mnemonic, account, index, count)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := ks.GetAddresses()
+	if err != nil {
+		return nil, err
+	}
+	var addresses []Address
+	for _, key := range keys {
+		address, err := ks.addKey(key, pass)
+		if err != nil {
+			return nil, err
+		}
+		if !containsAddress(saved, address) {
+			if err := ks.SaveAddress(address); err != nil {
+				return nil, err
+			}
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+func containsAddress(addresses []Address, address Address) bool {
+	for _, v := range addresses {
+		if bytes.Equal(v, address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package key
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestDeriveKey(t *testing.T) {
+	tests := []struct {
+		path string
+		key  string
+	}{
+		// the well-known vector of the ethereum path
+		{"m/44'/60'/0'/0/0", "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727"},
+		{DerivationPath(0, 0), "40cb146fa082c9407929bc883682982c27b9c85a82c6b30c13543ded563039fe"},
+		{"m/44h/441h/0h/0/1", "bd87885d3c40038cc6525441108566308ff6d216e3a270fb673ba13358a284e2"},
+	}
+	for _, test := range tests {
+		key, err := DeriveKey(testMnemonic, test.path)
+		if err != nil {
+			t.Errorf("could not derive key of %s : %s", test.path, err.Error())
+			continue
+		}
+		if hex.EncodeToString(key.Serialize()) != test.key {
+			t.Errorf("invalid key of %s : %x", test.path, key.Serialize())
+		}
+	}
+
+	// the key of m/44' has a leading zero byte, which must be padded in the
+	// derivation of its hardened child
+	const leadingZeroMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon cool"
+	for path, want := range map[string]string{
+		"m/44'":              "00a61826a935ae0abbc41f1bb1cdeede862986e48c26353c88924c1fd82111c0",
+		DerivationPath(0, 0): "e852a80e520e056e78310b519a1024678a3f8517fc83b02a8a95590fed5641f3",
+	} {
+		key, err := DeriveKey(leadingZeroMnemonic, path)
+		if err != nil {
+			t.Errorf("could not derive key of %s : %s", path, err.Error())
+		} else if hex.EncodeToString(key.Serialize()) != want {
+			t.Errorf("invalid key of %s : %x", path, key.Serialize())
+		}
+	}
+
+	for _, path := range []string{"", "m", "44'/441'", "m/44'/x", "m/2147483648"} {
+		if _, err := DeriveKey(testMnemonic, path); err != ErrInvalidDerivationPath {
+			t.Errorf("invalid path %q is accepted", path)
+		}
+	}
+	invalid := strings.Replace(testMnemonic, "about", "abandon", 1)
+	if _, err := DeriveKey(invalid, DerivationPath(0, 0)); err != ErrInvalidMnemonic {
+		t.Errorf("invalid mnemonic is accepted")
+	}
+}
+
+func TestDeriveAddresses(t *testing.T) {
+	addresses, err := DeriveAddresses(testMnemonic, 0, 0, 3)
+	if err != nil {
+		t.Fatalf("could not derive addresses : %s", err.Error())
+	}
+	if len(addresses) != 3 {
+		t.Fatalf("invalid number of addresses : %d", len(addresses))
+	}
+	for i, address := range addresses {
+		key, _ := DeriveKey(testMnemonic, DerivationPath(0, uint32(i)))
+		if !bytes.Equal(address, GenerateAddress(&key.PublicKey)) {
+			t.Errorf("invalid address of index %d", i)
+		}
+	}
+	if _, err := DeriveAddresses(testMnemonic, 0, 0, MaxDeriveKeys+1); err != ErrTooManyKeys {
+		t.Errorf("too many keys are derived")
+	}
+}
+
+func TestCreateAndRecoverKeys(t *testing.T) {
+	initTest()
+	defer deinitTest()
+	address, mnemonic, err := ks.CreateKeyWithMnemonic("pass")
+	if err != nil {
+		t.Fatalf("could not create key : %s", err.Error())
+	}
+	if len(strings.Fields(mnemonic)) != 24 || !IsMnemonicValid(mnemonic) {
+		t.Fatalf("invalid mnemonic : %s", mnemonic)
+	}
+	if _, err := ks.getKey(address, "pass"); err != nil {
+		t.Errorf("could not get created key : %s", err.Error())
+	}
+
+	recovered, err := ks.RecoverKeys(mnemonic, 0, 0, 2, "newpass")
+	if err != nil {
+		t.Fatalf("could not recover keys : %s", err.Error())
+	}
+	if len(recovered) != 2 || !bytes.Equal(recovered[0], address) {
+		t.Fatalf("invalid recovered addresses")
+	}
+	for _, addr := range recovered {
+		if _, err := ks.getKey(addr, "newpass"); err != nil {
+			t.Errorf("could not get recovered key : %s", err.Error())
+		}
+	}
+	saved, err := ks.GetAddresses()
+	if err != nil {
+		t.Fatalf("could not get addresses : %s", err.Error())
+	}
+	if len(saved) != 2 {
+		t.Errorf("invalid number of saved addresses : %d", len(saved))
+	}
+}
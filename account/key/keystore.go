@@ -0,0 +1,197 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/scrypt"
+)
+
+// A key is stored in the keystore format, which encrypts the key by
+// AES-256-GCM with the key derived from the passphrase by scrypt. The salt
+// and the nonce are random for each encryption, and the stored key is looked
+// up by the address only, so that a passphrase can't be tested without
+// running scrypt.
+//
+// The keys of the old format, which are encrypted with sha256(address +
+// passphrase) and a nonce taken from the address, are migrated to the
+// keystore format when they are used first.
+const (
+	// KeystoreVersion is the version of the keystore format.
+	KeystoreVersion = 1
+
+	kdfScrypt    = "scrypt"
+	cipherAESGCM = "aes-256-gcm"
+
+	scryptR      = 8
+	scryptKeyLen = 32
+	saltLength   = 32
+
+	// maxScryptN and maxScryptP limit the cost of an imported keystore.
+	maxScryptN = 1 << 18
+	maxScryptP = 16
+)
+
+var (
+	// scryptN and scryptP are the cost parameters of the new keystores,
+	// which are lowered by the tests.
+	scryptN = 1 << 18
+	scryptP = 1
+
+	ErrInvalidKeystore = errors.New("invalid keystore")
+)
+
+var keystorePrefix = []byte("KEYSTORE")
+
+// Keystore is the encrypted key in the keystore format.
+type Keystore struct {
+	Version    int           `json:"version"`
+	Address    string        `json:"address"`
+	KDF        string        `json:"kdf"`
+	KDFParams  *ScryptParams `json:"kdfparams"`
+	Cipher     string        `json:"cipher"`
+	Nonce      string        `json:"nonce"`
+	CipherText string        `json:"ciphertext"`
+}
+
+// ScryptParams is the parameters of scrypt to derive the encryption key.
+type ScryptParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// EncryptKeystore encrypts a private key with a passphrase into the keystore
+// format.
+func EncryptKeystore(key []byte, pass string) ([]byte, error) {
+	_, pubkey := btcec.PrivKeyFromBytes(btcec.S256(), key)
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	params := &ScryptParams{N: scryptN, R: scryptR, P: scryptP, DKLen: scryptKeyLen, Salt: hex.EncodeToString(salt)}
+	derived, err := scrypt.Key([]byte(pass), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, err
+	}
+	aesgcm, err := newGCM(derived)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aesgcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&Keystore{
+		Version:    KeystoreVersion,
+		Address:    types.EncodeAddress(GenerateAddress(pubkey.ToECDSA())),
+		KDF:        kdfScrypt,
+		KDFParams:  params,
+		Cipher:     cipherAESGCM,
+		Nonce:      hex.EncodeToString(nonce),
+		CipherText: hex.EncodeToString(aesgcm.Seal(nil, nonce, key, nil)),
+	})
+}
+
+// DecryptKeystore decrypts the private key in the keystore format with a
+// passphrase.
+func DecryptKeystore(data []byte, pass string) ([]byte, error) {
+	ks, err := parseKeystore(data)
+	if err != nil {
+		return nil, err
+	}
+	params := ks.KDFParams
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	nonce, err := hex.DecodeString(ks.Nonce)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	cipherText, err := hex.DecodeString(ks.CipherText)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	derived, err := scrypt.Key([]byte(pass), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	aesgcm, err := newGCM(derived)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	if len(nonce) != aesgcm.NonceSize() {
+		return nil, ErrInvalidKeystore
+	}
+	key, err := aesgcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, types.ErrWrongAddressOrPassWord
+	}
+	_, pubkey := btcec.PrivKeyFromBytes(btcec.S256(), key)
+	if ks.Address != types.EncodeAddress(GenerateAddress(pubkey.ToECDSA())) {
+		return nil, ErrInvalidKeystore
+	}
+	return key, nil
+}
+
+// IsKeystore reports whether data is a key in the keystore format.
+func IsKeystore(data []byte) bool {
+	_, err := parseKeystore(data)
+	return err == nil
+}
+
+func parseKeystore(data []byte) (*Keystore, error) {
+	if len(data) == 0 || data[0] != '{' {
+		return nil, ErrInvalidKeystore
+	}
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, ErrInvalidKeystore
+	}
+	if ks.Version != KeystoreVersion || ks.KDF != kdfScrypt || ks.Cipher != cipherAESGCM || ks.KDFParams == nil {
+		return nil, ErrInvalidKeystore
+	}
+	if params := ks.KDFParams; params.N > maxScryptN || params.R != scryptR || params.P > maxScryptP ||
+		params.DKLen != scryptKeyLen {
+		return nil, ErrInvalidKeystore
+	}
+	return &ks, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func keystoreKey(address Address) []byte {
+	return append(append([]byte{}, keystorePrefix...), address...)
+}
+
+// migrateKey replaces the key of the old format with the one of the keystore
+// format.
+func (ks *Store) migrateKey(address Address, pass string, key []byte, legacyKey []byte) error {
+	encrypted, err := EncryptKeystore(key, pass)
+	if err != nil {
+		return err
+	}
+	ks.storage.Set(keystoreKey(address), encrypted)
+	ks.storage.Delete(legacyKey)
+	return nil
+}
@@ -0,0 +1,104 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package key
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+var (
+	ErrNotMultisigSigner   = errors.New("key is not in the multisig key set")
+	ErrMultisigTxMismatch  = errors.New("multisig txs to combine are different")
+	ErrNotEnoughSignatures = errors.New("not enough signatures of multisig tx")
+)
+
+// SignMultisigTx adds the signature by key to the multisig tx of keys. The
+// signers sign the same hash as a single signature tx, so that the partially
+// signed txs are combined.
+func SignMultisigTx(tx *types.Tx, key *aergokey, keys *types.MultisigKeys) error {
+	index := keys.IndexOf(GenerateAddress(key.PubKey().ToECDSA()))
+	if index < 0 {
+		return ErrNotMultisigSigner
+	}
+	sign, err := key.Sign(CalculateHashWithoutSign(tx.Body))
+	if err != nil {
+		return err
+	}
+	tx.Body.AddSignature(uint32(index), sign.Serialize())
+	tx.Hash = tx.CalculateTxHash()
+	return nil
+}
+
+// CombineMultisigTxs merges the signatures of the partially signed copies of
+// a multisig tx into one tx.
+func CombineMultisigTxs(txs ...*types.Tx) (*types.Tx, error) {
+	if len(txs) == 0 {
+		return nil, ErrMultisigTxMismatch
+	}
+	combined := txs[0].Clone()
+	combined.Body.Signatures = append([]*types.MultisigSignature(nil), combined.Body.Signatures...)
+	hash := CalculateHashWithoutSign(combined.Body)
+	for _, tx := range txs[1:] {
+		if !bytes.Equal(hash, CalculateHashWithoutSign(tx.Body)) {
+			return nil, ErrMultisigTxMismatch
+		}
+		if combined.Body.Multisig == nil {
+			combined.Body.Multisig = tx.Body.Multisig
+		}
+		for _, sig := range tx.Body.Signatures {
+			combined.Body.AddSignature(sig.GetPubKeyIndex(), sig.GetSign())
+		}
+	}
+	combined.Hash = combined.CalculateTxHash()
+	return combined, nil
+}
+
+// VerifyMultisigTx checks that the multisig tx is signed by at least the
+// threshold number of keys. keys is the key set in the tx or the one stored in
+// the sender state.
+func VerifyMultisigTx(tx *types.Tx, keys *types.MultisigKeys) error {
+	txBody := tx.Body
+	if keys == nil {
+		return types.ErrMultisigKeysNotFound
+	}
+	if !bytes.Equal(keys.Address(), txBody.Account) {
+		return types.ErrInvalidMultisigKeys
+	}
+	if len(txBody.Signatures) < int(keys.Threshold) {
+		return ErrNotEnoughSignatures
+	}
+	hash := CalculateHashWithoutSign(txBody)
+	for _, sig := range txBody.Signatures {
+		if int(sig.PubKeyIndex) >= len(keys.PubKeys) {
+			return types.ErrTxInvalidMultisig
+		}
+		if err := verifySign(hash, sig.Sign, keys.PubKeys[sig.PubKeyIndex]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseMultisigKeys makes the key set of threshold from the addresses of the
+// signers, which are their compressed public keys.
+func ParseMultisigKeys(threshold uint32, signers [][]byte) (*types.MultisigKeys, error) {
+	keys := &types.MultisigKeys{Threshold: threshold}
+	for _, signer := range signers {
+		pubkey, err := btcec.ParsePubKey(signer, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		keys.PubKeys = append(keys.PubKeys, pubkey.SerializeCompressed())
+	}
+	if err := keys.Validate(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,61 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestMultisigTx(t *testing.T) {
+	var signers []*btcec.PrivateKey
+	var addrs [][]byte
+	for i := 0; i < 3; i++ {
+		signer, _ := btcec.NewPrivateKey(btcec.S256())
+		signers = append(signers, signer)
+		addrs = append(addrs, GenerateAddress(&signer.PublicKey))
+	}
+	keys, err := ParseMultisigKeys(2, addrs)
+	if err != nil {
+		t.Fatalf("could not make key set : %s", err.Error())
+	}
+
+	body := &types.TxBody{Nonce: 1, Account: keys.Address(), Recipient: addrs[0], Type: types.TxType_MULTISIG, Multisig: keys}
+	partial := make([]*types.Tx, 2)
+	for i, signer := range []*btcec.PrivateKey{signers[2], signers[0]} {
+		partial[i] = &types.Tx{Body: body}
+		partial[i] = partial[i].Clone()
+		if err := SignMultisigTx(partial[i], signer, keys); err != nil {
+			t.Errorf("could not sign : %s", err.Error())
+		}
+		if err := VerifyTx(partial[i]); err != ErrNotEnoughSignatures {
+			t.Errorf("partially signed tx is verified")
+		}
+	}
+
+	tx, err := CombineMultisigTxs(partial...)
+	if err != nil {
+		t.Fatalf("could not combine : %s", err.Error())
+	}
+	if err := VerifyTx(tx); err != nil {
+		t.Errorf("could not verify : %s", err.Error())
+	}
+	if len(partial[0].Body.Signatures) != 1 {
+		t.Errorf("combining modified the partially signed tx")
+	}
+
+	// the key set stored in the state is used if the tx omits it
+	tx.Body.Multisig = nil
+	if err := VerifyMultisigTx(tx, keys); err != nil {
+		t.Errorf("could not verify with the stored key set : %s", err.Error())
+	}
+	other, _ := ParseMultisigKeys(1, addrs)
+	if err := VerifyMultisigTx(tx, other); err != types.ErrInvalidMultisigKeys {
+		t.Errorf("tx is verified with the other key set")
+	}
+
+	outsider, _ := btcec.NewPrivateKey(btcec.S256())
+	if err := SignMultisigTx(tx, outsider, keys); err != ErrNotMultisigSigner {
+		t.Errorf("tx is signed by the key out of the key set")
+	}
+}
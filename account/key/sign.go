@@ -33,6 +33,19 @@ func SignTx(tx *types.Tx, key *aergokey) error {
 	return nil
 }
 
+// SignFeePayerTx co-signs the fee delegation tx as its fee payer. The payer
+// signs the same hash as the sender, which includes the payer address.
+func SignFeePayerTx(tx *types.Tx, key *aergokey) error {
+	hash := CalculateHashWithoutSign(tx.Body)
+	sign, err := key.Sign(hash)
+	if err != nil {
+		return err
+	}
+	tx.Body.PayerSign = sign.Serialize()
+	tx.Hash = tx.CalculateTxHash()
+	return nil
+}
+
 //SignTx return transaction which signed with unlocked key. if requester is nil, requester is assumed to tx.Account
 func (ks *Store) SignTx(tx *types.Tx, requester []byte) error {
 	addr := tx.Body.Account
@@ -46,15 +59,28 @@ func (ks *Store) SignTx(tx *types.Tx, requester []byte) error {
 	return SignTx(tx, keyPair.key)
 }
 
-//VerifyTx return result to varify sign
+//VerifyTx return result to varify sign. The multisig tx is verified with its key set
 func VerifyTx(tx *types.Tx) error {
+	if tx.Body.Type == types.TxType_MULTISIG {
+		return VerifyMultisigTx(tx, tx.Body.Multisig)
+	}
 	return VerifyTxWithAddress(tx, tx.Body.Account)
 }
 
 func VerifyTxWithAddress(tx *types.Tx, address []byte) error {
 	txBody := tx.Body
 	hash := CalculateHashWithoutSign(txBody)
-	sign, err := btcec.ParseSignature(txBody.Sign, btcec.S256())
+	if err := verifySign(hash, txBody.Sign, address); err != nil {
+		return err
+	}
+	if txBody.Type == types.TxType_FEE_DELEGATION {
+		return verifySign(hash, txBody.PayerSign, txBody.FeePayer)
+	}
+	return nil
+}
+
+func verifySign(hash []byte, rawSign []byte, address []byte) error {
+	sign, err := btcec.ParseSignature(rawSign, btcec.S256())
 	if err != nil {
 		return err
 	}
@@ -85,5 +111,11 @@ func CalculateHashWithoutSign(txBody *types.TxBody) []byte {
 	h.Write(txBody.GasPrice)
 	binary.Write(h, binary.LittleEndian, txBody.Type)
 	h.Write(txBody.ChainIdHash)
+	if txBody.HasValidityWindow() {
+		binary.Write(h, binary.LittleEndian, txBody.NotBefore)
+		binary.Write(h, binary.LittleEndian, txBody.ValidUntil)
+	}
+	h.Write(txBody.FeePayer)
+	txBody.WriteOps(h)
 	return h.Sum(nil)
 }
@@ -53,11 +53,18 @@ func (ks *Store) CreateKey(pass string) (Address, error) {
 	return ks.addKey(privkey, pass)
 }
 
-//ImportKey is to import encrypted key
+// ImportKey is to import encrypted key, which is in the keystore format or in
+// the old format of ExportLegacyKey
 func (ks *Store) ImportKey(imported []byte, oldpass string, newpass string) (Address, error) {
-	hash := hashBytes([]byte(oldpass), nil)
-	rehash := hashBytes([]byte(oldpass), hash)
-	key, err := decrypt(hash, rehash, imported)
+	var key []byte
+	var err error
+	if IsKeystore(imported) {
+		key, err = DecryptKeystore(imported, oldpass)
+	} else {
+		hash := hashBytes([]byte(oldpass), nil)
+		rehash := hashBytes([]byte(oldpass), hash)
+		key, err = decrypt(hash, rehash, imported)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +86,18 @@ func (ks *Store) ImportKey(imported []byte, oldpass string, newpass string) (Add
 	return ks.addKey(privkey, newpass)
 }
 
-//ExportKey is to export encrypted key
+// ExportKey is to export encrypted key in the keystore format
 func (ks *Store) ExportKey(addr Address, pass string) ([]byte, error) {
+	key, err := ks.getKey(addr, pass)
+	if key == nil {
+		return nil, err
+	}
+	return EncryptKeystore(key, pass)
+}
+
+// ExportLegacyKey is to export encrypted key in the old format, which is kept
+// for the compatibility with the other tools. ExportKey is preferred.
+func (ks *Store) ExportLegacyKey(addr Address, pass string) ([]byte, error) {
 	key, err := ks.getKey(addr, pass)
 	if key == nil {
 		return nil, err
@@ -88,7 +105,7 @@ func (ks *Store) ExportKey(addr Address, pass string) ([]byte, error) {
 	return EncryptKey(key, pass)
 }
 
-// EncryptKey encrypts a key with a given export for exporting
+// EncryptKey encrypts a key with a given export for exporting in the old format
 func EncryptKey(key []byte, pass string) ([]byte, error) {
 	hash := hashBytes([]byte(pass), nil)
 	rehash := hashBytes([]byte(pass), hash)
@@ -138,24 +155,36 @@ func (ks *Store) Lock(addr Address, pass string) (Address, error) {
 }
 
 func (ks *Store) getKey(address []byte, pass string) ([]byte, error) {
+	if stored := ks.storage.Get(keystoreKey(address)); cap(stored) != 0 {
+		return DecryptKeystore(stored, pass)
+	}
+
+	// the key of the old format is looked up by the passphrase
 	encryptkey := hashBytes(address, []byte(pass))
-	key := ks.storage.Get(hashBytes(address, encryptkey))
-	if cap(key) == 0 {
+	legacyKey := hashBytes(address, encryptkey)
+	stored := ks.storage.Get(legacyKey)
+	if cap(stored) == 0 {
 		return nil, types.ErrWrongAddressOrPassWord
 	}
-	return decrypt(address, encryptkey, key)
+	key, err := decrypt(address, encryptkey, stored)
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.migrateKey(address, pass, key, legacyKey); err != nil {
+		return nil, err
+	}
+	return key, nil
 }
 
 func (ks *Store) addKey(key *btcec.PrivateKey, pass string) (Address, error) {
 	//gen new address
 	address := GenerateAddress(&key.PublicKey)
-	//save pass/address/key
-	encryptkey := hashBytes(address, []byte(pass))
-	encrypted, err := encrypt(address, encryptkey, key.Serialize())
+	//save address/key
+	encrypted, err := EncryptKeystore(key.Serialize(), pass)
 	if err != nil {
 		return nil, err
 	}
-	ks.storage.Set(hashBytes(address, encryptkey), encrypted)
+	ks.storage.Set(keystoreKey(address), encrypted)
 	return address, nil
 }
 
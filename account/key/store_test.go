@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
 )
 
 var (
@@ -16,6 +17,7 @@ var (
 func initTest() {
 	testDir, _ = ioutil.TempDir("", "test")
 	ks = NewStore(testDir, 0)
+	scryptN = 1 << 12
 }
 
 func deinitTest() {
@@ -70,6 +72,13 @@ func TestExportKey(t *testing.T) {
 		if err != nil {
 			t.Errorf("could not export key : %s", err.Error())
 		}
+		if !IsKeystore(exported) {
+			t.Errorf("exported key is not in the keystore format")
+		}
+		exported, err = ks.ExportLegacyKey(addr, pass)
+		if err != nil {
+			t.Errorf("could not export key : %s", err.Error())
+		}
 		if len(exported) != 48 {
 			t.Errorf("invalid exported address : length = %d", len(exported))
 		}
@@ -107,6 +116,34 @@ func TestSignTx(t *testing.T) {
 	}
 }
 
+func TestSignFeePayerTx(t *testing.T) {
+	sender, _ := btcec.NewPrivateKey(btcec.S256())
+	payer, _ := btcec.NewPrivateKey(btcec.S256())
+	tx := &types.Tx{Body: &types.TxBody{
+		Account:  GenerateAddress(&sender.PublicKey),
+		Type:     types.TxType_FEE_DELEGATION,
+		FeePayer: GenerateAddress(&payer.PublicKey),
+	}}
+	if err := SignTx(tx, sender); err != nil {
+		t.Errorf("could not sign : %s", err.Error())
+	}
+	if err := VerifyTx(tx); err == nil {
+		t.Errorf("tx without the sign of fee payer is verified")
+	}
+	if err := SignFeePayerTx(tx, payer); err != nil {
+		t.Errorf("could not sign as fee payer : %s", err.Error())
+	}
+	if err := VerifyTx(tx); err != nil {
+		t.Errorf("could not verify : %s", err.Error())
+	}
+
+	// both of the signs cover the fee payer address
+	tx.Body.FeePayer = GenerateAddress(&sender.PublicKey)
+	if err := VerifyTx(tx); err != types.ErrSignNotMatch {
+		t.Errorf("tx of the other fee payer is verified")
+	}
+}
+
 func TestSign(t *testing.T) {
 	initTest()
 	defer deinitTest()
@@ -127,3 +164,84 @@ func TestSign(t *testing.T) {
 		}
 	}
 }
+
+func TestMigrateKey(t *testing.T) {
+	initTest()
+	defer deinitTest()
+	const pass = "pass"
+	privkey, _ := btcec.NewPrivateKey(btcec.S256())
+	addr := GenerateAddress(&privkey.PublicKey)
+
+	// store the key in the old format
+	encryptkey := hashBytes(addr, []byte(pass))
+	encrypted, err := encrypt(addr, encryptkey, privkey.Serialize())
+	if err != nil {
+		t.Fatalf("could not encrypt key : %s", err.Error())
+	}
+	legacyKey := hashBytes(addr, encryptkey)
+	ks.storage.Set(legacyKey, encrypted)
+
+	if _, err := ks.Unlock(addr, "wrong"); err != types.ErrWrongAddressOrPassWord {
+		t.Errorf("unlocked with wrong password : %v", err)
+	}
+	if _, err := ks.Unlock(addr, pass); err != nil {
+		t.Errorf("could not unlock old key : %s", err.Error())
+	}
+	if ks.storage.Exist(legacyKey) {
+		t.Errorf("old key is not removed")
+	}
+	if !IsKeystore(ks.storage.Get(keystoreKey(addr))) {
+		t.Errorf("old key is not migrated")
+	}
+	if _, err := ks.Unlock(addr, pass); err != nil {
+		t.Errorf("could not unlock migrated key : %s", err.Error())
+	}
+	if _, err := ks.Unlock(addr, "wrong"); err != types.ErrWrongAddressOrPassWord {
+		t.Errorf("unlocked migrated key with wrong password : %v", err)
+	}
+}
+
+func TestImportKeystore(t *testing.T) {
+	initTest()
+	defer deinitTest()
+	addr, err := ks.CreateKey("old")
+	if err != nil {
+		t.Fatalf("could not create key : %s", err.Error())
+	}
+	exported, err := ks.ExportKey(addr, "old")
+	if err != nil {
+		t.Fatalf("could not export key : %s", err.Error())
+	}
+	exportedAgain, _ := ks.ExportKey(addr, "old")
+	if string(exported) == string(exportedAgain) {
+		t.Errorf("salt and nonce are reused")
+	}
+
+	otherDir, _ := ioutil.TempDir("", "test")
+	other := NewStore(otherDir, 0)
+	defer other.CloseStore()
+	if _, err := other.ImportKey(exported, "wrong", "new"); err != types.ErrWrongAddressOrPassWord {
+		t.Errorf("imported with wrong password : %v", err)
+	}
+	imported, err := other.ImportKey(exported, "old", "new")
+	if err != nil {
+		t.Fatalf("could not import key : %s", err.Error())
+	}
+	if string(imported) != string(addr) {
+		t.Errorf("different address imported")
+	}
+	if _, err := other.Unlock(imported, "new"); err != nil {
+		t.Errorf("could not unlock imported key : %s", err.Error())
+	}
+
+	// the old export format is still accepted
+	legacy, err := ks.ExportLegacyKey(addr, "old")
+	if err != nil {
+		t.Fatalf("could not export key : %s", err.Error())
+	}
+	legacyStore := NewStore(otherDir+"/legacy", 0)
+	defer legacyStore.CloseStore()
+	if _, err := legacyStore.ImportKey(legacy, "old", "new"); err != nil {
+		t.Errorf("could not import old format : %s", err.Error())
+	}
+}
@@ -0,0 +1,246 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/contract/name"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+)
+
+// The account tx index maps an account to the txs of the main chain which
+// are sent by or sent to the account. It is kept only if it is enabled by
+// the configuration, since it doubles the writes of the tx mapping.
+//
+//	accountTxIndexPrefix + address + position => block hash
+//
+// The position is the block number and the tx index in big endian. The
+// accounts of a tx are the sender, the recipient or the deployed contract, the
+// recipients of the batch operations and the fee payer, whose names are
+// resolved by the state after the block. As the event index, the entries of a
+// rolled back block are deleted with the block hash check. The keys of a block
+// are kept to delete them, since the names may be resolved differently later.
+//
+//	accountTxBlockPrefix + block hash => index keys of the block
+const (
+	accountTxPosLength = 12
+
+	MaxAccountTxListLimit = 1000
+)
+
+var (
+	accountTxIndexPrefix = []byte("h")
+	accountTxBlockPrefix = []byte(chainDBName + ".accountTxs.")
+
+	ErrAccountTxIndexDisabled = errors.New("account tx index is not enabled")
+	ErrInvalidAccountTxCursor = errors.New("invalid account tx cursor")
+)
+
+func accountTxPos(blockNo types.BlockNo, txIdx int) []byte {
+	pos := make([]byte, accountTxPosLength)
+	binary.BigEndian.PutUint64(pos, blockNo)
+	binary.BigEndian.PutUint32(pos[8:], uint32(txIdx))
+	return pos
+}
+
+func accountTxKey(addr []byte) []byte {
+	var key bytes.Buffer
+	key.Write(accountTxIndexPrefix)
+	key.Write(eventIndexAddress(addr))
+	return key.Bytes()
+}
+
+func accountTxBlockKey(blockHash []byte) []byte {
+	return append(append([]byte(nil), accountTxBlockPrefix...), blockHash...)
+}
+
+// accountTxKeys returns the index keys of the accounts of tx. The names are
+// resolved by bs unless it is nil.
+func accountTxKeys(bs *state.BlockState, tx *types.Tx, pos []byte) [][]byte {
+	resolve := func(account []byte) []byte {
+		if bs == nil {
+			return account
+		}
+		return name.Resolve(bs, account)
+	}
+
+	body := tx.GetBody()
+	sender := resolve(body.GetAccount())
+	accounts := [][]byte{sender}
+	if len(body.GetRecipient()) != 0 {
+		accounts = append(accounts, resolve(body.GetRecipient()))
+	} else if body.GetType() != types.TxType_BATCH {
+		accounts = append(accounts, contract.CreateContractID(body.GetAccount(), body.GetNonce()))
+	}
+	for _, op := range body.GetOps() {
+		accounts = append(accounts, resolve(op.GetRecipient()))
+	}
+	if len(body.GetFeePayer()) != 0 {
+		accounts = append(accounts, resolve(body.GetFeePayer()))
+	}
+
+	var keys [][]byte
+	for i, account := range accounts {
+		dup := len(account) == 0
+		for _, prev := range accounts[:i] {
+			dup = dup || bytes.Equal(account, prev)
+		}
+		if !dup {
+			keys = append(keys, append(accountTxKey(account), pos...))
+		}
+	}
+	return keys
+}
+
+func encodeAccountTxKeys(keys [][]byte) []byte {
+	var data []byte
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, key := range keys {
+		n := binary.PutUvarint(buf, uint64(len(key)))
+		data = append(append(data, buf[:n]...), key...)
+	}
+	return data
+}
+
+func decodeAccountTxKeys(data []byte) [][]byte {
+	var keys [][]byte
+	for len(data) != 0 {
+		l, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < l {
+			break
+		}
+		keys = append(keys, data[n:n+int(l)])
+		data = data[n+int(l):]
+	}
+	return keys
+}
+
+// EnableAccountTxIndex makes the chain DB maintain the account tx index for
+// the blocks connected from now on.
+func (cdb *ChainDB) EnableAccountTxIndex() {
+	cdb.accountTxIndex = true
+}
+
+// addAccountTxs adds the txs of a main chain block to the account tx index.
+// The names of the accounts are resolved by the state of sdb after the block,
+// unless sdb is nil.
+func (cdb *ChainDB) addAccountTxs(dbTx *db.Transaction, block *types.Block, sdb *state.ChainStateDB) {
+	if !cdb.accountTxIndex {
+		return
+	}
+	var bs *state.BlockState
+	if sdb != nil {
+		bs = state.NewBlockState(sdb.OpenNewStateDB(block.GetHeader().GetBlocksRootHash()))
+	}
+
+	var blockKeys [][]byte
+	for i, tx := range block.GetBody().GetTxs() {
+		for _, key := range accountTxKeys(bs, tx, accountTxPos(block.BlockNo(), i)) {
+			(*dbTx).Set(key, block.BlockHash())
+			blockKeys = append(blockKeys, key)
+		}
+	}
+	(*dbTx).Set(accountTxBlockKey(block.BlockHash()), encodeAccountTxKeys(blockKeys))
+}
+
+// deleteAccountTxs removes the txs of a block from the account tx index. The
+// entries which belong to the other block at the same height are kept. The
+// keys of a block indexed without the key list are derived from its txs.
+func (cdb *ChainDB) deleteAccountTxs(dbTx *db.Transaction, block *types.Block) {
+	blockKey := accountTxBlockKey(block.BlockHash())
+	keys := decodeAccountTxKeys(cdb.store.Get(blockKey))
+	if keys == nil {
+		for i, tx := range block.GetBody().GetTxs() {
+			keys = append(keys, accountTxKeys(nil, tx, accountTxPos(block.BlockNo(), i))...)
+		}
+	}
+	for _, key := range keys {
+		if bytes.Equal(cdb.store.Get(key), block.BlockHash()) {
+			(*dbTx).Delete(key)
+		}
+	}
+	(*dbTx).Delete(blockKey)
+}
+
+// listAccountTxs returns the txs of addr from the account tx index. The txs are
+// returned after cursor, which is the position of the last tx of the previous
+// page. The cursor of the next page is returned if there are more than limit
+// txs.
+func (cdb *ChainDB) listAccountTxs(addr []byte, cursor []byte, limit int, desc bool) ([]*types.TxInBlock, []byte, error) {
+	if !cdb.accountTxIndex {
+		return nil, nil, ErrAccountTxIndexDisabled
+	}
+	if len(cursor) != 0 && len(cursor) != accountTxPosLength {
+		return nil, nil, ErrInvalidAccountTxCursor
+	}
+	if limit <= 0 || limit > MaxAccountTxListLimit {
+		limit = MaxAccountTxListLimit
+	}
+
+	prefix := accountTxKey(addr)
+	lower := append(append([]byte(nil), prefix...), accountTxPos(0, 0)...)
+	upper := append(append([]byte(nil), prefix...), accountTxPos(cdb.getBestBlockNo()+1, 0)...)
+	if len(cursor) != 0 {
+		if desc {
+			upper = append(append([]byte(nil), prefix...), cursor...)
+		} else {
+			lower = append(append([]byte(nil), prefix...), nextEventPos(cursor)...)
+		}
+	}
+	if bytes.Compare(lower, upper) >= 0 {
+		return []*types.TxInBlock{}, nil, nil
+	}
+
+	var iter db.Iterator
+	if desc {
+		// the iterator moves in the reverse order if start > end
+		iter = cdb.store.Iterator(upper, lower)
+	} else {
+		iter = cdb.store.Iterator(lower, upper)
+	}
+
+	txs := []*types.TxInBlock{}
+	var lastPos []byte
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if len(key) != len(prefix)+accountTxPosLength || bytes.Compare(key, lower) < 0 || bytes.Compare(key, upper) >= 0 {
+			continue
+		}
+		pos := key[len(prefix):]
+		blockNo := types.BlockNo(binary.BigEndian.Uint64(pos))
+		txIdx := int32(binary.BigEndian.Uint32(pos[8:]))
+
+		blockHash := iter.Value()
+		if mainHash, err := cdb.getHashByNo(blockNo); err != nil || !bytes.Equal(mainHash, blockHash) {
+			continue
+		}
+		block, err := cdb.getBlock(blockHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		blockTxs := block.GetBody().GetTxs()
+		if txIdx >= int32(len(blockTxs)) {
+			continue
+		}
+
+		if len(txs) == limit {
+			return txs, lastPos, nil
+		}
+		txs = append(txs, &types.TxInBlock{
+			TxIdx: &types.TxIdx{BlockHash: blockHash, Idx: txIdx},
+			Tx:    blockTxs[txIdx],
+		})
+		lastPos = append([]byte(nil), pos...)
+	}
+
+	return txs, nil, nil
+}
@@ -0,0 +1,125 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+package chain
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAccountTxBlock(t *testing.T, cdb *ChainDB, prev *types.Block, from, to []byte) *types.Block {
+	var txs []*types.Tx
+	for i := 0; i < 2; i++ {
+		tx := &types.Tx{Body: &types.TxBody{
+			Nonce:     prev.BlockNo()*2 + uint64(i),
+			Account:   from,
+			Recipient: to,
+		}}
+		tx.Hash = tx.CalculateTxHash()
+		txs = append(txs, tx)
+	}
+
+	block := types.NewBlock(prev, nil, nil, txs, nil, prev.GetHeader().GetTimestamp()+1)
+	dbTx := cdb.store.NewTx()
+	cdb.connectToChain(&dbTx, block, false)
+	assert.NoError(t, cdb.addTxsOfBlock(&dbTx, txs, block.BlockHash(), block.BlockNo()))
+	cdb.addAccountTxs(&dbTx, block, nil)
+	dbTx.Commit()
+
+	return block
+}
+
+func TestAccountTxIndex(t *testing.T) {
+	tmpdir, _ := ioutil.TempDir("", "accounttxindex")
+	defer os.RemoveAll(tmpdir)
+
+	cdb := NewChainDB()
+	assert.NoError(t, cdb.Init(string(db.BadgerImpl), tmpdir))
+	defer cdb.Close()
+	assert.NoError(t, cdb.addGenesisBlock(types.GetTestGenesis()))
+
+	_, _, err := cdb.listAccountTxs([]byte("sender"), nil, 0, false)
+	assert.Equal(t, ErrAccountTxIndexDisabled, err)
+	cdb.EnableAccountTxIndex()
+
+	sender := append([]byte{0x02}, common.Hasher([]byte("sender"))...)
+	recipient := append([]byte{0x02}, common.Hasher([]byte("recipient"))...)
+	prev, err := cdb.GetBlockByNo(0)
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		prev = newTestAccountTxBlock(t, cdb, prev, sender, recipient)
+	}
+	prev = newTestAccountTxBlock(t, cdb, prev, recipient, recipient)
+
+	txs, cursor, err := cdb.listAccountTxs(sender, nil, 0, false)
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+	assert.Equal(t, 10, len(txs))
+	txs, _, err = cdb.listAccountTxs(recipient, nil, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, len(txs))
+
+	// pagination in the descending order
+	var paged []*types.TxInBlock
+	for {
+		txs, cursor, err = cdb.listAccountTxs(sender, cursor, 3, true)
+		assert.NoError(t, err)
+		paged = append(paged, txs...)
+		if cursor == nil {
+			break
+		}
+	}
+	assert.Equal(t, 10, len(paged))
+	assert.Equal(t, uint64(9), paged[0].Tx.GetBody().GetNonce())
+	assert.Equal(t, uint64(0), paged[9].Tx.GetBody().GetNonce())
+
+	_, _, err = cdb.listAccountTxs(sender, []byte{1}, 0, false)
+	assert.Equal(t, ErrInvalidAccountTxCursor, err)
+
+	// the txs of a dropped block are deleted
+	assert.NoError(t, cdb.dropBlock(prev.BlockNo()))
+	txs, _, err = cdb.listAccountTxs(recipient, nil, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, len(txs))
+}
+
+func TestAccountTxKeys(t *testing.T) {
+	sender := append([]byte{0x02}, common.Hasher([]byte("sender"))...)
+	recipient := append([]byte{0x02}, common.Hasher([]byte("recipient"))...)
+	payer := append([]byte{0x02}, common.Hasher([]byte("payer"))...)
+	pos := accountTxPos(1, 0)
+
+	// the deployed contract is indexed for the tx without the recipient
+	deploy := &types.Tx{Body: &types.TxBody{Nonce: 1, Account: sender}}
+	keys := accountTxKeys(nil, deploy, pos)
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, append(accountTxKey(contract.CreateContractID(sender, 1)), pos...), keys[1])
+
+	// the batch operations and the fee payer are indexed once per account
+	batch := &types.Tx{Body: &types.TxBody{
+		Account:  sender,
+		Type:     types.TxType_BATCH,
+		FeePayer: payer,
+		Ops: []*types.BatchOp{
+			{Recipient: recipient},
+			{Recipient: recipient},
+			{Recipient: sender},
+		},
+	}}
+	keys = accountTxKeys(nil, batch, pos)
+	assert.Equal(t, [][]byte{
+		append(accountTxKey(sender), pos...),
+		append(accountTxKey(recipient), pos...),
+		append(accountTxKey(payer), pos...),
+	}, keys)
+	assert.Equal(t, keys, decodeAccountTxKeys(encodeAccountTxKeys(keys)))
+}
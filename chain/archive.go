@@ -0,0 +1,276 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/gogo/protobuf/proto"
+)
+
+// A chain archive file starts with ArchiveMagic, and is followed by the
+// records of types.ArchiveHeader and types.ArchiveBlock. Each record is a
+// marshaled protobuf message prefixed by its length in uvarint.
+const (
+	ArchiveMagic   = "AERGOARC"
+	ArchiveVersion = 1
+
+	maxArchiveRecordSize = 1024 * 1024 * 256
+)
+
+var (
+	ErrArchiveInvalidMagic    = errors.New("archive: not a chain archive file")
+	ErrArchiveInvalidVersion  = errors.New("archive: unsupported archive version")
+	ErrArchiveRecordTooBig    = errors.New("archive: record is too big")
+	ErrArchiveInvalidRecord   = errors.New("archive: invalid block record")
+	ErrArchiveInvalidRange    = errors.New("archive: invalid block range")
+	ErrArchiveGenesisMismatch = errors.New("archive: genesis block is not matched")
+	ErrArchiveNotConnected    = errors.New("archive: imported block is not connected to the chain")
+	ErrArchiveReceiptMismatch = errors.New("archive: receipts of the imported block are not matched")
+)
+
+// ArchiveWriter writes the blocks to a chain archive file.
+type ArchiveWriter struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+// NewArchiveWriter writes the magic and the header to w, and returns the
+// writer for the blocks.
+func NewArchiveWriter(w io.Writer, header *types.ArchiveHeader) (*ArchiveWriter, error) {
+	aw := &ArchiveWriter{w: bufio.NewWriter(w), buf: make([]byte, binary.MaxVarintLen64)}
+	if _, err := aw.w.WriteString(ArchiveMagic); err != nil {
+		return nil, err
+	}
+	if err := aw.writeRecord(header); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write appends a block and its receipts to the archive.
+func (aw *ArchiveWriter) Write(block *types.ArchiveBlock) error {
+	return aw.writeRecord(block)
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (aw *ArchiveWriter) Flush() error {
+	return aw.w.Flush()
+}
+
+func (aw *ArchiveWriter) writeRecord(pb proto.Message) error {
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		return err
+	}
+	n := binary.PutUvarint(aw.buf, uint64(len(data)))
+	if _, err := aw.w.Write(aw.buf[:n]); err != nil {
+		return err
+	}
+	_, err = aw.w.Write(data)
+	return err
+}
+
+// ArchiveReader reads the blocks from a chain archive file.
+type ArchiveReader struct {
+	r      *bufio.Reader
+	header *types.ArchiveHeader
+}
+
+// NewArchiveReader checks the magic and reads the header from r.
+func NewArchiveReader(r io.Reader) (*ArchiveReader, error) {
+	ar := &ArchiveReader{r: bufio.NewReader(r)}
+
+	magic := make([]byte, len(ArchiveMagic))
+	if _, err := io.ReadFull(ar.r, magic); err != nil || string(magic) != ArchiveMagic {
+		return nil, ErrArchiveInvalidMagic
+	}
+
+	header := &types.ArchiveHeader{}
+	if err := ar.readRecord(header); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if header.Version != ArchiveVersion {
+		return nil, ErrArchiveInvalidVersion
+	}
+	if header.From > header.To {
+		return nil, ErrArchiveInvalidRange
+	}
+	ar.header = header
+
+	return ar, nil
+}
+
+// Header returns the header of the archive.
+func (ar *ArchiveReader) Header() *types.ArchiveHeader {
+	return ar.header
+}
+
+// Next returns the next block of the archive. It returns io.EOF if there is
+// no more block.
+func (ar *ArchiveReader) Next() (*types.ArchiveBlock, error) {
+	block := &types.ArchiveBlock{}
+	if err := ar.readRecord(block); err != nil {
+		return nil, err
+	}
+	if block.Block == nil || block.Block.GetHeader() == nil {
+		return nil, ErrArchiveInvalidRecord
+	}
+	return block, nil
+}
+
+func (ar *ArchiveReader) readRecord(pb proto.Message) error {
+	size, err := binary.ReadUvarint(ar.r)
+	if err != nil {
+		return err
+	}
+	if size > maxArchiveRecordSize {
+		return ErrArchiveRecordTooBig
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(ar.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return proto.Unmarshal(data, pb)
+}
+
+// ExportArchive writes the blocks from from to to, with their receipts, to w.
+// to is set to the best block number if it is 0.
+func (core *Core) ExportArchive(w io.Writer, from, to types.BlockNo) (*types.ArchiveHeader, error) {
+	bestNo := core.cdb.getBestBlockNo()
+	if to == 0 || to > bestNo {
+		to = bestNo
+	}
+	if from == 0 || from > to {
+		return nil, ErrArchiveInvalidRange
+	}
+
+	genesis, err := core.cdb.GetBlockByNo(0)
+	if err != nil {
+		return nil, err
+	}
+	header := &types.ArchiveHeader{
+		Version:     ArchiveVersion,
+		Genesis:     core.cdb.Get([]byte(genesisKey)),
+		GenesisHash: genesis.BlockHash(),
+		From:        from,
+		To:          to,
+	}
+
+	aw, err := NewArchiveWriter(w, header)
+	if err != nil {
+		return nil, err
+	}
+	for no := from; no <= to; no++ {
+		block, err := core.cdb.GetBlockByNo(no)
+		if err != nil {
+			return nil, err
+		}
+		receipts, err := core.getReceiptsBinary(block)
+		if err != nil {
+			return nil, err
+		}
+		if err := aw.Write(&types.ArchiveBlock{Block: block, Receipts: receipts}); err != nil {
+			return nil, err
+		}
+	}
+	if err := aw.Flush(); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// InitArchiveGenesis prepares an empty chain for the import of the archive.
+// The genesis block is created from the archive header if the chain has no
+// genesis block.
+func (core *Core) InitArchiveGenesis(header *types.ArchiveHeader) error {
+	if core.GetGenesisInfo() == nil {
+		genesis := types.GetGenesisFromBytes(header.Genesis)
+		if genesis == nil {
+			return ErrArchiveGenesisMismatch
+		}
+		if _, err := core.initGenesis(genesis, false, false); err != nil {
+			return err
+		}
+	}
+
+	genesis, err := core.cdb.GetBlockByNo(0)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(genesis.BlockHash(), header.GenesisHash) {
+		return ErrArchiveGenesisMismatch
+	}
+	if core.cdb.getBestBlockNo()+1 != header.From {
+		return ErrArchiveInvalidRange
+	}
+	return nil
+}
+
+// PrepareArchiveBlock checks that the block from the archive is connected to
+// the best block, and keeps it until the next one. The chain executes the
+// kept block, and rejects it before it is committed if the receipts made by
+// the execution are different from the archived ones.
+func (core *Core) PrepareArchiveBlock(block *types.ArchiveBlock) error {
+	if !block.Block.ValidHash() {
+		return ErrArchiveInvalidRecord
+	}
+	best, err := core.cdb.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	if block.Block.BlockNo() != best.BlockNo()+1 ||
+		!bytes.Equal(block.Block.GetHeader().GetPrevBlockHash(), best.BlockHash()) {
+		return ErrArchiveNotConnected
+	}
+
+	core.archiveLock.Lock()
+	core.pendingArchive = block
+	core.archiveLock.Unlock()
+	return nil
+}
+
+// checkArchiveReceipts compares the receipts made by the execution of block
+// with the archived ones, if block is the archive block being imported and
+// the archive has its receipts.
+func (core *Core) checkArchiveReceipts(block *types.Block, receipts *types.Receipts) error {
+	core.archiveLock.Lock()
+	pending := core.pendingArchive
+	core.archiveLock.Unlock()
+
+	if pending == nil || len(pending.Receipts) == 0 || !bytes.Equal(pending.Block.BlockHash(), block.BlockHash()) {
+		return nil
+	}
+	data, err := receipts.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, pending.Receipts) {
+		return ErrArchiveReceiptMismatch
+	}
+	return nil
+}
+
+func (core *Core) getReceiptsBinary(block *types.Block) ([]byte, error) {
+	receipts, err := core.cdb.getReceipts(block.BlockHash(), block.BlockNo())
+	if err != nil {
+		// a block which is not executed by this node has no receipts
+		return nil, nil
+	}
+	return receipts.MarshalBinary()
+}
@@ -0,0 +1,87 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+package chain
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveReadWrite(t *testing.T) {
+	genesis := types.GetTestGenesis()
+	header := &types.ArchiveHeader{
+		Version:     ArchiveVersion,
+		Genesis:     genesis.Bytes(),
+		GenesisHash: genesis.Block().BlockHash(),
+		From:        1,
+		To:          3,
+	}
+
+	var buf bytes.Buffer
+	aw, err := NewArchiveWriter(&buf, header)
+	assert.NoError(t, err)
+	prev := genesis.Block()
+	for i := 0; i < 3; i++ {
+		block := types.NewBlock(prev, nil, nil, nil, nil, prev.GetHeader().GetTimestamp()+1)
+		assert.NoError(t, aw.Write(&types.ArchiveBlock{Block: block, Receipts: []byte{byte(i)}}))
+		prev = block
+	}
+	assert.NoError(t, aw.Flush())
+	data := buf.Bytes()
+
+	ar, err := NewArchiveReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, header.GenesisHash, ar.Header().GenesisHash)
+	assert.NotNil(t, types.GetGenesisFromBytes(ar.Header().Genesis))
+	for i := 0; i < 3; i++ {
+		block, err := ar.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, types.BlockNo(i+1), block.Block.BlockNo())
+		assert.Equal(t, []byte{byte(i)}, block.Receipts)
+	}
+	_, err = ar.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// truncated
+	ar, err = NewArchiveReader(bytes.NewReader(data[:len(data)-1]))
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = ar.Next()
+		assert.NoError(t, err)
+	}
+	_, err = ar.Next()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	_, err = NewArchiveReader(bytes.NewReader(data[1:]))
+	assert.Equal(t, ErrArchiveInvalidMagic, err)
+}
+
+func TestArchiveReceiptsCheck(t *testing.T) {
+	genesis := types.GetTestGenesis().Block()
+	block := types.NewBlock(genesis, nil, nil, nil, nil, genesis.GetHeader().GetTimestamp()+1)
+
+	receipts := &types.Receipts{}
+	receipts.Set([]*types.Receipt{types.NewReceipt([]byte("contract"), "SUCCESS", "")})
+	archived, err := receipts.MarshalBinary()
+	assert.NoError(t, err)
+
+	core := &Core{}
+	assert.NoError(t, core.checkArchiveReceipts(block, receipts), "no archive block")
+
+	core.pendingArchive = &types.ArchiveBlock{Block: block, Receipts: archived}
+	assert.NoError(t, core.checkArchiveReceipts(block, receipts))
+
+	other := &types.Receipts{}
+	other.Set([]*types.Receipt{types.NewReceipt([]byte("contract"), "ERROR", "")})
+	assert.Equal(t, ErrArchiveReceiptMismatch, core.checkArchiveReceipts(block, other))
+
+	// the other blocks are not checked
+	next := types.NewBlock(block, nil, nil, nil, nil, block.GetHeader().GetTimestamp()+1)
+	assert.NoError(t, core.checkArchiveReceipts(next, other))
+}
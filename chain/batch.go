@@ -0,0 +1,129 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package chain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/contract/name"
+	"github.com/aergoio/aergo/fee"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+)
+
+// batchOpError is the error of an operation of a batch tx. It is a runtime
+// error, so that the batch tx is included as failed with the fee charged.
+type batchOpError struct {
+	index int
+	err   error
+}
+
+func (e *batchOpError) Error() string {
+	return fmt.Sprintf("batch operation %d: %s", e.index, e.err.Error())
+}
+
+func (e *batchOpError) Runtime() bool {
+	return e != nil
+}
+
+// executeBatchTx executes the operations of the batch tx in order. If an
+// operation fails, the changes of all the operations to the state db and the
+// contract databases are rolled back, and the results until the failed one
+// are returned with the error. An operation fails by a runtime error only;
+// the other errors drop the batch tx as they drop a single tx.
+func executeBatchTx(cdb contract.ChainAccessor, bs *state.BlockState, tx *types.Tx, blockNo uint64, ts int64,
+	prevBlockHash []byte, sender *state.V, preLoadService int) (results []*types.BatchOpResult, events []*types.Event,
+	txFee *big.Int, gasUsed uint64, err error) {
+
+	txBody := tx.GetBody()
+	txFee = new(big.Int)
+
+	snapshot := bs.Snapshot()
+	storages := bs.SnapshotStorages()
+	contract.BeginBatch(preLoadService)
+	defer func() {
+		commit := err == nil
+		if !commit {
+			events = nil
+			if rErr := bs.Rollback(snapshot); rErr != nil {
+				err = rErr
+			} else if rErr = bs.RollbackStorages(storages); rErr != nil {
+				err = rErr
+			}
+		}
+		if bErr := contract.EndBatch(preLoadService, commit); bErr != nil {
+			err = bErr
+		}
+	}()
+
+	isGasMetered := fee.IsGasMetered(txBody.GetGasLimit())
+	for i := range txBody.Ops {
+		opTx := &types.Tx{Hash: tx.GetHash(), Body: txBody.OpBody(i)}
+		if isGasMetered {
+			// the operations share the gas limit of the batch tx
+			if opTx.Body.Type == types.TxType_NORMAL &&
+				gasUsed+fee.TxGas(len(opTx.Body.Payload)) > txBody.GasLimit {
+				results = append(results, &types.BatchOpResult{Status: "ERROR", Ret: types.ErrTxGasLimitTooLow.Error()})
+				return results, events, txFee, gasUsed, &batchOpError{i, types.ErrTxGasLimitTooLow}
+			}
+			opTx.Body.GasLimit = txBody.GasLimit - gasUsed
+		}
+
+		rv, opEvents, opFee, opGas, opErr := executeBatchOp(cdb, bs, opTx, blockNo, ts, prevBlockHash, sender, preLoadService)
+		if opFee != nil {
+			txFee.Add(txFee, opFee)
+		}
+		gasUsed += opGas
+		if opErr != nil {
+			if !contract.IsRuntimeError(opErr) {
+				return results, events, txFee, gasUsed, opErr
+			}
+			results = append(results, &types.BatchOpResult{Status: "ERROR", Ret: opErr.Error(), GasUsed: opGas})
+			return results, events, txFee, gasUsed, &batchOpError{i, opErr}
+		}
+		results = append(results, &types.BatchOpResult{Status: "SUCCESS", Ret: adjustRv(rv), GasUsed: opGas})
+		events = append(events, opEvents...)
+	}
+	return results, events, txFee, gasUsed, nil
+}
+
+// executeBatchOp executes an operation of a batch tx as a tx of the sender.
+// The states are put after each operation, so that the next one sees the
+// changes.
+func executeBatchOp(cdb contract.ChainAccessor, bs *state.BlockState, opTx *types.Tx, blockNo uint64, ts int64,
+	prevBlockHash []byte, sender *state.V, preLoadService int) (rv string, events []*types.Event, opFee *big.Int,
+	gasUsed uint64, err error) {
+
+	opBody := opTx.GetBody()
+	receiver := sender
+	if recipient := name.Resolve(bs, opBody.Recipient); types.ToAccountID(recipient) != sender.AccountID() {
+		if receiver, err = bs.GetAccountStateV(recipient); err != nil {
+			return
+		}
+	}
+
+	switch opBody.Type {
+	case types.TxType_NORMAL:
+		rv, events, opFee, gasUsed, err = contract.Execute(bs, cdb, opTx, blockNo, ts, prevBlockHash, sender, receiver, preLoadService)
+	case types.TxType_GOVERNANCE:
+		events, err = executeGovernanceTx(bs, opBody, sender, receiver, blockNo)
+	default:
+		err = types.ErrTxInvalidBatch
+	}
+	if err != nil {
+		return
+	}
+
+	if err = sender.PutState(); err != nil {
+		return
+	}
+	if receiver != sender {
+		err = receiver.PutState()
+	}
+	return
+}
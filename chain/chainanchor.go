@@ -31,6 +31,11 @@ func (cs *ChainService) getAnchorsNew() (ChainAnchor, types.BlockNo, error) {
 LOOP:
 	for i := 0; i < cnt; i++ {
 		blockHash, err := cs.getHashByNo(blkNo)
+		if err != nil && len(anchors) > 0 {
+			// the blocks before the snapshot installed by the snapshot sync
+			// are missing
+			break LOOP
+		}
 		if err != nil {
 			logger.Info().Msg("assertion - hash get failed")
 			// assertion!
@@ -66,6 +71,10 @@ func (cs *ChainService) getAnchorsFromHash(blockHash []byte) ChainAnchor {
 	latestNo := cs.getBestBlockNo()
 	for i := 0; i < 10; i++ {
 		blockHash, err := cs.getHashByNo(latestNo)
+		if err != nil && len(anchors) > 1 {
+			// the blocks before the snapshot are missing
+			return anchors
+		}
 		if err != nil {
 			logger.Info().Msg("assertion - hash get failed")
 			// assertion!
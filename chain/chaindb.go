@@ -36,9 +36,11 @@ var (
 	ErrTooBigResetHeight   = errors.New("reset height is too big")
 	ErrInvalidHardState    = errors.New("invalid hard state")
 	ErrInvalidRaftSnapshot = errors.New("invalid raft snapshot")
+	ErrNotVerifiedContract = errors.New("the source of the contract is not verified")
 
-	latestKey      = []byte(chainDBName + ".latest")
-	receiptsPrefix = []byte("r")
+	latestKey            = []byte(chainDBName + ".latest")
+	receiptsPrefix       = []byte("r")
+	contractSourcePrefix = []byte(chainDBName + ".source.")
 
 	raftIdentityKey     = []byte("r_identity")
 	raftStateKey        = []byte("r_state")
@@ -72,6 +74,8 @@ type ChainDB struct {
 	bestBlock atomic.Value // *types.Block
 	//	blocks []*types.Block
 	store db.DB
+
+	accountTxIndex bool
 }
 
 func NewChainDB() *ChainDB {
@@ -106,6 +110,8 @@ func (cdb *ChainDB) Init(dbType string, dataDir string) error {
 		return err
 	}
 
+	cdb.initEventIndex()
+
 	// // if empty then create new genesis block
 	// // if cdb.getBestBlockNo() == 0 && len(cdb.blocks) == 0 {
 	// blockIdx := types.BlockNoToBytes(0)
@@ -452,7 +458,7 @@ type txInfo struct {
 	idx       int
 }
 
-func (cdb *ChainDB) addTxsOfBlock(dbTx *db.Transaction, txs []*types.Tx, blockHash []byte) error {
+func (cdb *ChainDB) addTxsOfBlock(dbTx *db.Transaction, txs []*types.Tx, blockHash []byte, blockNo types.BlockNo) error {
 	for i, txEntry := range txs {
 		if err := cdb.addTx(dbTx, txEntry, blockHash, i); err != nil {
 			logger.Error().Err(err).Str("hash", enc.ToString(blockHash)).Int("txidx", i).
@@ -532,8 +538,10 @@ func (cdb *ChainDB) dropBlock(dropNo types.BlockNo) error {
 	for _, tx := range dropBlock.GetBody().GetTxs() {
 		cdb.deleteTx(&dbTx, tx)
 	}
+	cdb.deleteAccountTxs(&dbTx, dropBlock)
 
-	// remove receipt
+	// remove receipt and its events
+	cdb.deleteEventIndex(&dbTx, dropBlock.BlockHash(), dropBlock.BlockNo())
 	cdb.deleteReceipts(&dbTx, dropBlock.BlockHash(), dropBlock.BlockNo())
 
 	// remove (hash/block)
@@ -689,17 +697,12 @@ func (cdb *ChainDB) GetChainTree() ([]byte, error) {
 	return jsonBytes, nil
 }
 
-func (cdb *ChainDB) writeReceipts(blockHash []byte, blockNo types.BlockNo, receipts *types.Receipts) {
-	dbTx := cdb.store.NewTx()
-	defer dbTx.Discard()
-
+func setReceipts(dbTx *db.Transaction, blockHash []byte, blockNo types.BlockNo, receipts *types.Receipts) {
 	var val bytes.Buffer
 	gob := gob.NewEncoder(&val)
 	gob.Encode(receipts)
 
-	dbTx.Set(receiptsKey(blockHash, blockNo), val.Bytes())
-
-	dbTx.Commit()
+	(*dbTx).Set(receiptsKey(blockHash, blockNo), val.Bytes())
 }
 
 func (cdb *ChainDB) deleteReceipts(dbTx *db.Transaction, blockHash []byte, blockNo types.BlockNo) {
@@ -716,6 +719,44 @@ func receiptsKey(blockHash []byte, blockNo types.BlockNo) []byte {
 	return key.Bytes()
 }
 
+// writeContractSource stores the verified source of the contract. It is kept
+// per code hash, so that the source of the redeployed code is verified again.
+func (cdb *ChainDB) writeContractSource(src *types.ContractSource) error {
+	val, err := proto.Marshal(src)
+	if err != nil {
+		return err
+	}
+	dbTx := cdb.store.NewTx()
+	defer dbTx.Discard()
+
+	dbTx.Set(contractSourceKey(src.ContractAddress, src.CodeHash), val)
+
+	dbTx.Commit()
+	return nil
+}
+
+// getContractSource returns the verified source of the code of the contract,
+// or nil if the source isn't verified.
+func (cdb *ChainDB) getContractSource(address, codeHash []byte) (*types.ContractSource, error) {
+	data := cdb.store.Get(contractSourceKey(address, codeHash))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	src := &types.ContractSource{}
+	if err := proto.Unmarshal(data, src); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+func contractSourceKey(address, codeHash []byte) []byte {
+	var key bytes.Buffer
+	key.Write(contractSourcePrefix)
+	key.Write(address)
+	key.Write(codeHash)
+	return key.Bytes()
+}
+
 func (cdb *ChainDB) writeReorgMarker(marker *ReorgMarker) error {
 	dbTx := cdb.store.NewTx()
 	defer dbTx.Discard()
@@ -0,0 +1,49 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+package chain
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractSource(t *testing.T) {
+	tmpdir, _ := ioutil.TempDir("", "contractsource")
+	defer os.RemoveAll(tmpdir)
+
+	cdb := NewChainDB()
+	assert.NoError(t, cdb.Init(string(db.BadgerImpl), tmpdir))
+	defer cdb.Close()
+
+	address := append([]byte{0x02}, common.Hasher([]byte("contract"))...)
+	codeHash := common.Hasher([]byte("code"))
+	src, err := cdb.getContractSource(address, codeHash)
+	assert.NoError(t, err)
+	assert.Nil(t, src)
+
+	assert.NoError(t, cdb.writeContractSource(&types.ContractSource{
+		ContractAddress: address,
+		CodeHash:        codeHash,
+		Source:          "function hello() end abi.register(hello)",
+		CompilerVersion: "v1.0.0",
+		BlockNo:         10,
+	}))
+	src, err = cdb.getContractSource(address, codeHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "function hello() end abi.register(hello)", src.GetSource())
+	assert.Equal(t, "v1.0.0", src.GetCompilerVersion())
+	assert.Equal(t, uint64(10), src.GetBlockNo())
+
+	// the source of the redeployed code isn't verified
+	src, err = cdb.getContractSource(address, common.Hasher([]byte("new code")))
+	assert.NoError(t, err)
+	assert.Nil(t, src)
+}
@@ -8,14 +8,17 @@ package chain
 import (
 	"bytes"
 	"container/list"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/aergoio/aergo/consensus"
 	"github.com/aergoio/aergo/contract"
 	"github.com/aergoio/aergo/contract/name"
+	"github.com/aergoio/aergo/fee"
 	"github.com/aergoio/aergo/internal/common"
 	"github.com/aergoio/aergo/internal/enc"
 	"github.com/aergoio/aergo/message"
@@ -163,7 +166,7 @@ func (cs *ChainService) getEvents(events *[]*types.Event, blkNo types.BlockNo, f
 
 const MaxEventSize = 4 * 1024 * 1024
 
-func (cs *ChainService) listEvents(filter *types.FilterInfo) ([]*types.Event, error) {
+func (cs *ChainService) listEvents(filter *types.FilterInfo) ([]*types.Event, []byte, error) {
 	from := filter.Blockfrom
 	to := filter.Blockto
 
@@ -179,13 +182,36 @@ func (cs *ChainService) listEvents(filter *types.FilterInfo) ([]*types.Event, er
 			to = cs.cdb.getBestBlockNo()
 		}
 	}
+	limit := int(filter.Limit)
+	if limit < 0 || limit > MaxEventListLimit {
+		limit = MaxEventListLimit
+	}
+
+	// the range which is covered by the event index has no limit
+	if start, exist := cs.cdb.eventIndexStart(); exist && to >= start {
+		if err := filter.ValidateAddress(); err != nil {
+			return nil, nil, err
+		}
+		argFilter, err := filter.GetExArgFilter()
+		if err != nil {
+			return nil, nil, err
+		}
+		if from >= start {
+			return cs.cdb.listIndexedEvents(filter, argFilter, from, to, filter.Cursor, limit)
+		}
+		return cs.listEventsAcrossIndex(filter, argFilter, from, start, to, limit)
+	}
+	if len(filter.Cursor) != 0 {
+		return nil, nil, ErrInvalidEventCursor
+	}
+
 	err := filter.ValidateCheck(to)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	argFilter, err := filter.GetExArgFilter()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	events := []*types.Event{}
 	var totalSize uint64
@@ -193,18 +219,104 @@ func (cs *ChainService) listEvents(filter *types.FilterInfo) ([]*types.Event, er
 		for i := to; i >= from && i != 0; i-- {
 			totalSize += cs.getEvents(&events, types.BlockNo(i), filter, argFilter)
 			if totalSize > MaxEventSize {
-				return nil, errors.New(fmt.Sprintf("too large size of event (%v)", totalSize))
+				return nil, nil, errors.New(fmt.Sprintf("too large size of event (%v)", totalSize))
 			}
 		}
 	} else {
 		for i := from; i <= to; i++ {
 			totalSize += cs.getEvents(&events, types.BlockNo(i), filter, argFilter)
 			if totalSize > MaxEventSize {
-				return nil, errors.New(fmt.Sprintf("too large size of event (%v)", totalSize))
+				return nil, nil, errors.New(fmt.Sprintf("too large size of event (%v)", totalSize))
 			}
 		}
 	}
-	return events, nil
+	return events, nil, nil
+}
+
+// listEventsAcrossIndex lists the events of the blocks from..to, which are
+// found by the event index from start and by the receipts below start. Only
+// the blocks below start are limited by MAXBLOCKRANGE.
+func (cs *ChainService) listEventsAcrossIndex(filter *types.FilterInfo, argFilter []types.ArgFilter,
+	from, start, to types.BlockNo, limit int) ([]*types.Event, []byte, error) {
+	cursor := filter.Cursor
+	if len(cursor) != 0 && len(cursor) != eventPosLength {
+		return nil, nil, ErrInvalidEventCursor
+	}
+	belowStart := len(cursor) != 0 && binary.BigEndian.Uint64(cursor) < start
+
+	// the indexed events come first in the descending order
+	events := []*types.Event{}
+	if filter.Desc && !belowStart {
+		indexed, next, err := cs.cdb.listIndexedEvents(filter, argFilter, start, to, cursor, limit)
+		if err != nil || next != nil {
+			return indexed, next, err
+		}
+		events, cursor = indexed, nil
+	} else if !filter.Desc && len(cursor) != 0 && !belowStart {
+		return cs.cdb.listIndexedEvents(filter, argFilter, start, to, cursor, limit)
+	}
+
+	if from+types.MAXBLOCKRANGE < start-1 {
+		return nil, nil, fmt.Errorf("too large block range(max %d) from %d to %d below the event index",
+			types.MAXBLOCKRANGE, from, start-1)
+	}
+	var scanned []*types.Event
+	var totalSize uint64
+	for i := from; i < start; i++ {
+		no := i
+		if filter.Desc {
+			no = start - 1 - (i - from)
+		}
+		totalSize += cs.getEvents(&scanned, no, filter, argFilter)
+		if totalSize > MaxEventSize {
+			return nil, nil, errors.New(fmt.Sprintf("too large size of event (%v)", totalSize))
+		}
+	}
+	for _, ev := range scanned {
+		pos := eventPos(ev.BlockNo, ev.TxIndex, ev.EventIdx)
+		if len(cursor) != 0 {
+			if cmp := bytes.Compare(pos, cursor); cmp == 0 || (cmp < 0) != filter.Desc {
+				continue
+			}
+		}
+		if limit > 0 && len(events) == limit {
+			return events, lastEventPos(events), nil
+		}
+		events = append(events, ev)
+	}
+	if filter.Desc {
+		return events, nil, nil
+	}
+
+	// the indexed events follow in the ascending order
+	if limit > 0 && len(events) == limit {
+		more, _, err := cs.cdb.listIndexedEvents(filter, argFilter, start, to, nil, 1)
+		if err != nil || len(more) == 0 {
+			return events, nil, err
+		}
+		return events, lastEventPos(events), nil
+	}
+	rest := 0
+	if limit > 0 {
+		rest = limit - len(events)
+	}
+	indexed, next, err := cs.cdb.listIndexedEvents(filter, argFilter, start, to, nil, rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(events, indexed...), next, nil
+}
+
+func lastEventPos(events []*types.Event) []byte {
+	last := events[len(events)-1]
+	return eventPos(last.BlockNo, last.TxIndex, last.EventIdx)
+}
+
+func (cs *ChainService) listAccountTxs(addr []byte, cursor []byte, limit uint32, desc bool) ([]*types.TxInBlock, []byte, error) {
+	if len(addr) == 0 {
+		return nil, nil, errors.New("address is empty")
+	}
+	return cs.cdb.listAccountTxs(addr, cursor, int(limit), desc)
 }
 
 type chainProcessor struct {
@@ -344,9 +456,10 @@ func (cp *chainProcessor) connectToChain(block *types.Block) (types.BlockNo, err
 
 	// skip to add hash/block if wal of block is already written
 	oldLatest := cp.cdb.connectToChain(&dbTx, block, cp.isByBP && cp.HasWAL())
-	if err := cp.cdb.addTxsOfBlock(&dbTx, block.GetBody().GetTxs(), block.BlockHash()); err != nil {
+	if err := cp.cdb.addTxsOfBlock(&dbTx, block.GetBody().GetTxs(), block.BlockHash(), block.BlockNo()); err != nil {
 		return 0, err
 	}
+	cp.cdb.addAccountTxs(&dbTx, block, cp.sdb)
 
 	dbTx.Commit()
 
@@ -451,6 +564,8 @@ func (cs *ChainService) addBlockInternal(newBlock *types.Block, usedBstate *stat
 
 	logger.Info().Uint64("best", cs.cdb.getBestBlockNo()).Msg("Block added successfully")
 
+	cs.pruneState()
+
 	return nil, true
 }
 
@@ -566,7 +681,10 @@ func newBlockExecutor(cs *ChainService, bState *state.BlockState, block *types.B
 		txs:              block.GetBody().GetTxs(),
 		coinbaseAcccount: block.GetHeader().GetCoinbaseAccount(),
 		validatePost: func() error {
-			return cs.validator.ValidatePost(bState.GetRoot(), bState.Receipts(), block)
+			if err := cs.validator.ValidatePost(bState.GetRoot(), bState.Receipts(), block); err != nil {
+				return err
+			}
+			return cs.checkArchiveReceipts(block, bState.Receipts())
 		},
 		commitOnly:       commitOnly,
 		validateSignWait: validateSignWait,
@@ -691,7 +809,9 @@ func (cs *ChainService) executeBlock(bstate *state.BlockState, block *types.Bloc
 	}
 
 	if len(ex.BlockState.Receipts().Get()) != 0 {
-		cs.cdb.writeReceipts(block.BlockHash(), block.BlockNo(), ex.BlockState.Receipts())
+		if err := cs.cdb.writeReceiptsAndEvents(block.BlockHash(), block.BlockNo(), ex.BlockState.Receipts()); err != nil {
+			return err
+		}
 	}
 
 	cs.notifyEvents(block, ex.BlockState)
@@ -797,6 +917,9 @@ func executeTx(cdb contract.ChainAccessor, bs *state.BlockState, tx types.Transa
 	if err != nil {
 		return err
 	}
+	if err = txBody.ValidateWindow(blockNo); err != nil {
+		return err
+	}
 
 	sender, err := bs.GetAccountStateV(account)
 	if err != nil {
@@ -807,6 +930,9 @@ func executeTx(cdb contract.ChainAccessor, bs *state.BlockState, tx types.Transa
 	if err != nil {
 		return err
 	}
+	if txBody.Type == types.TxType_MULTISIG && txBody.Multisig == nil && sender.State().GetMultisig() == nil {
+		return types.ErrMultisigKeysNotFound
+	}
 
 	recipient := name.Resolve(bs, txBody.Recipient)
 	var receiver *state.V
@@ -814,6 +940,10 @@ func executeTx(cdb contract.ChainAccessor, bs *state.BlockState, tx types.Transa
 	if len(recipient) > 0 {
 		receiver, err = bs.GetAccountStateV(recipient)
 		status = "SUCCESS"
+	} else if txBody.Type == types.TxType_BATCH {
+		// the operations have their own recipients
+		receiver = sender
+		status = "SUCCESS"
 	} else {
 		receiver, err = bs.CreateAccountStateV(contract.CreateContractID(txBody.Account, txBody.Nonce))
 		status = "CREATED"
@@ -822,19 +952,50 @@ func executeTx(cdb contract.ChainAccessor, bs *state.BlockState, tx types.Transa
 		return err
 	}
 
+	// the fee payer pays the fee instead of the sender, which is still the
+	// caller of the contract
+	payer := sender
+	var reservedFee *big.Int
+	if txBody.Type == types.TxType_FEE_DELEGATION {
+		if payer, err = feePayerState(bs, txBody.FeePayer, sender, receiver); err != nil {
+			return err
+		}
+		if err = tx.ValidateWithFeePayerState(payer.State()); err != nil {
+			return err
+		}
+		// the max fee is reserved during the execution, so that the contract
+		// paying the fee can't spend it
+		reservedFee = tx.GetMaxFee()
+		payer.SubBalance(reservedFee)
+	}
+
 	var txFee *big.Int
+	var gasUsed uint64
 	var rv string
 	var events []*types.Event
+	var opResults []*types.BatchOpResult
 	switch txBody.Type {
-	case types.TxType_NORMAL:
-		rv, events, txFee, err = contract.Execute(bs, cdb, tx.GetTx(), blockNo, ts, prevBlockHash, sender, receiver, preLoadService)
-		sender.SubBalance(txFee)
+	case types.TxType_NORMAL, types.TxType_FEE_DELEGATION, types.TxType_MULTISIG, types.TxType_REDEPLOY:
+		rv, events, txFee, gasUsed, err = contract.Execute(bs, cdb, tx.GetTx(), blockNo, ts, prevBlockHash, sender, receiver, preLoadService)
 	case types.TxType_GOVERNANCE:
 		txFee = new(big.Int).SetUint64(0)
 		events, err = executeGovernanceTx(bs, txBody, sender, receiver, blockNo)
 		if err != nil {
 			logger.Warn().Err(err).Str("txhash", enc.ToString(tx.GetHash())).Msg("governance tx Error")
 		}
+	case types.TxType_BATCH:
+		opResults, events, txFee, gasUsed, err = executeBatchTx(cdb, bs, tx.GetTx(), blockNo, ts, prevBlockHash, sender, preLoadService)
+	}
+	if isFeeCapped(txBody) {
+		if maxFee := tx.GetMaxFee(); txFee.Cmp(maxFee) > 0 {
+			txFee = maxFee
+		}
+		if gasUsed > txBody.GetGasLimit() && fee.IsGasMetered(txBody.GetGasLimit()) {
+			gasUsed = txBody.GetGasLimit()
+		}
+	}
+	if reservedFee != nil {
+		payer.AddBalance(reservedFee)
 	}
 
 	if err != nil {
@@ -842,15 +1003,26 @@ func executeTx(cdb contract.ChainAccessor, bs *state.BlockState, tx types.Transa
 			return err
 		}
 		sender.Reset()
-		sender.SubBalance(txFee)
+		if payer != sender {
+			payer.Reset()
+		}
+		payer.SubBalance(txFee)
+		registerMultisig(sender, txBody)
 		sender.SetNonce(txBody.Nonce)
 		sErr := sender.PutState()
 		if sErr != nil {
 			return sErr
 		}
+		if payer != sender {
+			if sErr = payer.PutState(); sErr != nil {
+				return sErr
+			}
+		}
 		status = "ERROR"
 		rv = err.Error()
 	} else {
+		payer.SubBalance(txFee)
+		registerMultisig(sender, txBody)
 		sender.SetNonce(txBody.Nonce)
 		err = sender.PutState()
 		if err != nil {
@@ -862,18 +1034,108 @@ func executeTx(cdb contract.ChainAccessor, bs *state.BlockState, tx types.Transa
 				return err
 			}
 		}
+		if payer != sender && payer != receiver {
+			if err = payer.PutState(); err != nil {
+				return err
+			}
+		}
 		rv = adjustRv(rv)
 	}
 	bs.BpReward = new(big.Int).Add(new(big.Int).SetBytes(bs.BpReward), txFee).Bytes()
 
 	receipt := types.NewReceipt(receiver.ID(), status, rv)
+	if payer != sender {
+		receipt.FeePayer = payer.ID()
+	}
 	receipt.FeeUsed = txFee.Bytes()
+	receipt.GasUsed = gasUsed
 	receipt.TxHash = tx.GetHash()
 	receipt.Events = events
+	receipt.OpResults = opResults
 
 	return bs.AddReceipt(receipt)
 }
 
+// isFeeCapped reports whether the fee of the tx is limited to its max fee,
+// which the payer is checked for. The fee of the legacy txs isn't capped to
+// keep the results of the existing blocks.
+func isFeeCapped(txBody *types.TxBody) bool {
+	return fee.IsGasMetered(txBody.GetGasLimit()) || txBody.GetType() == types.TxType_FEE_DELEGATION
+}
+
+// registerMultisig stores the key set of the first multisig tx of the sender
+// into its state, so that the later txs may omit it.
+func registerMultisig(sender *state.V, txBody *types.TxBody) {
+	if txBody.Type == types.TxType_MULTISIG && sender.State().GetMultisig() == nil {
+		sender.State().Multisig = txBody.Multisig
+	}
+}
+
+// feePayerState returns the state of the fee payer. The state of the receiver
+// is shared if the receiver pays the fee, so that both changes are kept. The
+// sender can't be the payer even by its name.
+func feePayerState(bs *state.BlockState, feePayer []byte, sender, receiver *state.V) (*state.V, error) {
+	switch types.ToAccountID(feePayer) {
+	case sender.AccountID():
+		return nil, types.ErrTxInvalidFeePayer
+	case receiver.AccountID():
+		return receiver, nil
+	}
+	return bs.GetAccountStateV(feePayer)
+}
+
+// simulateTx executes tx on top of the best block without committing the
+// result and returns the receipt which the tx would produce. The nonce and
+// the chain id hash are filled in when they are omitted, and the signature is
+// not checked, so an unsigned tx can be simulated as well.
+func (cs *ChainService) simulateTx(tx *types.Tx) (*types.Receipt, error) {
+	if tx.GetBody() == nil {
+		return nil, types.ErrTxFormatInvalid
+	}
+	bestBlock, err := cs.cdb.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	header := bestBlock.GetHeader()
+	chainIDHash := common.Hasher(header.GetChainID())
+
+	bs := state.NewBlockState(cs.sdb.OpenNewStateDB(cs.sdb.GetRoot()))
+
+	simTx := proto.Clone(tx).(*types.Tx)
+	txBody := simTx.GetBody()
+	modified := false
+	if len(txBody.GetChainIdHash()) == 0 {
+		txBody.ChainIdHash = chainIDHash
+		modified = true
+	}
+	if txBody.GetNonce() == 0 {
+		sender, err := bs.GetAccountState(types.ToAccountID(name.Resolve(bs, txBody.GetAccount())))
+		if err != nil {
+			return nil, err
+		}
+		txBody.Nonce = sender.GetNonce() + 1
+		modified = true
+	}
+	if modified || len(simTx.GetHash()) == 0 {
+		simTx.Hash = simTx.CalculateTxHash()
+	}
+
+	exec := NewTxExecutor(cs.cdb, header.GetBlockNo()+1, time.Now().UnixNano(), bestBlock.BlockHash(),
+		contract.Simulator, header.GetChainID())
+	if err := exec(bs, types.NewTransaction(simTx)); err != nil {
+		return nil, err
+	}
+	receipts := bs.Receipts().Get()
+	if len(receipts) == 0 {
+		return nil, errors.New("no receipt is produced")
+	}
+	r := receipts[0]
+	r.ContractAddress = types.AddressOrigin(r.ContractAddress)
+	r.From = txBody.GetAccount()
+	r.To = txBody.GetRecipient()
+	return r, nil
+}
+
 func SendRewardCoinbase(bState *state.BlockState, coinbaseAccount []byte) error {
 	bpReward := new(big.Int).SetBytes(bState.BpReward)
 	if bpReward.Cmp(new(big.Int).SetUint64(0)) <= 0 || coinbaseAccount == nil {
@@ -5,6 +5,7 @@
 package chain
 
 import (
+	"encoding/binary"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"github.com/aergoio/aergo-lib/db"
 	"github.com/aergoio/aergo/account/key"
 	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/fee"
+	"github.com/aergoio/aergo/pkg/luac"
 	"github.com/aergoio/aergo/state"
 	"github.com/aergoio/aergo/types"
 	"github.com/stretchr/testify/assert"
@@ -122,3 +125,157 @@ func TestBasicExecuteTx(t *testing.T) {
 	assert.NoError(t, err, "execute governance type")
 
 }
+
+func TestBatchExecuteTx(t *testing.T) {
+	initTest(t, true)
+	defer deinitTest()
+	bs := state.NewBlockState(sdb.GetStateDB())
+
+	sender := makeTestAddress(t)
+	recipient1 := makeTestAddress(t)
+	recipient2 := makeTestAddress(t)
+	balanceOf := func(addr []byte) *big.Int {
+		st, err := bs.GetAccountState(types.ToAccountID(addr))
+		assert.NoError(t, err)
+		return st.GetBalanceBigInt()
+	}
+
+	tx := &types.Tx{Body: &types.TxBody{
+		Nonce:       1,
+		Account:     sender,
+		Type:        types.TxType_BATCH,
+		ChainIdHash: chainID,
+		Ops: []*types.BatchOp{
+			{Recipient: recipient1, Amount: new(big.Int).SetUint64(1000).Bytes()},
+			{Recipient: recipient2, Amount: new(big.Int).SetUint64(2000).Bytes()},
+		},
+	}}
+	signTestAddress(t, tx)
+	err := executeTx(nil, bs, types.NewTransaction(tx), 0, 0, nil, contract.ChainService, chainID)
+	assert.NoError(t, err, "execute batch")
+	assert.Equal(t, int64(1000), balanceOf(recipient1).Int64())
+	assert.Equal(t, int64(2000), balanceOf(recipient2).Int64())
+	receipts := bs.Receipts().Get()
+	assert.Equal(t, "SUCCESS", receipts[0].Status)
+	assert.Equal(t, 2, len(receipts[0].OpResults))
+	assert.Equal(t, "SUCCESS", receipts[0].OpResults[1].Status)
+
+	// the transfer of the first operation is rolled back by the second one
+	tx.Body.Nonce = 2
+	tx.Body.Ops[1].Amount = types.MaxAER.Bytes()
+	signTestAddress(t, tx)
+	err = executeTx(nil, bs, types.NewTransaction(tx), 0, 0, nil, contract.ChainService, chainID)
+	assert.EqualError(t, err, types.ErrInsufficientBalance.Error(), "execute batch over balance")
+	assert.Equal(t, int64(1000), balanceOf(recipient1).Int64())
+}
+
+func makeTestDeployPayload(t *testing.T, source string) []byte {
+	L := luac.NewLState()
+	code, err := luac.Compile(L, source)
+	luac.CloseLState(L)
+	assert.NoError(t, err)
+	payload := make([]byte, 4+len(code))
+	binary.LittleEndian.PutUint32(payload, uint32(len(payload)))
+	copy(payload[4:], code)
+	return payload
+}
+
+const simulateTestCode = `
+function constructor()
+	db.exec("create table kv(k text primary key, v integer)")
+	db.exec("insert into kv values('a', 1)")
+end
+
+function set(k, v)
+	db.exec("insert or replace into kv values(?, ?)", k, v)
+end
+
+function get(k)
+	local rs = db.query("select v from kv where k = ?", k)
+	if rs:next() then
+		return rs:get()
+	end
+	return nil
+end
+
+abi.register(set)
+abi.register_view(get)`
+
+func TestSimulateTx(t *testing.T) {
+	initTest(t, true)
+	defer deinitTest()
+	tmpdir, _ := ioutil.TempDir("", "simulate")
+	defer os.RemoveAll(tmpdir)
+
+	cdb := NewChainDB()
+	assert.NoError(t, cdb.Init(string(db.BadgerImpl), tmpdir))
+	defer cdb.Close()
+	assert.NoError(t, cdb.addGenesisBlock(types.GetTestGenesis()))
+	assert.NoError(t, contract.LoadDatabase(tmpdir))
+	defer contract.CloseDatabase()
+	contract.StartLStateFactory()
+	cs := &ChainService{Core: &Core{cdb: cdb, sdb: sdb}}
+
+	sender := makeTestAddress(t)
+	recipient := makeTestAddress(t)
+
+	payload := makeTestDeployPayload(t, simulateTestCode)
+
+	// deploy the contract, whose database has 'a' = 1
+	bs := state.NewBlockState(sdb.OpenNewStateDB(sdb.GetRoot()))
+	tx := &types.Tx{Body: &types.TxBody{Nonce: 1, Account: sender, Payload: payload, ChainIdHash: chainID}}
+	signTestAddress(t, tx)
+	assert.NoError(t, executeTx(cdb, bs, types.NewTransaction(tx), 1, 0, nil, contract.ChainService, chainID))
+	ctrAddress := contract.CreateContractID(sender, 1)
+	assert.Equal(t, "CREATED", bs.Receipts().Get()[0].Status)
+	assert.NoError(t, contract.SaveRecoveryPoint(bs))
+	assert.NoError(t, sdb.Apply(bs))
+	root := sdb.GetRoot()
+
+	queryA := func() string {
+		ctrState, err := sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(ctrAddress))
+		assert.NoError(t, err)
+		rv, err := contract.Query(ctrAddress, state.NewBlockState(sdb.GetStateDB()), cdb, ctrState,
+			[]byte(`{"Name":"get","Args":["a"]}`))
+		assert.NoError(t, err)
+		return string(rv)
+	}
+
+	// transfer
+	receipt, err := cs.simulateTx(&types.Tx{Body: &types.TxBody{
+		Account: sender, Recipient: recipient, Amount: new(big.Int).SetUint64(1000).Bytes()}})
+	assert.NoError(t, err)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	recipientState, err := sdb.GetStateDB().GetState(types.ToAccountID(recipient))
+	assert.NoError(t, err)
+	assert.Nil(t, recipientState)
+
+	// contract call
+	callPayload := []byte(`{"Name":"set","Args":["a", 2]}`)
+	receipt, err = cs.simulateTx(&types.Tx{Body: &types.TxBody{
+		Account: sender, Recipient: ctrAddress, Payload: callPayload}})
+	assert.NoError(t, err)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, "1", queryA())
+
+	// contract deploy
+	receipt, err = cs.simulateTx(&types.Tx{Body: &types.TxBody{Account: sender, Payload: payload}})
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATED", receipt.Status)
+
+	// out of gas
+	gasLimit := fee.TxGas(len(callPayload)) + 1
+	receipt, err = cs.simulateTx(&types.Tx{Body: &types.TxBody{
+		Account: sender, Recipient: ctrAddress, Payload: callPayload,
+		GasLimit: gasLimit, GasPrice: fee.MinGasPrice().Bytes()}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ERROR", receipt.Status)
+	assert.Equal(t, gasLimit, receipt.GasUsed)
+
+	// the best state is unchanged
+	assert.Equal(t, root, sdb.GetRoot())
+	senderState, err := sdb.GetStateDB().GetAccountState(types.ToAccountID(sender))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), senderState.GetNonce())
+	assert.Equal(t, "1", queryA())
+}
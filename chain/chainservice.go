@@ -6,12 +6,15 @@
 package chain
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
 	"runtime"
+	"sync"
 	"sync/atomic"
 
 	"github.com/aergoio/aergo-actor/actor"
@@ -40,6 +43,8 @@ var (
 	ErrNotSupportedConsensus = errors.New("not supported by this consensus")
 	ErrRecoNoBestStateRoot   = errors.New("state root of best block is not exist")
 	ErrRecoInvalidSdbRoot    = errors.New("state root of sdb is invalid")
+	ErrInvalidBlockParam     = errors.New("block should be a 32 byte hash or an 8 byte number")
+	ErrHistoricalSQLQuery    = errors.New("sql contract can not be queried at a past block")
 
 	debugger *Debugger
 )
@@ -48,6 +53,9 @@ var (
 type Core struct {
 	cdb *ChainDB
 	sdb *state.ChainStateDB
+
+	archiveLock    sync.Mutex
+	pendingArchive *types.ArchiveBlock
 }
 
 // NewCore returns an instance of Core.
@@ -186,7 +194,14 @@ type IChainHandler interface {
 	getAnchorsNew() (ChainAnchor, types.BlockNo, error)
 	findAncestor(Hashes [][]byte) (*types.BlockInfo, error)
 	setSync(val bool)
-	listEvents(filter *types.FilterInfo) ([]*types.Event, error)
+	listEvents(filter *types.FilterInfo) ([]*types.Event, []byte, error)
+	listAccountTxs(addr []byte, cursor []byte, limit uint32, desc bool) ([]*types.TxInBlock, []byte, error)
+	simulateTx(tx *types.Tx) (*types.Receipt, error)
+	getStateRange(root, start []byte, size int) ([][]byte, [][]byte, bool, error)
+	getContractCodes(hashes [][]byte) [][]byte
+	importStateRange(root []byte, keys, values [][]byte, done bool) error
+	importContractCodes(codes [][]byte) error
+	installSnapshot(block *types.Block) error
 }
 
 // ChainService manage connectivity of blocks
@@ -203,6 +218,8 @@ type ChainService struct {
 
 	chainWorker  *ChainWorker
 	chainManager *ChainManager
+	pruner       *statePruner
+	snapshot     *state.Snapshot
 
 	stat stats
 
@@ -235,6 +252,17 @@ func NewChainService(cfg *cfg.Config) *ChainService {
 		panic("invalid config: blockchain")
 	}
 
+	if cs.pruner, err = newStatePruner(cfg.Blockchain.StateMode, cfg.Blockchain.StateRetention); err != nil {
+		logger.Error().Err(err).Msg("failed to init state pruner")
+		panic("invalid config: blockchain")
+	}
+	if cs.pruner != nil {
+		cs.sdb.EnablePruning()
+	}
+	if cfg.Blockchain.AccountTxIndex {
+		cs.cdb.EnableAccountTxIndex()
+	}
+
 	cs.validator = NewBlockValidator(cs, cs.sdb)
 	cs.BaseComponent = component.NewBaseComponent(message.ChainSvc, cs, logger)
 	cs.chainManager = newChainManager(cs, cs.Core)
@@ -378,7 +406,11 @@ func (cs *ChainService) Receive(context actor.Context) {
 	switch msg := context.Message().(type) {
 	case *message.AddBlock,
 		*message.GetAnchors, //TODO move to ChainWorker (need chain lock)
-		*message.GetAncestor:
+		*message.GetAncestor,
+		*message.ImportStateRange,
+		*message.ImportContractCode,
+		*message.InstallSnapshot,
+		*message.SimulateTx: // the simulation writes to the sql databases, which the block execution uses
 		cs.chainManager.Request(msg, context.Sender())
 
 		//pass to chainWorker
@@ -389,13 +421,19 @@ func (cs *ChainService) Receive(context actor.Context) {
 		*message.GetTx,
 		*message.GetReceipt,
 		*message.GetABI,
+		*message.GetCodeVersions,
+		*message.VerifyContract,
+		*message.GetContractSource,
 		*message.GetQuery,
 		*message.GetStateQuery,
 		*message.GetElected,
 		*message.GetVote,
 		*message.GetStaking,
 		*message.GetNameInfo,
-		*message.ListEvents:
+		*message.ListEvents,
+		*message.ListAccountTxs,
+		*message.GetStateRange,
+		*message.GetContractCode:
 		cs.chainWorker.Request(msg, context.Sender())
 
 		//handle directly
@@ -598,6 +636,20 @@ func (cm *ChainManager) Receive(context actor.Context) {
 			Ancestor: ancestor,
 			Err:      err,
 		})
+	case *message.ImportStateRange:
+		err := cm.importStateRange(msg.Root, msg.Keys, msg.Values, msg.Done)
+		context.Respond(message.ImportStateRangeRsp{Err: err})
+	case *message.ImportContractCode:
+		err := cm.importContractCodes(msg.Codes)
+		context.Respond(message.ImportContractCodeRsp{Err: err})
+	case *message.InstallSnapshot:
+		err := cm.installSnapshot(msg.Block)
+		context.Respond(message.InstallSnapshotRsp{Err: err})
+	case *message.SimulateTx:
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		receipt, err := cm.simulateTx(msg.Tx)
+		context.Respond(message.SimulateTxRsp{Receipt: receipt, Err: err})
 	case *actor.Started, *actor.Stopping, *actor.Stopped, *component.CompStatReq: // donothing
 	default:
 		debug := fmt.Sprintf("[%s] Missed message. (%v) %s", cm.name, reflect.TypeOf(msg), msg)
@@ -605,9 +657,9 @@ func (cm *ChainManager) Receive(context actor.Context) {
 	}
 }
 
-func getAddressNameResolved(sdb *state.ChainStateDB, account []byte) ([]byte, error) {
+func getAddressNameResolved(sdb *state.StateDB, account []byte) ([]byte, error) {
 	if len(account) <= types.NameLength {
-		scs, err := sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID([]byte(types.AergoName)))
+		scs, err := sdb.OpenContractStateAccount(types.ToAccountID([]byte(types.AergoName)))
 		if err != nil {
 			logger.Error().Str("hash", enc.ToString(account)).Err(err).Msg("failed to get state for account")
 			return nil, err
@@ -617,6 +669,91 @@ func getAddressNameResolved(sdb *state.ChainStateDB, account []byte) ([]byte, er
 	return account, nil
 }
 
+// getStateRoot returns the state root of the block given by a block hash or
+// an 8 byte little endian block number. It returns nil, which means the
+// latest state, if block is empty.
+func (cw *ChainWorker) getStateRoot(block []byte) ([]byte, error) {
+	var b *types.Block
+	var err error
+	switch len(block) {
+	case 0:
+		return nil, nil
+	case types.HashIDLength:
+		b, err = cw.getBlock(block)
+	case 8:
+		b, err = cw.getBlockByNo(types.BlockNo(binary.LittleEndian.Uint64(block)))
+	default:
+		return nil, ErrInvalidBlockParam
+	}
+	if err != nil {
+		return nil, err
+	}
+	root := b.GetHeader().GetBlocksRootHash()
+	if len(root) > 0 && !cw.sdb.GetStateDB().HasMarker(root) {
+		return nil, ErrStatePruned
+	}
+	return root, nil
+}
+
+// openStateDB returns the state db at the block given by block, or the latest
+// one if block is empty.
+func (cw *ChainWorker) openStateDB(block []byte) (*state.StateDB, error) {
+	root, err := cw.getStateRoot(block)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return cw.sdb.GetStateDB(), nil
+	}
+	return cw.sdb.OpenNewStateDB(root), nil
+}
+
+// getState returns the resolved address and the state of account at the block
+// given by block, or at the best block if block is empty.
+func (cw *ChainWorker) getState(account []byte, block []byte) ([]byte, *types.State, error) {
+	sdb, err := cw.openStateDB(block)
+	if err != nil {
+		return account, nil, err
+	}
+	address, err := getAddressNameResolved(sdb, account)
+	if err != nil {
+		return account, nil, err
+	}
+	accState, err := sdb.GetAccountState(types.ToAccountID(address))
+	if err != nil {
+		logger.Error().Str("hash", enc.ToString(address)).Err(err).Msg("failed to get state for account")
+	}
+	return address, accState, err
+}
+
+// queryContract runs the query of queryinfo on the contract ctr at the block
+// given by block, or at the best block if block is empty.
+func (cw *ChainWorker) queryContract(ctr []byte, queryinfo []byte, block []byte) ([]byte, error) {
+	root, err := cw.getStateRoot(block)
+	if err != nil {
+		return nil, err
+	}
+	// the database of a sql contract has the latest data only
+	historical := root != nil && !bytes.Equal(root, cw.sdb.GetRoot())
+	if root == nil {
+		root = cw.sdb.GetRoot()
+	}
+	sdb := cw.sdb.OpenNewStateDB(root)
+	address, err := getAddressNameResolved(sdb, ctr)
+	if err != nil {
+		return nil, err
+	}
+	ctrState, err := sdb.OpenContractStateAccount(types.ToAccountID(address))
+	if err != nil {
+		logger.Error().Str("hash", enc.ToString(address)).Err(err).Msg("failed to get state for contract")
+		return nil, err
+	}
+	if historical && ctrState.SqlRecoveryPoint > 0 {
+		return nil, ErrHistoricalSQLQuery
+	}
+	return contract.Query(address, state.NewBlockState(sdb), cw.cdb, ctrState, queryinfo)
+}
+
 func (cw *ChainWorker) Receive(context actor.Context) {
 	switch msg := context.Message().(type) {
 	case *message.GetBlock:
@@ -639,27 +776,26 @@ func (cw *ChainWorker) Receive(context actor.Context) {
 			Err:   err,
 		})
 	case *message.GetState:
-		address, err := getAddressNameResolved(cw.sdb, msg.Account)
-		if err != nil {
-			context.Respond(message.GetStateRsp{
-				Account: msg.Account,
-				State:   nil,
-				Err:     err,
-			})
-			return
-		}
-		id := types.ToAccountID(address)
-		accState, err := cw.sdb.GetStateDB().GetAccountState(id)
-		if err != nil {
-			logger.Error().Str("hash", enc.ToString(address)).Err(err).Msg("failed to get state for account")
-		}
+		address, accState, err := cw.getState(msg.Account, msg.Block)
 		context.Respond(message.GetStateRsp{
 			Account: address,
 			State:   accState,
 			Err:     err,
 		})
 	case *message.GetStateAndProof:
-		address, err := getAddressNameResolved(cw.sdb, msg.Account)
+		root := msg.Root
+		sdb, err := cw.openStateDB(msg.Block)
+		if err == nil && len(root) == 0 {
+			root, err = cw.getStateRoot(msg.Block)
+		}
+		if err != nil {
+			context.Respond(message.GetStateAndProofRsp{
+				StateProof: nil,
+				Err:        err,
+			})
+			break
+		}
+		address, err := getAddressNameResolved(sdb, msg.Account)
 		if err != nil {
 			context.Respond(message.GetStateAndProofRsp{
 				StateProof: nil,
@@ -668,7 +804,7 @@ func (cw *ChainWorker) Receive(context actor.Context) {
 			break
 		}
 		id := types.ToAccountID(address)
-		stateProof, err := cw.sdb.GetStateDB().GetAccountAndProof(id[:], msg.Root, msg.Compressed)
+		stateProof, err := sdb.GetAccountAndProof(id[:], root, msg.Compressed)
 		if err != nil {
 			logger.Error().Str("hash", enc.ToString(address)).Err(err).Msg("failed to get state for account")
 		}
@@ -691,7 +827,7 @@ func (cw *ChainWorker) Receive(context actor.Context) {
 			Err:     err,
 		})
 	case *message.GetABI:
-		address, err := getAddressNameResolved(cw.sdb, msg.Contract)
+		address, err := getAddressNameResolved(cw.sdb.GetStateDB(), msg.Contract)
 		if err != nil {
 			context.Respond(message.GetABIRsp{
 				ABI: nil,
@@ -712,29 +848,85 @@ func (cw *ChainWorker) Receive(context actor.Context) {
 				Err: err,
 			})
 		}
-	case *message.GetQuery:
-		runtime.LockOSThread()
-		defer runtime.UnlockOSThread()
-		address, err := getAddressNameResolved(cw.sdb, msg.Contract)
+	case *message.GetCodeVersions:
+		address, err := getAddressNameResolved(cw.sdb.GetStateDB(), msg.Contract)
 		if err != nil {
-			context.Respond(message.GetQueryRsp{Result: nil, Err: err})
+			context.Respond(message.GetCodeVersionsRsp{Err: err})
 			break
 		}
-		ctrState, err := cw.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(address))
+		contractState, err := cw.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(address))
 		if err != nil {
-			logger.Error().Str("hash", enc.ToString(address)).Err(err).Msg("failed to get state for contract")
-			context.Respond(message.GetQueryRsp{Result: nil, Err: err})
-		} else {
-			bs := state.NewBlockState(cw.sdb.OpenNewStateDB(cw.sdb.GetRoot()))
-			ret, err := contract.Query(address, bs, cw.cdb, ctrState, msg.Queryinfo)
-			context.Respond(message.GetQueryRsp{Result: ret, Err: err})
+			context.Respond(message.GetCodeVersionsRsp{Err: err})
+			break
 		}
+		versions, err := contract.GetCodeVersions(contractState)
+		context.Respond(message.GetCodeVersionsRsp{
+			Versions: &types.CodeVersionList{Versions: versions},
+			Err:      err,
+		})
+	case *message.VerifyContract:
+		address, err := getAddressNameResolved(cw.sdb.GetStateDB(), msg.Contract)
+		if err != nil {
+			context.Respond(message.GetContractSourceRsp{Err: err})
+			break
+		}
+		contractState, err := cw.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(address))
+		if err != nil {
+			context.Respond(message.GetContractSourceRsp{Err: err})
+			break
+		}
+		if err = contract.VerifySource(contractState, msg.Source); err != nil {
+			context.Respond(message.GetContractSourceRsp{Err: err})
+			break
+		}
+		src := &types.ContractSource{
+			ContractAddress: address,
+			CodeHash:        contractState.GetCodeHash(),
+			Source:          msg.Source,
+			CompilerVersion: msg.CompilerVersion,
+			BlockNo:         cw.cdb.getBestBlockNo(),
+		}
+		err = cw.cdb.writeContractSource(src)
+		context.Respond(message.GetContractSourceRsp{Source: src, Err: err})
+	case *message.GetContractSource:
+		address, err := getAddressNameResolved(cw.sdb.GetStateDB(), msg.Contract)
+		if err != nil {
+			context.Respond(message.GetContractSourceRsp{Err: err})
+			break
+		}
+		contractState, err := cw.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(address))
+		if err != nil {
+			context.Respond(message.GetContractSourceRsp{Err: err})
+			break
+		}
+		src, err := cw.cdb.getContractSource(address, contractState.GetCodeHash())
+		if err == nil && src == nil {
+			err = ErrNotVerifiedContract
+		}
+		context.Respond(message.GetContractSourceRsp{Source: src, Err: err})
+	case *message.GetQuery:
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		ret, err := cw.queryContract(msg.Contract, msg.Queryinfo, msg.Block)
+		context.Respond(message.GetQueryRsp{Result: ret, Err: err})
 	case *message.GetStateQuery:
 		var varProofs []*types.ContractVarProof
 		var contractProof *types.AccountProof
 		var err error
 
-		address, err := getAddressNameResolved(cw.sdb, msg.ContractAddress)
+		root := msg.Root
+		sdb, err := cw.openStateDB(msg.Block)
+		if err == nil && len(root) == 0 {
+			root, err = cw.getStateRoot(msg.Block)
+		}
+		if err != nil {
+			context.Respond(message.GetStateQueryRsp{
+				Result: nil,
+				Err:    err,
+			})
+			break
+		}
+		address, err := getAddressNameResolved(sdb, msg.ContractAddress)
 		if err != nil {
 			context.Respond(message.GetStateQueryRsp{
 				Result: nil,
@@ -743,14 +935,14 @@ func (cw *ChainWorker) Receive(context actor.Context) {
 			break
 		}
 		id := types.ToAccountID(address)
-		contractProof, err = cw.sdb.GetStateDB().GetAccountAndProof(id[:], msg.Root, msg.Compressed)
+		contractProof, err = sdb.GetAccountAndProof(id[:], root, msg.Compressed)
 		if err != nil {
 			logger.Error().Str("hash", enc.ToString(address)).Err(err).Msg("failed to get state for account")
 		} else if contractProof.Inclusion {
 			contractTrieRoot := contractProof.State.StorageRoot
 			for _, storageKey := range msg.StorageKeys {
 				trieKey := common.Hasher([]byte(storageKey))
-				varProof, err := cw.sdb.GetStateDB().GetVarAndProof(trieKey, contractTrieRoot, msg.Compressed)
+				varProof, err := sdb.GetVarAndProof(trieKey, contractTrieRoot, msg.Compressed)
 				varProof.Key = storageKey
 				varProofs = append(varProofs, varProof)
 				if err != nil {
@@ -792,10 +984,30 @@ func (cw *ChainWorker) Receive(context actor.Context) {
 			Err:   err,
 		})
 	case *message.ListEvents:
-		events, err := cw.listEvents(msg.Filter)
+		events, cursor, err := cw.listEvents(msg.Filter)
 		context.Respond(&message.ListEventsRsp{
-			Events: events,
-			Err:    err,
+			Events:     events,
+			NextCursor: cursor,
+			Err:        err,
+		})
+	case *message.ListAccountTxs:
+		txs, cursor, err := cw.listAccountTxs(msg.Address, msg.Cursor, msg.Limit, msg.Desc)
+		context.Respond(&message.ListAccountTxsRsp{
+			Txs:        txs,
+			NextCursor: cursor,
+			Err:        err,
+		})
+	case *message.GetStateRange:
+		keys, values, hasNext, err := cw.getStateRange(msg.Root, msg.Start, msg.Size)
+		context.Respond(message.GetStateRangeRsp{
+			Keys:    keys,
+			Values:  values,
+			HasNext: hasNext,
+			Err:     err,
+		})
+	case *message.GetContractCode:
+		context.Respond(message.GetContractCodeRsp{
+			Codes: cw.getContractCodes(msg.Hashes),
 		})
 	case *actor.Started, *actor.Stopping, *actor.Stopped, *component.CompStatReq: // donothing
 	default:
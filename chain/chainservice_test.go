@@ -2,11 +2,16 @@ package chain
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 
+	"github.com/aergoio/aergo-lib/db"
 	"github.com/aergoio/aergo/config"
 	"github.com/aergoio/aergo/consensus"
+	"github.com/aergoio/aergo/contract"
 	"github.com/aergoio/aergo/state"
 	"github.com/aergoio/aergo/types"
 	"github.com/stretchr/testify/assert"
@@ -377,3 +382,100 @@ func checkBlockEqual(t *testing.T, x *types.Block, y *types.Block) bool {
 
 	return true
 }
+
+const stateAtTestCode = `
+state.var {
+	v = state.value()
+}
+
+function constructor()
+	v:set(1)
+end
+
+function set(x)
+	v:set(x)
+end
+
+function get()
+	return v:get()
+end
+
+abi.register(set)
+abi.register_view(get)`
+
+func TestStateAtBlock(t *testing.T) {
+	initTest(t, true)
+	defer deinitTest()
+	tmpdir, _ := ioutil.TempDir("", "stateat")
+	defer os.RemoveAll(tmpdir)
+
+	cdb := NewChainDB()
+	assert.NoError(t, cdb.Init(string(db.BadgerImpl), tmpdir))
+	defer cdb.Close()
+	assert.NoError(t, cdb.addGenesisBlock(types.GetTestGenesis()))
+	contract.StartLStateFactory()
+	core := &Core{cdb: cdb, sdb: sdb}
+	cw := &ChainWorker{IChainHandler: &ChainService{Core: core}, Core: core}
+
+	sender := makeTestAddress(t)
+	ctrAddress := contract.CreateContractID(sender, 1)
+	prev, err := cdb.GetBlockByNo(0)
+	assert.NoError(t, err)
+
+	// executes tx and connects the block of the resulting state
+	addBlock := func(tx *types.Tx) *types.Block {
+		blockNo := prev.BlockNo() + 1
+		bs := state.NewBlockState(sdb.OpenNewStateDB(sdb.GetRoot()))
+		signTestAddress(t, tx)
+		assert.NoError(t, executeTx(cdb, bs, types.NewTransaction(tx), blockNo, 0, prev.BlockHash(), contract.ChainService, chainID))
+		assert.NoError(t, sdb.Apply(bs))
+
+		block := types.NewBlock(prev, sdb.GetRoot(), nil, []*types.Tx{tx}, nil, prev.GetHeader().GetTimestamp()+1)
+		dbTx := cdb.store.NewTx()
+		cdb.connectToChain(&dbTx, block, false)
+		dbTx.Commit()
+		prev = block
+		return block
+	}
+	blockNoParam := func(blockNo types.BlockNo) []byte {
+		param := make([]byte, 8)
+		binary.LittleEndian.PutUint64(param, blockNo)
+		return param
+	}
+	get := []byte(`{"Name":"get"}`)
+
+	// v = 1 at N and v = 2 at N+1
+	blockN := addBlock(&types.Tx{Body: &types.TxBody{
+		Nonce: 1, Account: sender, Payload: makeTestDeployPayload(t, stateAtTestCode), ChainIdHash: chainID}})
+	addBlock(&types.Tx{Body: &types.TxBody{
+		Nonce: 2, Account: sender, Recipient: ctrAddress, Payload: []byte(`{"Name":"set","Args":[2]}`), ChainIdHash: chainID}})
+
+	for _, param := range [][]byte{blockN.BlockHash(), blockNoParam(blockN.BlockNo())} {
+		_, senderState, err := cw.getState(sender, param)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), senderState.GetNonce())
+		ret, err := cw.queryContract(ctrAddress, get, param)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", string(ret))
+	}
+
+	// the best block is used without the block
+	_, senderState, err := cw.getState(sender, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), senderState.GetNonce())
+	ret, err := cw.queryContract(ctrAddress, get, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(ret))
+
+	// an unknown hash and a height above the best block
+	for _, param := range [][]byte{make([]byte, types.HashIDLength), blockNoParam(prev.BlockNo() + 1)} {
+		_, _, err = cw.getState(sender, param)
+		assert.IsType(t, &ErrNoBlock{}, err)
+		_, err = cw.queryContract(ctrAddress, get, param)
+		assert.IsType(t, &ErrNoBlock{}, err)
+	}
+
+	// a block param of an invalid length
+	_, _, err = cw.getState(sender, []byte{1})
+	assert.Equal(t, ErrInvalidBlockParam, err)
+}
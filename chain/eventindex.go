@@ -0,0 +1,340 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/types"
+	"github.com/gogo/protobuf/proto"
+)
+
+// The event index keeps the events of the main chain in the chain DB, so that
+// the events of a contract are listed without reading the receipts of every
+// block in the range. Every index key ends with the position of the event,
+// which is the block number, the tx index and the event index in big endian,
+// so that the entries of an index are in the order of the chain.
+//
+//	address entry:  eventIndexPrefix + 'a' + address + position => event
+//	name entry:     eventIndexPrefix + 'n' + address + hash(name) + position => block hash
+//	argument entry: eventIndexPrefix + 'g' + address + hash(name) + argNo + hash(arg) + position => block hash
+//
+// The entries of the blocks which are rolled back by a reorganization are
+// deleted with the block hash check, since the blocks of the new branch at the
+// same heights are indexed before.
+//
+// The index covers the blocks from the height stored at eventIndexStartKey,
+// which is 0 for a chain indexed from the genesis. The events of the blocks
+// connected before the index was introduced are found by the receipts.
+const (
+	eventPosLength = 16
+
+	eventAddressIndex  = 'a'
+	eventNameIndex     = 'n'
+	eventArgumentIndex = 'g'
+
+	// maxIndexedEventArgs is the number of the leading arguments of an event
+	// which are indexed.
+	maxIndexedEventArgs = 4
+
+	MaxEventListLimit = 1000
+)
+
+var (
+	eventIndexPrefix   = []byte("e")
+	eventIndexStartKey = []byte(chainDBName + ".eventIndexStart")
+
+	ErrInvalidEventCursor = errors.New("invalid event cursor")
+)
+
+func eventPos(blockNo types.BlockNo, txIdx int32, eventIdx int32) []byte {
+	pos := make([]byte, eventPosLength)
+	binary.BigEndian.PutUint64(pos, blockNo)
+	binary.BigEndian.PutUint32(pos[8:], uint32(txIdx))
+	binary.BigEndian.PutUint32(pos[12:], uint32(eventIdx))
+	return pos
+}
+
+func eventIndexAddress(addr []byte) []byte {
+	if len(addr) < types.AddressLength {
+		return types.AddressPadding(addr)
+	}
+	return addr
+}
+
+func eventAddressKey(addr []byte) []byte {
+	var key bytes.Buffer
+	key.Write(eventIndexPrefix)
+	key.WriteByte(eventAddressIndex)
+	key.Write(eventIndexAddress(addr))
+	return key.Bytes()
+}
+
+func eventNameKey(addr []byte, name string) []byte {
+	var key bytes.Buffer
+	key.Write(eventIndexPrefix)
+	key.WriteByte(eventNameIndex)
+	key.Write(eventIndexAddress(addr))
+	key.Write(common.Hasher([]byte(name)))
+	return key.Bytes()
+}
+
+func eventArgumentKey(addr []byte, name string, argNo int, arg []byte) []byte {
+	var key bytes.Buffer
+	key.Write(eventIndexPrefix)
+	key.WriteByte(eventArgumentIndex)
+	key.Write(eventIndexAddress(addr))
+	key.Write(common.Hasher([]byte(name)))
+	key.WriteByte(byte(argNo))
+	key.Write(common.Hasher(arg))
+	return key.Bytes()
+}
+
+// indexedEventArg returns the encoded value of the argument to be indexed. Only
+// the scalar values are indexed since ArgFilter matches only them.
+func indexedEventArg(value interface{}) ([]byte, bool) {
+	switch value.(type) {
+	case string, float64, bool:
+		b, err := json.Marshal(value)
+		return b, err == nil
+	default:
+		return nil, false
+	}
+}
+
+// eventIndexKeys returns the keys of the name and the argument entries of ev.
+func eventIndexKeys(ev *types.Event, pos []byte) [][]byte {
+	keys := [][]byte{append(eventNameKey(ev.ContractAddress, ev.EventName), pos...)}
+
+	var args []interface{}
+	if err := json.Unmarshal([]byte(ev.JsonArgs), &args); err != nil {
+		return keys
+	}
+	for argNo, value := range args {
+		if argNo >= maxIndexedEventArgs {
+			break
+		}
+		if arg, ok := indexedEventArg(value); ok {
+			keys = append(keys, append(eventArgumentKey(ev.ContractAddress, ev.EventName, argNo, arg), pos...))
+		}
+	}
+	return keys
+}
+
+// initEventIndex records the height which the event index covers from. It is
+// the next block of the best one, or 0 for a new chain.
+func (cdb *ChainDB) initEventIndex() {
+	if len(cdb.store.Get(eventIndexStartKey)) != 0 {
+		return
+	}
+	var start types.BlockNo
+	if latest := cdb.store.Get(latestKey); len(latest) != 0 {
+		if bestNo := types.BlockNoFromBytes(latest); bestNo > 0 {
+			start = bestNo + 1
+		}
+	}
+
+	dbTx := cdb.store.NewTx()
+	defer dbTx.Discard()
+	dbTx.Set(eventIndexStartKey, types.BlockNoToBytes(start))
+	dbTx.Commit()
+}
+
+// writeReceiptsAndEvents stores the receipts of a main chain block, and adds
+// their events to the event index by the same transaction, so that the index
+// never misses the events of the stored receipts.
+func (cdb *ChainDB) writeReceiptsAndEvents(blockHash []byte, blockNo types.BlockNo, receipts *types.Receipts) error {
+	dbTx := cdb.store.NewTx()
+	defer dbTx.Discard()
+
+	setReceipts(&dbTx, blockHash, blockNo, receipts)
+	if err := writeEventIndex(&dbTx, blockHash, blockNo, receipts); err != nil {
+		return err
+	}
+
+	dbTx.Commit()
+	return nil
+}
+
+// writeEventIndex adds the events of the receipts of a main chain block to
+// the event index.
+func writeEventIndex(dbTx *db.Transaction, blockHash []byte, blockNo types.BlockNo, receipts *types.Receipts) error {
+	for txIdx, r := range receipts.Get() {
+		for _, ev := range r.Events {
+			ev.SetMemoryInfo(r, blockHash, blockNo, int32(txIdx))
+			pos := eventPos(blockNo, int32(txIdx), ev.EventIdx)
+
+			data, err := ev.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			(*dbTx).Set(append(eventAddressKey(ev.ContractAddress), pos...), data)
+			for _, key := range eventIndexKeys(ev, pos) {
+				(*dbTx).Set(key, blockHash)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteEventIndex removes the events of a block from the event index. The
+// entries which belong to the other block at the same height are kept.
+func (cdb *ChainDB) deleteEventIndex(dbTx *db.Transaction, blockHash []byte, blockNo types.BlockNo) {
+	receipts, err := cdb.getReceipts(blockHash, blockNo)
+	if err != nil {
+		return
+	}
+
+	for txIdx, r := range receipts.Get() {
+		for _, ev := range r.Events {
+			pos := eventPos(blockNo, int32(txIdx), ev.EventIdx)
+
+			addrKey := append(eventAddressKey(ev.ContractAddress), pos...)
+			indexed := &types.Event{}
+			if data := cdb.store.Get(addrKey); len(data) == 0 {
+				continue
+			} else if _, err := indexed.UnmarshalBinary(data); err != nil || !bytes.Equal(indexed.BlockHash, blockHash) {
+				continue
+			}
+
+			(*dbTx).Delete(addrKey)
+			for _, key := range eventIndexKeys(ev, pos) {
+				(*dbTx).Delete(key)
+			}
+		}
+	}
+}
+
+// eventIndexStart returns the number of the first block covered by the event
+// index.
+func (cdb *ChainDB) eventIndexStart() (types.BlockNo, bool) {
+	data := cdb.store.Get(eventIndexStartKey)
+	if len(data) == 0 {
+		return 0, false
+	}
+	return types.BlockNoFromBytes(data), true
+}
+
+// listIndexedEvents returns the events from the event index in the block range
+// from..to. The events are returned after cursor, which is the position of
+// the last event of the previous page. The cursor of the next page is
+// returned if there are more than limit events.
+func (cdb *ChainDB) listIndexedEvents(filter *types.FilterInfo, argFilter []types.ArgFilter,
+	from, to types.BlockNo, cursor []byte, limit int) ([]*types.Event, []byte, error) {
+	if len(cursor) != 0 && len(cursor) != eventPosLength {
+		return nil, nil, ErrInvalidEventCursor
+	}
+
+	prefix := eventAddressKey(filter.ContractAddress)
+	isAddressIndex := true
+	if len(filter.EventName) != 0 {
+		prefix = eventNameKey(filter.ContractAddress, filter.EventName)
+		isAddressIndex = false
+		for _, f := range argFilter {
+			if f.ArgNo() >= maxIndexedEventArgs {
+				continue
+			}
+			if arg, ok := indexedEventArg(f.Value()); ok {
+				prefix = eventArgumentKey(filter.ContractAddress, filter.EventName, f.ArgNo(), arg)
+				break
+			}
+		}
+	}
+
+	lower := append(append([]byte(nil), prefix...), eventPos(from, 0, 0)...)
+	upper := append(append([]byte(nil), prefix...), eventPos(to+1, 0, 0)...)
+	if len(cursor) != 0 {
+		if filter.Desc {
+			upper = append(append([]byte(nil), prefix...), cursor...)
+		} else {
+			lower = append(append([]byte(nil), prefix...), nextEventPos(cursor)...)
+		}
+	}
+	if bytes.Compare(lower, upper) >= 0 {
+		return []*types.Event{}, nil, nil
+	}
+
+	var iter db.Iterator
+	if filter.Desc {
+		// the iterator moves in the reverse order if start > end
+		iter = cdb.store.Iterator(upper, lower)
+	} else {
+		iter = cdb.store.Iterator(lower, upper)
+	}
+
+	mainHashes := make(map[types.BlockNo][]byte)
+	isMain := func(blockNo types.BlockNo, blockHash []byte) bool {
+		hash, exist := mainHashes[blockNo]
+		if !exist {
+			hash, _ = cdb.getHashByNo(blockNo)
+			mainHashes[blockNo] = hash
+		}
+		return bytes.Equal(hash, blockHash)
+	}
+
+	events := []*types.Event{}
+	var totalSize uint64
+	var lastPos []byte
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if len(key) != len(prefix)+eventPosLength || bytes.Compare(key, lower) < 0 || bytes.Compare(key, upper) >= 0 {
+			continue
+		}
+		pos := key[len(prefix):]
+
+		data := iter.Value()
+		if !isAddressIndex {
+			if !isMain(types.BlockNo(binary.BigEndian.Uint64(pos)), data) {
+				continue
+			}
+			data = cdb.store.Get(append(eventAddressKey(filter.ContractAddress), pos...))
+		}
+		ev := &types.Event{}
+		if len(data) == 0 {
+			continue
+		}
+		if _, err := ev.UnmarshalBinary(data); err != nil {
+			return nil, nil, err
+		}
+		if !isMain(ev.BlockNo, ev.BlockHash) || !ev.Filter(filter, argFilter) {
+			continue
+		}
+
+		if limit > 0 && len(events) == limit {
+			return events, lastPos, nil
+		}
+		events = append(events, ev)
+		lastPos = append([]byte(nil), pos...)
+
+		totalSize += uint64(proto.Size(ev))
+		if totalSize > MaxEventSize {
+			if limit > 0 {
+				return events, lastPos, nil
+			}
+			return nil, nil, fmt.Errorf("too large size of event (%v)", totalSize)
+		}
+	}
+
+	return events, nil, nil
+}
+
+// nextEventPos returns the smallest position after pos.
+func nextEventPos(pos []byte) []byte {
+	next := append([]byte(nil), pos...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
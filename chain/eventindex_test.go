@@ -0,0 +1,157 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEventBlock(t *testing.T, cdb *ChainDB, prev *types.Block, contract []byte, count int) (*types.Block, *types.Receipts) {
+	r := types.NewReceipt(contract, "SUCCESS", "{}")
+	r.TxHash = common.Hasher([]byte(fmt.Sprintf("tx%d", prev.BlockNo()+1)))
+	for i := 0; i < count; i++ {
+		r.Events = append(r.Events, &types.Event{
+			ContractAddress: contract,
+			EventName:       fmt.Sprintf("event%d", i%2),
+			JsonArgs:        fmt.Sprintf(`["arg%d", %d]`, i%3, prev.BlockNo()+1),
+			EventIdx:        int32(i),
+		})
+	}
+	receipts := &types.Receipts{}
+	receipts.Set([]*types.Receipt{r})
+
+	block := types.NewBlock(prev, nil, receipts, nil, nil, prev.GetHeader().GetTimestamp()+1)
+	dbTx := cdb.store.NewTx()
+	cdb.connectToChain(&dbTx, block, false)
+	dbTx.Commit()
+
+	assert.NoError(t, cdb.writeReceiptsAndEvents(block.BlockHash(), block.BlockNo(), receipts))
+
+	return block, receipts
+}
+
+func TestEventIndex(t *testing.T) {
+	tmpdir, _ := ioutil.TempDir("", "eventindex")
+	defer os.RemoveAll(tmpdir)
+
+	cdb := NewChainDB()
+	assert.NoError(t, cdb.Init(string(db.BadgerImpl), tmpdir))
+	defer cdb.Close()
+	assert.NoError(t, cdb.addGenesisBlock(types.GetTestGenesis()))
+
+	contract := append([]byte{0x02}, common.Hasher([]byte("contract"))...)
+	prev, err := cdb.GetBlockByNo(0)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		prev, _ = newTestEventBlock(t, cdb, prev, contract, 4)
+	}
+	start, exist := cdb.eventIndexStart()
+	assert.True(t, exist)
+	assert.Equal(t, types.BlockNo(0), start)
+
+	filter := &types.FilterInfo{ContractAddress: contract}
+	events, cursor, err := cdb.listIndexedEvents(filter, nil, 1, 10, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, len(events))
+	assert.Nil(t, cursor)
+
+	// by name and argument
+	filter = &types.FilterInfo{ContractAddress: contract, EventName: "event0", ArgFilter: []byte(`{"0":"arg2"}`)}
+	argFilter, err := filter.GetExArgFilter()
+	assert.NoError(t, err)
+	events, _, err = cdb.listIndexedEvents(filter, argFilter, 1, 10, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, len(events))
+	for _, ev := range events {
+		assert.Equal(t, "event0", ev.EventName)
+		assert.Equal(t, int32(2), ev.EventIdx)
+	}
+
+	// pagination in the descending order
+	filter = &types.FilterInfo{ContractAddress: contract, EventName: "event1", Desc: true}
+	var paged []*types.Event
+	for {
+		events, cursor, err = cdb.listIndexedEvents(filter, nil, 3, 7, cursor, 3)
+		assert.NoError(t, err)
+		paged = append(paged, events...)
+		if cursor == nil {
+			break
+		}
+	}
+	assert.Equal(t, 10, len(paged))
+	assert.Equal(t, uint64(7), paged[0].BlockNo)
+	assert.Equal(t, uint64(3), paged[9].BlockNo)
+
+	// the events of a rolled back block are deleted
+	dbTx := cdb.store.NewTx()
+	cdb.deleteEventIndex(&dbTx, prev.BlockHash(), prev.BlockNo())
+	dbTx.Commit()
+	filter = &types.FilterInfo{ContractAddress: contract}
+	events, _, err = cdb.listIndexedEvents(filter, nil, 10, 10, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(events))
+}
+
+func TestEventIndexAcrossStart(t *testing.T) {
+	tmpdir, _ := ioutil.TempDir("", "eventindex")
+	defer os.RemoveAll(tmpdir)
+
+	cdb := NewChainDB()
+	assert.NoError(t, cdb.Init(string(db.BadgerImpl), tmpdir))
+	defer cdb.Close()
+	assert.NoError(t, cdb.addGenesisBlock(types.GetTestGenesis()))
+
+	contract := append([]byte{0x02}, common.Hasher([]byte("contract"))...)
+	prev, err := cdb.GetBlockByNo(0)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		prev, _ = newTestEventBlock(t, cdb, prev, contract, 4)
+	}
+
+	// the blocks below 6 are connected before the index was introduced
+	dbTx := cdb.store.NewTx()
+	for no := types.BlockNo(1); no < 6; no++ {
+		hash, err := cdb.getHashByNo(no)
+		assert.NoError(t, err)
+		cdb.deleteEventIndex(&dbTx, hash, no)
+	}
+	dbTx.Set(eventIndexStartKey, types.BlockNoToBytes(6))
+	dbTx.Commit()
+
+	cs := &ChainService{Core: &Core{cdb: cdb}}
+	events, cursor, err := cs.listEvents(&types.FilterInfo{ContractAddress: contract})
+	assert.NoError(t, err)
+	assert.Equal(t, 40, len(events))
+	assert.Nil(t, cursor)
+
+	for _, desc := range []bool{false, true} {
+		filter := &types.FilterInfo{ContractAddress: contract, Blockfrom: 2, Blockto: 9, Limit: 3, Desc: desc}
+		var paged []*types.Event
+		for {
+			events, cursor, err = cs.listEvents(filter)
+			assert.NoError(t, err)
+			paged = append(paged, events...)
+			if cursor == nil {
+				break
+			}
+			filter.Cursor = cursor
+		}
+		assert.Equal(t, 32, len(paged))
+		for i := 1; i < len(paged); i++ {
+			prevPos := eventPos(paged[i-1].BlockNo, paged[i-1].TxIndex, paged[i-1].EventIdx)
+			pos := eventPos(paged[i].BlockNo, paged[i].TxIndex, paged[i].EventIdx)
+			assert.Equal(t, !desc, bytes.Compare(prevPos, pos) < 0)
+		}
+	}
+}
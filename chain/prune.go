@@ -0,0 +1,138 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/aergoio/aergo/consensus"
+	"github.com/aergoio/aergo/types"
+)
+
+const (
+	StateModeArchive = "archive"
+	StateModePruned  = "pruned"
+)
+
+var (
+	ErrStatePruned = errors.New("state of the block is pruned")
+)
+
+// statePruner removes the states of the old blocks in the pruned mode. The
+// states of the last retention blocks and of the blocks after the last
+// irreversible block are kept. The pruning runs in the background, and the
+// next one starts after the previous one finishes.
+type statePruner struct {
+	retention    types.BlockNo
+	lastPrunedNo types.BlockNo
+	running      int32
+
+	// marks are the journal sequences of the recent best blocks in the
+	// ascending order of the block number
+	marks []pruneMark
+}
+
+// pruneMark is the sequence of the state journal just after the state of the
+// best block is committed.
+type pruneMark struct {
+	no  types.BlockNo
+	seq uint64
+}
+
+// newStatePruner returns nil in the archive mode.
+func newStatePruner(mode string, retention uint64) (*statePruner, error) {
+	switch mode {
+	case "", StateModeArchive:
+		return nil, nil
+	case StateModePruned:
+		if retention == 0 {
+			return nil, errors.New("state retention must be positive in the pruned mode")
+		}
+		return &statePruner{retention: types.BlockNo(retention)}, nil
+	default:
+		return nil, fmt.Errorf("invalid state mode: %s", mode)
+	}
+}
+
+// mark records the journal sequence of the best block. The marks of the
+// blocks rolled back by a reorganization are replaced.
+func (p *statePruner) mark(no types.BlockNo, seq uint64) {
+	i := len(p.marks)
+	for i > 0 && p.marks[i-1].no >= no {
+		i--
+	}
+	p.marks = append(p.marks[:i], pruneMark{no: no, seq: seq})
+}
+
+// takeMark returns the mark of the highest block not above no, and removes
+// the marks before it.
+func (p *statePruner) takeMark(no types.BlockNo) (pruneMark, bool) {
+	i := len(p.marks) - 1
+	for i >= 0 && p.marks[i].no > no {
+		i--
+	}
+	if i < 0 {
+		return pruneMark{}, false
+	}
+	m := p.marks[i]
+	p.marks = p.marks[i+1:]
+	return m, true
+}
+
+// pruneState starts to delete the unreachable states once per retention
+// blocks. It is called after a block is added.
+func (cs *ChainService) pruneState() {
+	p := cs.pruner
+	if p == nil {
+		return
+	}
+
+	bestNo := cs.cdb.getBestBlockNo()
+	p.mark(bestNo, cs.sdb.PruneSeq())
+	if bestNo < p.lastPrunedNo+p.retention || bestNo < p.retention {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&p.running, 0, 1) {
+		return
+	}
+
+	retainFrom := bestNo - p.retention + 1
+	if f, ok := cs.ChainConsensus.(consensus.ChainFinality); ok {
+		if libNo := f.LibNo(); libNo < retainFrom {
+			retainFrom = libNo
+		}
+	}
+	// the states before the marked block are pruned, and the retained ones
+	// are derived from its state
+	m, ok := p.takeMark(retainFrom)
+	if !ok {
+		atomic.StoreInt32(&p.running, 0)
+		return
+	}
+	block, err := cs.cdb.GetBlockByNo(m.no)
+	if err != nil {
+		logger.Error().Err(err).Uint64("no", m.no).Msg("failed to get block to retain its state")
+		atomic.StoreInt32(&p.running, 0)
+		return
+	}
+	p.lastPrunedNo = bestNo
+
+	go func() {
+		defer atomic.StoreInt32(&p.running, 0)
+
+		start := time.Now()
+		deleted, err := cs.sdb.Prune(block.GetHeader().GetBlocksRootHash(), m.seq)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to prune state")
+			return
+		}
+		logger.Info().Uint64("best", bestNo).Uint64("retainfrom", m.no).Int("deleted", deleted).
+			Str("elapsed", time.Since(start).String()).Msg("state pruned")
+	}()
+}
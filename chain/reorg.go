@@ -311,10 +311,11 @@ func (reorg *reorganizer) swapTxMapping() error {
 
 		dbTx := cs.cdb.store.NewTx()
 
-		if err := cdb.addTxsOfBlock(&dbTx, newBlock.GetBody().GetTxs(), newBlock.BlockHash()); err != nil {
+		if err := cdb.addTxsOfBlock(&dbTx, newBlock.GetBody().GetTxs(), newBlock.BlockHash(), newBlock.BlockNo()); err != nil {
 			dbTx.Discard()
 			return err
 		}
+		cdb.addAccountTxs(&dbTx, newBlock, cs.sdb)
 
 		dbTx.Commit()
 	}
@@ -329,6 +330,15 @@ func (reorg *reorganizer) swapTxMapping() error {
 
 	bulk.Flush()
 
+	// delete the account tx index of the old blocks
+	if cdb.accountTxIndex {
+		dbTx := cdb.store.NewTx()
+		for _, oldBlock := range reorg.oldBlocks {
+			cdb.deleteAccountTxs(&dbTx, oldBlock)
+		}
+		dbTx.Commit()
+	}
+
 	//add rollbacked Tx to mempool (except played tx in roll forward)
 	count := len(oldTxs)
 	logger.Debug().Int("tx count", count).Int("overwrapped count", overwrap).Msg("tx add to mempool")
@@ -491,6 +501,7 @@ func (reorg *reorganizer) rollback() error {
 func (reorg *reorganizer) deleteOldReceipts() {
 	dbTx := reorg.cs.cdb.NewTx()
 	for _, blk := range reorg.oldBlocks {
+		reorg.cs.cdb.deleteEventIndex(&dbTx, blk.GetHash(), blk.BlockNo())
 		reorg.cs.cdb.deleteReceipts(&dbTx, blk.GetHash(), blk.BlockNo())
 	}
 	dbTx.Commit()
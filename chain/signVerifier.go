@@ -131,7 +131,15 @@ func (sv *SignVerifier) verifyTx(comm component.IComponentRequester, tx *types.T
 		}
 	}
 
-	if tx.NeedNameVerify() {
+	if tx.GetBody().GetType() == types.TxType_MULTISIG {
+		keys, err := sv.multisigKeys(tx)
+		if err != nil {
+			return false, err
+		}
+		if err = key.VerifyMultisigTx(tx, keys); err != nil {
+			return false, err
+		}
+	} else if tx.NeedNameVerify() {
 		cs, err := sv.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID([]byte(types.AergoName)))
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to get verify because of openning contract error")
@@ -151,6 +159,19 @@ func (sv *SignVerifier) verifyTx(comm component.IComponentRequester, tx *types.T
 	return false, nil
 }
 
+// multisigKeys returns the key set of the multisig tx, which is in the tx or
+// stored in the state of the sender.
+func (sv *SignVerifier) multisigKeys(tx *types.Tx) (*types.MultisigKeys, error) {
+	if keys := tx.GetBody().GetMultisig(); keys != nil {
+		return keys, nil
+	}
+	st, err := sv.sdb.GetStateDB().GetAccountState(types.ToAccountID(tx.GetBody().GetAccount()))
+	if err != nil {
+		return nil, err
+	}
+	return st.GetMultisig(), nil
+}
+
 func (sv *SignVerifier) RequestVerifyTxs(txlist *types.TxList) {
 	txs := txlist.GetTxs()
 	txLen := len(txs)
@@ -0,0 +1,75 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package chain
+
+import (
+	"errors"
+
+	"github.com/aergoio/aergo/consensus"
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/types"
+)
+
+var (
+	ErrSnapshotNotEmptyChain = errors.New("snapshot can be installed only on an empty chain")
+	ErrSnapshotNotStarted    = errors.New("no snapshot is being imported")
+)
+
+func (cs *ChainService) getStateRange(root, start []byte, size int) ([][]byte, [][]byte, bool, error) {
+	return cs.sdb.GetStateRange(root, start, size)
+}
+
+func (cs *ChainService) getContractCodes(hashes [][]byte) [][]byte {
+	return cs.sdb.GetContractCodes(hashes)
+}
+
+// importStateRange adds the trie leaves downloaded by the snapshot sync. The
+// snapshot is imported only while the chain has no block but the genesis.
+func (cs *ChainService) importStateRange(root []byte, keys, values [][]byte, done bool) error {
+	if cs.cdb.getBestBlockNo() != 0 {
+		return ErrSnapshotNotEmptyChain
+	}
+	if cs.snapshot == nil {
+		cs.snapshot = cs.sdb.NewSnapshot()
+	}
+	return cs.snapshot.Put(root, keys, values, done)
+}
+
+func (cs *ChainService) importContractCodes(codes [][]byte) error {
+	if cs.snapshot == nil {
+		return ErrSnapshotNotStarted
+	}
+	cs.snapshot.PutContractCodes(codes)
+	return nil
+}
+
+// installSnapshot makes the imported state the best state and block the best
+// block. The blocks between the genesis and block are left out of the DB.
+func (cs *ChainService) installSnapshot(block *types.Block) error {
+	if cs.cdb.getBestBlockNo() != 0 {
+		return ErrSnapshotNotEmptyChain
+	}
+	if cs.snapshot == nil {
+		return ErrSnapshotNotStarted
+	}
+	if err := cs.snapshot.Install(block.GetHeader().GetBlocksRootHash()); err != nil {
+		return err
+	}
+	cs.snapshot = nil
+
+	dbTx := cs.cdb.store.NewTx()
+	defer dbTx.Discard()
+	cs.cdb.connectToChain(&dbTx, block, false)
+	dbTx.Commit()
+
+	if r, ok := cs.ChainConsensus.(consensus.ChainStatusResetter); ok {
+		r.ResetStatus(block)
+	}
+
+	logger.Info().Uint64("no", block.BlockNo()).Str("hash", block.ID()).
+		Str("root", enc.ToString(block.GetHeader().GetBlocksRootHash())).Msg("state snapshot installed")
+	return nil
+}
@@ -5,11 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/aergoio/aergo/account/key"
+	"github.com/aergoio/aergo/cmd/aergocli/util"
 	"github.com/aergoio/aergo/types"
+	"github.com/mr-tron/base58/base58"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
 )
@@ -24,6 +28,19 @@ func init() {
 
 	newCmd.Flags().StringVar(&pw, "password", "", "Password")
 	newCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data", "Path to data directory")
+	newCmd.Flags().BoolVar(&withMnemonic, "mnemonic", false, "Create a HD wallet and print its mnemonic")
+
+	deriveCmd.Flags().StringVar(&mnemonic, "mnemonic", "", "Mnemonic of HD wallet")
+	deriveCmd.Flags().Uint32Var(&hdAccount, "account", 0, "Account of BIP-44 path")
+	deriveCmd.Flags().Uint32Var(&hdIndex, "index", 0, "First address index of BIP-44 path")
+	deriveCmd.Flags().Uint32Var(&hdCount, "count", 1, "Number of addresses")
+
+	recoverCmd.Flags().StringVar(&mnemonic, "mnemonic", "", "Mnemonic of HD wallet")
+	recoverCmd.Flags().Uint32Var(&hdAccount, "account", 0, "Account of BIP-44 path")
+	recoverCmd.Flags().Uint32Var(&hdIndex, "index", 0, "First address index of BIP-44 path")
+	recoverCmd.Flags().Uint32Var(&hdCount, "count", 1, "Number of accounts to recover")
+	recoverCmd.Flags().StringVar(&pw, "password", "", "Password")
+	recoverCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data", "Path to data directory")
 
 	listCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data", "Path to data directory")
 
@@ -36,7 +53,7 @@ func init() {
 	lockCmd.Flags().StringVar(&pw, "password", "", "Password")
 
 	importCmd.Flags().StringVar(&importFormat, "if", "", "Base58 import format string")
-	importCmd.MarkFlagRequired("if")
+	importCmd.Flags().StringVar(&keystoreFile, "keystore", "", "Path to keystore json file to import")
 	importCmd.Flags().StringVar(&pw, "password", "", "Password when exporting")
 	importCmd.Flags().StringVar(&to, "newpassword", "", "Password to be reset")
 	importCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data", "Path to data directory")
@@ -45,6 +62,7 @@ func init() {
 	exportCmd.MarkFlagRequired("address")
 	exportCmd.Flags().StringVar(&pw, "password", "", "Password")
 	exportCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data", "Path to data directory")
+	exportCmd.Flags().BoolVar(&asLegacy, "legacy", false, "Export in the old base58 format instead of keystore json")
 
 	voteCmd.Flags().StringVar(&address, "address", "", "Account address of voter")
 	voteCmd.MarkFlagRequired("address")
@@ -61,7 +79,13 @@ func init() {
 	unstakeCmd.Flags().StringVar(&amount, "amount", "0", "Amount of staking")
 	unstakeCmd.MarkFlagRequired("amount")
 
-	accountCmd.AddCommand(newCmd, listCmd, unlockCmd, lockCmd, importCmd, exportCmd, voteCmd, stakeCmd, unstakeCmd)
+	historyCmd.Flags().StringVar(&address, "address", "", "Address of account")
+	historyCmd.MarkFlagRequired("address")
+	historyCmd.Flags().Uint32Var(&historyLimit, "limit", 0, "maximum number of transactions in a page")
+	historyCmd.Flags().StringVar(&historyCursor, "cursor", "", "cursor of the page to list, which is printed with the previous page")
+	historyCmd.Flags().BoolVar(&desc, "desc", false, "descending order")
+
+	accountCmd.AddCommand(newCmd, deriveCmd, recoverCmd, listCmd, unlockCmd, lockCmd, importCmd, exportCmd, voteCmd, stakeCmd, unstakeCmd, historyCmd)
 	rootCmd.AddCommand(accountCmd)
 }
 
@@ -82,13 +106,26 @@ var newCmd = &cobra.Command{
 		}
 		var msg *types.Account
 		var addr []byte
+		var words string
 		if cmd.Flags().Changed("path") == false {
-			msg, err = client.CreateAccount(context.Background(), &param)
+			if withMnemonic {
+				var hd *types.HDAccounts
+				hd, err = client.CreateAccountMnemonic(context.Background(), &param)
+				if err == nil {
+					msg, words = hd.GetAccounts()[0], hd.GetMnemonic()
+				}
+			} else {
+				msg, err = client.CreateAccount(context.Background(), &param)
+			}
 		} else {
 			dataEnvPath := os.ExpandEnv(dataDir)
 			ks := key.NewStore(dataEnvPath, 0)
 			defer ks.CloseStore()
-			addr, err = ks.CreateKey(param.Passphrase)
+			if withMnemonic {
+				addr, words, err = ks.CreateKeyWithMnemonic(param.Passphrase)
+			} else {
+				addr, err = ks.CreateKey(param.Passphrase)
+			}
 			if err != nil {
 				cmd.Printf("Failed: %s\n", err.Error())
 				return
@@ -104,6 +141,85 @@ var newCmd = &cobra.Command{
 		} else {
 			cmd.Println(types.EncodeAddress(addr))
 		}
+		if words != "" {
+			cmd.Printf("mnemonic: %s\n", words)
+			cmd.Println("Write down the mnemonic and keep it safe. It is the only way to recover the accounts of the wallet.")
+		}
+	},
+}
+
+var (
+	withMnemonic bool
+	mnemonic     string
+	hdAccount    uint32
+	hdIndex      uint32
+	hdCount      uint32
+)
+
+var deriveCmd = &cobra.Command{
+	Use:   "derive [flags]",
+	Short: "Print the addresses of HD wallet derived from the mnemonic",
+	Long:  "Print the addresses of HD wallet derived from the mnemonic along the BIP-44 path m/44'/441'/account'/0/index. The keys are not stored",
+	Run: func(cmd *cobra.Command, args []string) {
+		words, err := getMnemonic(cmd)
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		addrs, err := key.DeriveAddresses(words, hdAccount, hdIndex, hdCount)
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		for i, addr := range addrs {
+			cmd.Printf("%s %s\n", key.DerivationPath(hdAccount, hdIndex+uint32(i)), types.EncodeAddress(addr))
+		}
+	},
+}
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover [flags]",
+	Short: "Recover the accounts of HD wallet from the mnemonic in the node or cli",
+	Run: func(cmd *cobra.Command, args []string) {
+		req := &types.HDAccountRequest{Account: hdAccount, Index: hdIndex, Count: hdCount}
+		var err error
+		req.Mnemonic, err = getMnemonic(cmd)
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		if pw != "" {
+			req.Passphrase = pw
+		} else {
+			req.Passphrase, err = getPasswd(cmd, true)
+			if err != nil {
+				cmd.Printf("Failed get password: %s\n", err.Error())
+				return
+			}
+		}
+		var addrs [][]byte
+		if cmd.Flags().Changed("path") == false {
+			msg, errRemote := client.RecoverAccounts(context.Background(), req)
+			if errRemote != nil {
+				cmd.Printf("Failed: %s\n", errRemote.Error())
+				return
+			}
+			for _, account := range msg.GetAccounts() {
+				addrs = append(addrs, account.GetAddress())
+			}
+		} else {
+			dataEnvPath := os.ExpandEnv(dataDir)
+			ks := key.NewStore(dataEnvPath, 0)
+			defer ks.CloseStore()
+			addrs, err = ks.RecoverKeys(req.Mnemonic, req.Account, req.Index, req.Count, req.Passphrase)
+			if err != nil {
+				cmd.Printf("Failed: %s\n", err.Error())
+				return
+			}
+		}
+		for _, addr := range addrs {
+			cmd.Println(types.EncodeAddress(addr))
+		}
 	},
 }
 
@@ -188,7 +304,15 @@ var importCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
 		var address []byte
-		importBuf, err := types.DecodePrivKey(importFormat)
+		var importBuf []byte
+		switch {
+		case keystoreFile != "":
+			importBuf, err = ioutil.ReadFile(keystoreFile)
+		case importFormat != "":
+			importBuf, err = types.DecodePrivKey(importFormat)
+		default:
+			err = errors.New("--if or --keystore is required")
+		}
 		if err != nil {
 			cmd.Printf("Failed to decode input: %s\n", err.Error())
 			return
@@ -242,7 +366,12 @@ var exportCmd = &cobra.Command{
 		}
 		var result []byte
 		if cmd.Flags().Changed("path") == false {
-			msg, err := client.ExportAccount(context.Background(), param)
+			var msg *types.SingleBytes
+			if asLegacy {
+				msg, err = client.ExportAccount(context.Background(), param)
+			} else {
+				msg, err = client.ExportAccountKeystore(context.Background(), param)
+			}
 			if err != nil {
 				cmd.Printf("Failed: %s\n", err.Error())
 				return
@@ -252,14 +381,64 @@ var exportCmd = &cobra.Command{
 			dataEnvPath := os.ExpandEnv(dataDir)
 			ks := key.NewStore(dataEnvPath, 0)
 			defer ks.CloseStore()
-			wif, err := ks.ExportKey(param.Account.Address, param.Passphrase)
+			if asLegacy {
+				result, err = ks.ExportLegacyKey(param.Account.Address, param.Passphrase)
+			} else {
+				result, err = ks.ExportKey(param.Account.Address, param.Passphrase)
+			}
 			if err != nil {
 				cmd.Printf("Failed: %s\n", err.Error())
 				return
 			}
-			result = wif
 		}
-		cmd.Println(types.EncodePrivKey(result))
+		if asLegacy {
+			cmd.Println(types.EncodePrivKey(result))
+			return
+		}
+		cmd.Println(string(result))
+	},
+}
+
+var (
+	keystoreFile string
+	asLegacy     bool
+)
+
+var (
+	historyLimit  uint32
+	historyCursor string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [flags]",
+	Short: "List transactions sent by or sent to the account",
+	Long:  "List transactions sent by or sent to the account. The node must keep the account tx index (blockchain.accounttxindex)",
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, err := types.DecodeAddress(address)
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		req := &types.AccountTxsRequest{Address: addr, Limit: historyLimit, Desc: desc}
+		if historyCursor != "" {
+			req.Cursor, err = base58.Decode(historyCursor)
+			if err != nil {
+				cmd.Printf("Failed: invalid cursor %s\n", historyCursor)
+				return
+			}
+		}
+
+		msg, err := client.ListAccountTxs(context.Background(), req)
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		for _, tx := range msg.GetTxs() {
+			cmd.Println(util.TxInBlockConvBase58Addr(tx))
+		}
+		if len(msg.GetNextCursor()) != 0 {
+			cmd.Printf("next cursor: %s\n", base58.Encode(msg.GetNextCursor()))
+		}
 	},
 }
 
@@ -304,6 +483,24 @@ func getPasswd(cmd *cobra.Command, isNew bool) (string, error) {
 	return string(password), err
 }
 
+func getMnemonic(cmd *cobra.Command) (string, error) {
+	words := mnemonic
+	if words == "" {
+		cmd.Print("Enter Mnemonic: ")
+		input, err := terminal.ReadPassword(int(syscall.Stdin))
+		cmd.Println("")
+		if err != nil {
+			return "", err
+		}
+		words = string(input)
+	}
+	words = strings.Join(strings.Fields(words), " ")
+	if !key.IsMnemonicValid(words) {
+		return "", key.ErrInvalidMnemonic
+	}
+	return words, nil
+}
+
 func preConnectAergo(cmd *cobra.Command, args []string) {
 	if cmd.Flags().Changed("path") == false {
 		connectAergo(cmd, args)
@@ -29,7 +29,7 @@ func TestAccountWithPath(t *testing.T) {
 	outputList = re.ReplaceAllString(outputList, "")
 	assert.Equalf(t, len(outputNew)+2, len(outputList), "wrong address list length value = %s", outputList)
 
-	outputExport, err := executeCommand(rootCmd, "account", "export", "--address", outputAddress, "--password", "1", "--path", testDir)
+	outputExport, err := executeCommand(rootCmd, "account", "export", "--address", outputAddress, "--password", "1", "--path", testDir, "--legacy")
 	assert.NoError(t, err, "should be success")
 	importFormat := strings.Split(outputExport, "\n")[0]
 
@@ -13,17 +13,22 @@ import (
 
 	"github.com/aergoio/aergo/cmd/aergocli/util"
 	luacEncoding "github.com/aergoio/aergo/cmd/aergoluac/encoding"
+	"github.com/aergoio/aergo/fee"
 	"github.com/aergoio/aergo/types"
 	"github.com/mr-tron/base58/base58"
 	"github.com/spf13/cobra"
 )
 
 var (
-	client *util.ConnClient
-	data   string
-	nonce  uint64
-	toJson bool
-	gover  bool
+	client   *util.ConnClient
+	data     string
+	nonce    uint64
+	toJson   bool
+	gover    bool
+	gasLimit uint64
+	gasPrice string
+	redeploy string
+	compiler string
 )
 
 func init() {
@@ -41,6 +46,17 @@ func init() {
 	}
 	deployCmd.PersistentFlags().StringVar(&data, "payload", "", "result of compiling a contract")
 	deployCmd.PersistentFlags().StringVar(&amount, "amount", "0", "setting amount")
+	deployCmd.PersistentFlags().Uint64Var(&gasLimit, "gaslimit", 0, "setting gas limit (0 means the payload based fee)")
+	deployCmd.PersistentFlags().StringVar(&gasPrice, "gasprice", "", "setting gas price in AER (the minimum gas price if not set)")
+	deployCmd.PersistentFlags().StringVar(&redeploy, "redeploy", "", "replace the code of the contract instead of deploying a new one")
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify [flags] contract srcfile",
+		Short: "Verify the source of the contract by compiling it on the node",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   runVerifyCmd,
+	}
+	verifyCmd.PersistentFlags().StringVar(&compiler, "compiler", "", "the version of aergoluac which compiled the deployed code, which must be the version of the node (the node version if not set)")
 
 	callCmd := &cobra.Command{
 		Use:   "call [flags] sender contract funcname '[argument...]'",
@@ -53,6 +69,8 @@ func init() {
 	callCmd.PersistentFlags().StringVar(&chainIdHash, "chainidhash", "", "chain id hash value encoded by base58")
 	callCmd.PersistentFlags().BoolVar(&toJson, "tojson", false, "get jsontx")
 	callCmd.PersistentFlags().BoolVar(&gover, "governance", false, "setting type")
+	callCmd.PersistentFlags().Uint64Var(&gasLimit, "gaslimit", 0, "setting gas limit (0 means the payload based fee)")
+	callCmd.PersistentFlags().StringVar(&gasPrice, "gasprice", "", "setting gas price in AER (the minimum gas price if not set)")
 
 	stateQueryCmd := &cobra.Command{
 		Use:   "statequery [flags] contract varname varindex",
@@ -62,6 +80,15 @@ func init() {
 	}
 	stateQueryCmd.Flags().StringVar(&stateroot, "root", "", "Query the state at a specified state root")
 	stateQueryCmd.Flags().BoolVar(&compressed, "compressed", false, "Get a compressed proof for the state")
+	stateQueryCmd.Flags().StringVar(&atBlock, "block", "", "Query the state at a specified block height or hash")
+
+	queryCmd := &cobra.Command{
+		Use:   "query [flags] contract funcname '[argument...]'",
+		Short: "Query contract by executing read-only function",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   runQueryCmd,
+	}
+	queryCmd.Flags().StringVar(&atBlock, "block", "", "Query at a specified block height or hash (not available for a sql contract)")
 
 	contractCmd.AddCommand(
 		deployCmd,
@@ -73,11 +100,19 @@ func init() {
 			Run:   runGetABICmd,
 		},
 		&cobra.Command{
-			Use:   "query [flags] contract funcname '[argument...]'",
-			Short: "Query contract by executing read-only function",
-			Args:  cobra.MinimumNArgs(2),
-			Run:   runQueryCmd,
+			Use:   "versions [flags] contract",
+			Short: "Get the code versions of the contract, which are replaced by redeploy",
+			Args:  cobra.MinimumNArgs(1),
+			Run:   runGetCodeVersionsCmd,
+		},
+		verifyCmd,
+		&cobra.Command{
+			Use:   "source [flags] contract",
+			Short: "Get the verified source of the contract",
+			Args:  cobra.MinimumNArgs(1),
+			Run:   runGetSourceCmd,
 		},
+		queryCmd,
 		stateQueryCmd,
 	)
 	rootCmd.AddCommand(contractCmd)
@@ -155,14 +190,28 @@ func runDeployCmd(cmd *cobra.Command, args []string) {
 		_, _ = fmt.Fprint(os.Stderr, "failed to parse --amount flags")
 		os.Exit(1)
 	}
+	gasPriceBigInt, ok := parseGasPrice()
+	if !ok {
+		_, _ = fmt.Fprint(os.Stderr, "failed to parse --gasprice flags")
+		os.Exit(1)
+	}
 	tx := &types.Tx{
 		Body: &types.TxBody{
-			Nonce:   state.GetNonce() + 1,
-			Account: creator,
-			Payload: payload,
-			Amount:  amountBigInt.Bytes(),
+			Nonce:    state.GetNonce() + 1,
+			Account:  creator,
+			Payload:  payload,
+			Amount:   amountBigInt.Bytes(),
+			GasLimit: gasLimit,
+			GasPrice: gasPriceBigInt.Bytes(),
 		},
 	}
+	if redeploy != "" {
+		tx.Body.Recipient, err = types.DecodeAddress(redeploy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tx.Body.Type = types.TxType_REDEPLOY
+	}
 
 	msg, err := client.SendTX(context.Background(), tx)
 	if err != nil || msg == nil {
@@ -191,15 +240,13 @@ func runCallCmd(cmd *cobra.Command, args []string) {
 	var ci types.CallInfo
 	ci.Name = args[2]
 	if len(args) > 3 {
-		err = json.Unmarshal([]byte(args[3]), &ci.Args)
+		d := json.NewDecoder(bytes.NewBufferString(args[3]))
+		d.UseNumber()
+		err = d.Decode(&ci.Args)
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
-	payload, err := json.Marshal(ci)
-	if err != nil {
-		log.Fatal(err)
-	}
 
 	if !toJson && !gover {
 		abi, err := client.GetABI(context.Background(), &types.SingleBytes{Value: contract})
@@ -216,6 +263,13 @@ func runCallCmd(cmd *cobra.Command, args []string) {
 		if !found {
 			log.Fatal(args[2], " function not found in contract :", args[1])
 		}
+		if err := abi.EncodeCallInfo(&ci); err != nil {
+			log.Fatal(err)
+		}
+	}
+	payload, err := json.Marshal(ci)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	amountBigInt, ok := new(big.Int).SetString(amount, 10)
@@ -223,6 +277,11 @@ func runCallCmd(cmd *cobra.Command, args []string) {
 		_, _ = fmt.Fprint(os.Stderr, "failed to parse --amount flags")
 		os.Exit(1)
 	}
+	gasPriceBigInt, ok := parseGasPrice()
+	if !ok {
+		_, _ = fmt.Fprint(os.Stderr, "failed to parse --gasprice flags")
+		os.Exit(1)
+	}
 	txType := types.TxType_NORMAL
 	if gover {
 		txType = types.TxType_GOVERNANCE
@@ -235,6 +294,8 @@ func runCallCmd(cmd *cobra.Command, args []string) {
 			Recipient: contract,
 			Payload:   payload,
 			Amount:    amountBigInt.Bytes(),
+			GasLimit:  gasLimit,
+			GasPrice:  gasPriceBigInt.Bytes(),
 			Type:      txType,
 		},
 	}
@@ -283,6 +344,50 @@ func runGetABICmd(cmd *cobra.Command, args []string) {
 	cmd.Println(util.JSON(abi))
 }
 
+func runGetCodeVersionsCmd(cmd *cobra.Command, args []string) {
+	contract, err := types.DecodeAddress(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	versions, err := client.GetCodeVersions(context.Background(), &types.SingleBytes{Value: contract})
+	if err != nil {
+		log.Fatal(err)
+	}
+	cmd.Println(util.CodeVersionsToString(versions))
+}
+
+func runVerifyCmd(cmd *cobra.Command, args []string) {
+	contract, err := types.DecodeAddress(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	source, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := client.VerifyContract(context.Background(), &types.VerifyContractRequest{
+		ContractAddress: contract,
+		Source:          string(source),
+		CompilerVersion: compiler,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	cmd.Println(util.ContractSourceToString(src, false))
+}
+
+func runGetSourceCmd(cmd *cobra.Command, args []string) {
+	contract, err := types.DecodeAddress(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := client.GetContractSource(context.Background(), &types.SingleBytes{Value: contract})
+	if err != nil {
+		log.Fatal(err)
+	}
+	cmd.Println(util.ContractSourceToString(src, true))
+}
+
 func runQueryCmd(cmd *cobra.Command, args []string) {
 	contract, err := types.DecodeAddress(args[0])
 	if err != nil {
@@ -301,10 +406,15 @@ func runQueryCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	block, err := parseBlockParam(atBlock)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	query := &types.Query{
 		ContractAddress: contract,
 		Queryinfo:       callinfo,
+		Block:           block,
 	}
 
 	ret, err := client.QueryContract(context.Background(), query)
@@ -328,6 +438,10 @@ func runQueryStateCmd(cmd *cobra.Command, args []string) {
 			return
 		}
 	}
+	block, err := parseBlockParam(atBlock)
+	if err != nil {
+		log.Fatal(err)
+	}
 	storageKey := bytes.NewBufferString("_sv_")
 	storageKey.WriteString(args[1])
 	if len(args) > 2 {
@@ -339,6 +453,7 @@ func runQueryStateCmd(cmd *cobra.Command, args []string) {
 		StorageKeys:     []string{storageKey.String()},
 		Root:            root,
 		Compressed:      compressed,
+		Block:           block,
 	}
 	ret, err := client.QueryContractState(context.Background(), stateQuery)
 	if err != nil {
@@ -346,3 +461,15 @@ func runQueryStateCmd(cmd *cobra.Command, args []string) {
 	}
 	cmd.Println(ret)
 }
+
+// parseGasPrice returns the gas price of the flag. The minimum gas price is
+// used if the flag isn't set, so that the metered tx isn't rejected.
+func parseGasPrice() (*big.Int, bool) {
+	if gasPrice == "" {
+		if fee.IsGasMetered(gasLimit) {
+			return fee.MinGasPrice(), true
+		}
+		return new(big.Int), true
+	}
+	return new(big.Int).SetString(gasPrice, 10)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/aergoio/aergo/cmd/aergocli/util"
 	aergorpc "github.com/aergoio/aergo/types"
+	"github.com/mr-tron/base58/base58"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,8 @@ var start uint64
 var end uint64
 var desc bool
 var recentBlockCnt int32
+var eventLimit int32
+var eventCursor string
 
 func init() {
 	eventCmd := &cobra.Command{
@@ -41,6 +44,8 @@ func init() {
 	listCmd.Flags().BoolVar(&desc, "desc", false, "descending order")
 	listCmd.Flags().StringVarP(&argFilter, "argfilter", "", "", "argument filter")
 	listCmd.Flags().Int32Var(&recentBlockCnt, "recent", 0, "recent block count")
+	listCmd.Flags().Int32Var(&eventLimit, "limit", 0, "maximum number of events in a page")
+	listCmd.Flags().StringVar(&eventCursor, "cursor", "", "cursor of the page to list, which is printed with the previous page")
 	listCmd.MarkFlagRequired("address")
 
 	streamCmd := &cobra.Command{
@@ -74,6 +79,14 @@ func execListEvent(cmd *cobra.Command, args []string) {
 		Desc:            desc,
 		ArgFilter:       []byte(argFilter),
 		RecentBlockCnt:  recentBlockCnt,
+		Limit:           eventLimit,
+	}
+	if eventCursor != "" {
+		filter.Cursor, err = base58.Decode(eventCursor)
+		if err != nil {
+			cmd.Printf("Failed: invalid cursor %s\n", eventCursor)
+			return
+		}
 	}
 
 	events, err := client.ListEvents(context.Background(), filter)
@@ -84,6 +97,9 @@ func execListEvent(cmd *cobra.Command, args []string) {
 	for _, ev := range events.GetEvents() {
 		cmd.Println(util.JSON(ev))
 	}
+	if len(events.GetNextCursor()) != 0 {
+		cmd.Printf("next cursor: %s\n", base58.Encode(events.GetNextCursor()))
+	}
 }
 
 func execStreamEvent(cmd *cobra.Command, args []string) {
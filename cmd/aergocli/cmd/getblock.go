@@ -8,6 +8,8 @@ package cmd
 import (
 	"context"
 	"encoding/binary"
+	"errors"
+	"strconv"
 
 	"github.com/aergoio/aergo/cmd/aergocli/util"
 	aergorpc "github.com/aergoio/aergo/types"
@@ -33,6 +35,27 @@ func init() {
 	getblockCmd.Flags().BoolVar(&stream, "stream", false, "Get the block information by streamming")
 }
 
+// parseBlockParam converts a block height or a base58 encoded block hash to
+// the block parameter of the state queries.
+func parseBlockParam(block string) ([]byte, error) {
+	if len(block) == 0 {
+		return nil, nil
+	}
+	if no, err := strconv.ParseUint(block, 10, 64); err == nil {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, no)
+		return b, nil
+	}
+	decoded, err := base58.Decode(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != aergorpc.HashIDLength {
+		return nil, errors.New("invalid block hash")
+	}
+	return decoded, nil
+}
+
 func execGetBlock(cmd *cobra.Command, args []string) {
 	if stream {
 		bs, err := client.ListBlockStream(context.Background(), &aergorpc.Empty{})
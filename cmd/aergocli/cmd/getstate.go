@@ -24,6 +24,7 @@ func init() {
 	getstateCmd.Flags().StringVar(&address, "address", "", "Get state from the address")
 	getstateCmd.MarkFlagRequired("address")
 	getstateCmd.Flags().StringVar(&stateroot, "root", "", "Get the state at a specified state root")
+	getstateCmd.Flags().StringVar(&atBlock, "block", "", "Get the state at a specified block height or hash")
 	getstateCmd.Flags().BoolVar(&proof, "proof", false, "Get the proof for the state")
 	getstateCmd.Flags().BoolVar(&compressed, "compressed", false, "Get a compressed proof for the state")
 	getstateCmd.Flags().BoolVar(&staking, "staking", false, "Get the staking info from the address")
@@ -41,6 +42,11 @@ func execGetState(cmd *cobra.Command, args []string) {
 			return
 		}
 	}
+	block, err := parseBlockParam(atBlock)
+	if err != nil {
+		cmd.Printf("decode error: %s", err.Error())
+		return
+	}
 	addr, err := types.DecodeAddress(address)
 	if err != nil {
 		cmd.Printf("Failed: %s\n", err.Error())
@@ -67,8 +73,14 @@ func execGetState(cmd *cobra.Command, args []string) {
 	if !proof {
 		// NOTE GetState first queries the statedb buffer.
 		// So the prefered way to get the state is with a proof
-		msg, err := client.GetState(context.Background(),
-			&types.SingleBytes{Value: addr})
+		var msg *types.State
+		if block == nil {
+			msg, err = client.GetState(context.Background(),
+				&types.SingleBytes{Value: addr})
+		} else {
+			msg, err = client.GetStateAt(context.Background(),
+				&types.AccountAndRoot{Account: addr, Block: block})
+		}
 		if err != nil {
 			cmd.Printf("Failed: %s", err.Error())
 			return
@@ -81,10 +93,10 @@ func execGetState(cmd *cobra.Command, args []string) {
 		cmd.Printf(`{"account":"%s", "nonce":%d, "balance":"%s"}`+"\n",
 			address, msg.GetNonce(), balance)
 	} else {
-		// Get the state and proof at a specific root.
-		// If root is nil, the latest block is queried.
+		// Get the state and proof at a specific root or block.
+		// If both are nil, the latest block is queried.
 		msg, err := client.GetStateAndProof(context.Background(),
-			&types.AccountAndRoot{Account: addr, Root: root, Compressed: compressed})
+			&types.AccountAndRoot{Account: addr, Root: root, Compressed: compressed, Block: block})
 		if err != nil {
 			cmd.Printf("Failed: %s", err.Error())
 			return
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/aergoio/aergo/cmd/aergocli/util/encoding/json"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStateAtBlockWithMock(t *testing.T) {
+	mock := initMock(t)
+	defer deinitMock()
+
+	block := make([]byte, 8)
+	binary.LittleEndian.PutUint64(block, 10)
+	mock.EXPECT().GetStateAt(
+		gomock.Any(),
+		&types.AccountAndRoot{Account: []byte("testaccount"), Block: block},
+	).Return(
+		&types.State{Nonce: 3, Balance: new(big.Int).SetUint64(100).Bytes()},
+		nil,
+	).Times(1)
+
+	output, err := executeCommand(rootCmd, "getstate", "--address", "testaccount", "--block", "10", "--unit", "aer")
+	assert.NoError(t, err, "should be success")
+	t.Log(output)
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, float64(3), result["nonce"])
+	assert.Equal(t, "100 aer", result["balance"])
+}
+
+func TestParseBlockParam(t *testing.T) {
+	b, err := parseBlockParam("")
+	assert.NoError(t, err)
+	assert.Nil(t, b)
+
+	b, err = parseBlockParam("258")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 1, 0, 0, 0, 0, 0, 0}, b)
+
+	b, err = parseBlockParam("56Qy6MQei9KM13rqEq1jiJ7Da21Kcq9KdmYWcnPLtxS3")
+	assert.NoError(t, err)
+	assert.Equal(t, types.HashIDLength, len(b))
+
+	_, err = parseBlockParam("56Qy6MQei9KM")
+	assert.Error(t, err)
+}
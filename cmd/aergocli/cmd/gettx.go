@@ -18,18 +18,34 @@ var gettxCmd = &cobra.Command{
 	Use:   "gettx",
 	Short: "Get transaction information",
 	Long:  "Get transaction information from aergosvr instance. \nIf transaction is in block, return transaction with index that represent where it's included",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  cobra.MinimumNArgs(0),
 	Run:   execGetTX,
 }
 
+var txSender string
+var txRecipient string
+var txContract string
+
 func init() {
 	rootCmd.AddCommand(gettxCmd)
+	gettxCmd.Flags().BoolVar(&stream, "stream", false, "Get the transactions accepted into the mempool by streamming")
+	gettxCmd.Flags().StringVar(&txSender, "sender", "", "Sender address to filter the streamed transactions")
+	gettxCmd.Flags().StringVar(&txRecipient, "recipient", "", "Recipient address to filter the streamed transactions")
+	gettxCmd.Flags().StringVar(&txContract, "contract", "", "Contract address to filter the streamed contract calls")
 	// args := make([]string, 0, 10)
 	// args = append(args, "subCommand")
 	// blockCmd.SetArgs(args)
 }
 
 func execGetTX(cmd *cobra.Command, args []string) {
+	if stream {
+		execStreamPendingTX(cmd)
+		return
+	}
+	if len(args) == 0 {
+		cmd.Println("no transaction hash specified")
+		return
+	}
 	txHash, err := base58.Decode(args[0])
 	if err != nil {
 		cmd.Printf("Failed decode: %s", err.Error())
@@ -48,3 +64,35 @@ func execGetTX(cmd *cobra.Command, args []string) {
 	}
 
 }
+
+func execStreamPendingTX(cmd *cobra.Command) {
+	filter := &aergorpc.TxFilter{}
+	for _, f := range []struct {
+		addr string
+		dst  *[]byte
+	}{{txSender, &filter.Sender}, {txRecipient, &filter.Recipient}, {txContract, &filter.Contract}} {
+		if f.addr == "" {
+			continue
+		}
+		decoded, err := aergorpc.DecodeAddress(f.addr)
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		*f.dst = decoded
+	}
+
+	ts, err := client.ListPendingTxStream(context.Background(), filter)
+	if err != nil {
+		cmd.Printf("Failed: %s\n", err.Error())
+		return
+	}
+	for {
+		tx, err := ts.Recv()
+		if err != nil {
+			cmd.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		cmd.Println(util.TxConvBase58Addr(tx))
+	}
+}
@@ -0,0 +1,102 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/aergoio/aergo/cmd/aergocli/util"
+	aergorpc "github.com/aergoio/aergo/types"
+	"github.com/mr-tron/base58/base58"
+	"github.com/spf13/cobra"
+)
+
+var mempoolLimit uint32
+var mempoolCursor string
+
+func init() {
+	mempoolCmd := &cobra.Command{
+		Use:   "mempool [flags] subcommand",
+		Short: "Inspect pending transactions in the mempool",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [flags]",
+		Short: "List pending transactions in the order of account and nonce",
+		Args:  cobra.NoArgs,
+		Run:   execMempoolList,
+	}
+	listCmd.Flags().Uint32Var(&mempoolLimit, "limit", 0, "maximum number of transactions in a page")
+	listCmd.Flags().StringVar(&mempoolCursor, "cursor", "", "cursor of the page to list, which is printed with the previous page")
+
+	accountCmd := &cobra.Command{
+		Use:   "account <address>",
+		Short: "Show pending transactions of an account with its nonce gaps",
+		Args:  cobra.ExactArgs(1),
+		Run:   execMempoolAccount,
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics of the mempool",
+		Args:  cobra.NoArgs,
+		Run:   execMempoolStats,
+	}
+
+	mempoolCmd.AddCommand(
+		listCmd,
+		accountCmd,
+		statsCmd,
+	)
+	rootCmd.AddCommand(mempoolCmd)
+}
+
+func execMempoolList(cmd *cobra.Command, args []string) {
+	req := &aergorpc.MempoolTxsRequest{Limit: mempoolLimit}
+	if mempoolCursor != "" {
+		var err error
+		req.Cursor, err = base58.Decode(mempoolCursor)
+		if err != nil {
+			cmd.Printf("Failed: invalid cursor %s\n", mempoolCursor)
+			return
+		}
+	}
+
+	msg, err := client.ListMempoolTxs(context.Background(), req)
+	if err != nil {
+		cmd.Printf("Failed: %s\n", err.Error())
+		return
+	}
+	for _, tx := range msg.GetTxs() {
+		cmd.Println(util.MempoolTxConvBase58Addr(tx))
+	}
+	if len(msg.GetNextCursor()) != 0 {
+		cmd.Printf("next cursor: %s\n", base58.Encode(msg.GetNextCursor()))
+	}
+}
+
+func execMempoolAccount(cmd *cobra.Command, args []string) {
+	account, err := aergorpc.DecodeAddress(args[0])
+	if err != nil {
+		cmd.Printf("Failed: %s\n", err.Error())
+		return
+	}
+	msg, err := client.GetMempoolAccount(context.Background(), &aergorpc.SingleBytes{Value: account})
+	if err != nil {
+		cmd.Printf("Failed: %s\n", err.Error())
+		return
+	}
+	cmd.Println(util.MempoolAccountConvBase58Addr(msg))
+}
+
+func execMempoolStats(cmd *cobra.Command, args []string) {
+	msg, err := client.GetMempoolStats(context.Background(), &aergorpc.Empty{})
+	if err != nil {
+		cmd.Printf("Failed: %s\n", err.Error())
+		return
+	}
+	cmd.Println(util.JSON(msg))
+}
@@ -125,6 +125,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) CreateAccount(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).CreateAccount), varargs...)
 }
 
+// CreateAccountMnemonic mocks base method
+func (m *MockAergoRPCServiceClient) CreateAccountMnemonic(arg0 context.Context, arg1 *types.Personal, arg2 ...grpc.CallOption) (*types.HDAccounts, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAccountMnemonic", varargs...)
+	ret0, _ := ret[0].(*types.HDAccounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountMnemonic indicates an expected call of CreateAccountMnemonic
+func (mr *MockAergoRPCServiceClientMockRecorder) CreateAccountMnemonic(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountMnemonic", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).CreateAccountMnemonic), varargs...)
+}
+
 // ExportAccount mocks base method
 func (m *MockAergoRPCServiceClient) ExportAccount(arg0 context.Context, arg1 *types.Personal, arg2 ...grpc.CallOption) (*types.SingleBytes, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -143,6 +161,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) ExportAccount(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccount", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ExportAccount), varargs...)
 }
 
+// ExportAccountKeystore mocks base method
+func (m *MockAergoRPCServiceClient) ExportAccountKeystore(arg0 context.Context, arg1 *types.Personal, arg2 ...grpc.CallOption) (*types.SingleBytes, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportAccountKeystore", varargs...)
+	ret0, _ := ret[0].(*types.SingleBytes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportAccountKeystore indicates an expected call of ExportAccountKeystore
+func (mr *MockAergoRPCServiceClientMockRecorder) ExportAccountKeystore(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccountKeystore", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ExportAccountKeystore), varargs...)
+}
+
 // GetABI mocks base method
 func (m *MockAergoRPCServiceClient) GetABI(arg0 context.Context, arg1 *types.SingleBytes, arg2 ...grpc.CallOption) (*types.ABI, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -287,6 +323,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) GetChainInfo(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChainInfo", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetChainInfo), varargs...)
 }
 
+// GetCodeVersions mocks base method
+func (m *MockAergoRPCServiceClient) GetCodeVersions(arg0 context.Context, arg1 *types.SingleBytes, arg2 ...grpc.CallOption) (*types.CodeVersionList, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCodeVersions", varargs...)
+	ret0, _ := ret[0].(*types.CodeVersionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeVersions indicates an expected call of GetCodeVersions
+func (mr *MockAergoRPCServiceClientMockRecorder) GetCodeVersions(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeVersions", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetCodeVersions), varargs...)
+}
+
 // GetConsensusInfo mocks base method
 func (m *MockAergoRPCServiceClient) GetConsensusInfo(arg0 context.Context, arg1 *types.Empty, arg2 ...grpc.CallOption) (*types.ConsensusInfo, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -305,6 +359,60 @@ func (mr *MockAergoRPCServiceClientMockRecorder) GetConsensusInfo(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsensusInfo", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetConsensusInfo), varargs...)
 }
 
+// GetContractSource mocks base method
+func (m *MockAergoRPCServiceClient) GetContractSource(arg0 context.Context, arg1 *types.SingleBytes, arg2 ...grpc.CallOption) (*types.ContractSource, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetContractSource", varargs...)
+	ret0, _ := ret[0].(*types.ContractSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContractSource indicates an expected call of GetContractSource
+func (mr *MockAergoRPCServiceClientMockRecorder) GetContractSource(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContractSource", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetContractSource), varargs...)
+}
+
+// GetMempoolAccount mocks base method
+func (m *MockAergoRPCServiceClient) GetMempoolAccount(arg0 context.Context, arg1 *types.SingleBytes, arg2 ...grpc.CallOption) (*types.MempoolAccount, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetMempoolAccount", varargs...)
+	ret0, _ := ret[0].(*types.MempoolAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMempoolAccount indicates an expected call of GetMempoolAccount
+func (mr *MockAergoRPCServiceClientMockRecorder) GetMempoolAccount(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMempoolAccount", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetMempoolAccount), varargs...)
+}
+
+// GetMempoolStats mocks base method
+func (m *MockAergoRPCServiceClient) GetMempoolStats(arg0 context.Context, arg1 *types.Empty, arg2 ...grpc.CallOption) (*types.MempoolStats, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetMempoolStats", varargs...)
+	ret0, _ := ret[0].(*types.MempoolStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMempoolStats indicates an expected call of GetMempoolStats
+func (mr *MockAergoRPCServiceClientMockRecorder) GetMempoolStats(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMempoolStats", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetMempoolStats), varargs...)
+}
+
 // GetNameInfo mocks base method
 func (m *MockAergoRPCServiceClient) GetNameInfo(arg0 context.Context, arg1 *types.Name, arg2 ...grpc.CallOption) (*types.NameInfo, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -431,6 +539,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) GetStateAndProof(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateAndProof", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetStateAndProof), varargs...)
 }
 
+// GetStateAt mocks base method
+func (m *MockAergoRPCServiceClient) GetStateAt(arg0 context.Context, arg1 *types.AccountAndRoot, arg2 ...grpc.CallOption) (*types.State, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetStateAt", varargs...)
+	ret0, _ := ret[0].(*types.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStateAt indicates an expected call of GetStateAt
+func (mr *MockAergoRPCServiceClientMockRecorder) GetStateAt(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateAt", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).GetStateAt), varargs...)
+}
+
 // GetTX mocks base method
 func (m *MockAergoRPCServiceClient) GetTX(arg0 context.Context, arg1 *types.SingleBytes, arg2 ...grpc.CallOption) (*types.Tx, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -485,6 +611,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) ImportAccount(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAccount", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ImportAccount), varargs...)
 }
 
+// ListAccountTxs mocks base method
+func (m *MockAergoRPCServiceClient) ListAccountTxs(arg0 context.Context, arg1 *types.AccountTxsRequest, arg2 ...grpc.CallOption) (*types.AccountTxList, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccountTxs", varargs...)
+	ret0, _ := ret[0].(*types.AccountTxList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTxs indicates an expected call of ListAccountTxs
+func (mr *MockAergoRPCServiceClientMockRecorder) ListAccountTxs(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTxs", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ListAccountTxs), varargs...)
+}
+
 // ListBlockHeaders mocks base method
 func (m *MockAergoRPCServiceClient) ListBlockHeaders(arg0 context.Context, arg1 *types.ListParams, arg2 ...grpc.CallOption) (*types.BlockHeaderList, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -593,6 +737,42 @@ func (mr *MockAergoRPCServiceClientMockRecorder) ListEvents(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ListEvents), varargs...)
 }
 
+// ListMempoolTxs mocks base method
+func (m *MockAergoRPCServiceClient) ListMempoolTxs(arg0 context.Context, arg1 *types.MempoolTxsRequest, arg2 ...grpc.CallOption) (*types.MempoolTxList, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListMempoolTxs", varargs...)
+	ret0, _ := ret[0].(*types.MempoolTxList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMempoolTxs indicates an expected call of ListMempoolTxs
+func (mr *MockAergoRPCServiceClientMockRecorder) ListMempoolTxs(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMempoolTxs", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ListMempoolTxs), varargs...)
+}
+
+// ListPendingTxStream mocks base method
+func (m *MockAergoRPCServiceClient) ListPendingTxStream(arg0 context.Context, arg1 *types.TxFilter, arg2 ...grpc.CallOption) (types.AergoRPCService_ListPendingTxStreamClient, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPendingTxStream", varargs...)
+	ret0, _ := ret[0].(types.AergoRPCService_ListPendingTxStreamClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingTxStream indicates an expected call of ListPendingTxStream
+func (mr *MockAergoRPCServiceClientMockRecorder) ListPendingTxStream(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingTxStream", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).ListPendingTxStream), varargs...)
+}
+
 // LockAccount mocks base method
 func (m *MockAergoRPCServiceClient) LockAccount(arg0 context.Context, arg1 *types.Personal, arg2 ...grpc.CallOption) (*types.Account, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -683,6 +863,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) QueryContractState(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryContractState", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).QueryContractState), varargs...)
 }
 
+// RecoverAccounts mocks base method
+func (m *MockAergoRPCServiceClient) RecoverAccounts(arg0 context.Context, arg1 *types.HDAccountRequest, arg2 ...grpc.CallOption) (*types.HDAccounts, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RecoverAccounts", varargs...)
+	ret0, _ := ret[0].(*types.HDAccounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecoverAccounts indicates an expected call of RecoverAccounts
+func (mr *MockAergoRPCServiceClientMockRecorder) RecoverAccounts(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverAccounts", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).RecoverAccounts), varargs...)
+}
+
 // SendTX mocks base method
 func (m *MockAergoRPCServiceClient) SendTX(arg0 context.Context, arg1 *types.Tx, arg2 ...grpc.CallOption) (*types.CommitResult, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -719,6 +917,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) SignTX(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTX", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).SignTX), varargs...)
 }
 
+// SimulateTX mocks base method
+func (m *MockAergoRPCServiceClient) SimulateTX(arg0 context.Context, arg1 *types.Tx, arg2 ...grpc.CallOption) (*types.SimulateResult, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SimulateTX", varargs...)
+	ret0, _ := ret[0].(*types.SimulateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateTX indicates an expected call of SimulateTX
+func (mr *MockAergoRPCServiceClientMockRecorder) SimulateTX(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTX", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).SimulateTX), varargs...)
+}
+
 // UnlockAccount mocks base method
 func (m *MockAergoRPCServiceClient) UnlockAccount(arg0 context.Context, arg1 *types.Personal, arg2 ...grpc.CallOption) (*types.Account, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -737,6 +953,24 @@ func (mr *MockAergoRPCServiceClientMockRecorder) UnlockAccount(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockAccount", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).UnlockAccount), varargs...)
 }
 
+// VerifyContract mocks base method
+func (m *MockAergoRPCServiceClient) VerifyContract(arg0 context.Context, arg1 *types.VerifyContractRequest, arg2 ...grpc.CallOption) (*types.ContractSource, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VerifyContract", varargs...)
+	ret0, _ := ret[0].(*types.ContractSource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyContract indicates an expected call of VerifyContract
+func (mr *MockAergoRPCServiceClientMockRecorder) VerifyContract(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyContract", reflect.TypeOf((*MockAergoRPCServiceClient)(nil).VerifyContract), varargs...)
+}
+
 // VerifyTX mocks base method
 func (m *MockAergoRPCServiceClient) VerifyTX(arg0 context.Context, arg1 *types.Tx, arg2 ...grpc.CallOption) (*types.VerifyResult, error) {
 	varargs := []interface{}{arg0, arg1}
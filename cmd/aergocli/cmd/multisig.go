@@ -0,0 +1,161 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/aergoio/aergo/account/key"
+	"github.com/aergoio/aergo/cmd/aergocli/util"
+	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/mr-tron/base58/base58"
+	"github.com/spf13/cobra"
+)
+
+var multisigThreshold uint32
+var multisigSigners []string
+
+func init() {
+	multisigCmd := &cobra.Command{
+		Use:   "multisig [flags] subcommand",
+		Short: "Create and sign transactions of multisig accounts",
+	}
+
+	multisigAddressCmd := &cobra.Command{
+		Use:   "address [flags]",
+		Short: "Show the address of the multisig account of the signers",
+		Args:  cobra.NoArgs,
+		RunE:  execMultisigAddress,
+	}
+	addMultisigKeyFlags(multisigAddressCmd)
+
+	multisigSignCmd := &cobra.Command{
+		Use:   "sign [flags]",
+		Short: "Add a signature to a multisig transaction",
+		Args:  cobra.NoArgs,
+		RunE:  execMultisigSign,
+	}
+	addMultisigKeyFlags(multisigSignCmd)
+	multisigSignCmd.Flags().StringVar(&jsonTx, "jsontx", "", "transaction json to sign, which may be signed by the other signers")
+	multisigSignCmd.MarkFlagRequired("jsontx")
+	multisigSignCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data/cli", "path to data directory")
+	multisigSignCmd.Flags().StringVar(&address, "address", "", "address of account to use for signing")
+	multisigSignCmd.Flags().StringVar(&pw, "password", "", "local account password")
+	multisigSignCmd.Flags().StringVar(&privKey, "key", "", "base58 encoded key for sign")
+
+	multisigCombineCmd := &cobra.Command{
+		Use:   "combine [flags]",
+		Short: "Combine the signatures of the partially signed multisig transactions",
+		Args:  cobra.NoArgs,
+		RunE:  execMultisigCombine,
+	}
+	multisigCombineCmd.Flags().StringVar(&jsonTx, "jsontx", "", "transaction list json to combine")
+	multisigCombineCmd.MarkFlagRequired("jsontx")
+
+	multisigCmd.AddCommand(
+		multisigAddressCmd,
+		multisigSignCmd,
+		multisigCombineCmd,
+	)
+	rootCmd.AddCommand(multisigCmd)
+}
+
+func addMultisigKeyFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint32Var(&multisigThreshold, "threshold", 0, "number of signatures required (M of M-of-N)")
+	cmd.Flags().StringArrayVar(&multisigSigners, "signer", nil, "address of a signer, which is repeated in the order of the key set")
+}
+
+// multisigKeys returns the key set given by the flags. The one of tx is used
+// if the flags are omitted.
+func multisigKeys(tx *types.Tx) (*types.MultisigKeys, error) {
+	if len(multisigSigners) == 0 {
+		if tx != nil && tx.GetBody().GetMultisig() != nil {
+			return tx.GetBody().GetMultisig(), nil
+		}
+		return nil, errors.New("--threshold and --signer are required")
+	}
+	var signers [][]byte
+	for _, signer := range multisigSigners {
+		addr, err := types.DecodeAddress(signer)
+		if err != nil {
+			return nil, errors.New("Wrong address in --signer flag\n" + err.Error())
+		}
+		signers = append(signers, addr)
+	}
+	return key.ParseMultisigKeys(multisigThreshold, signers)
+}
+
+func execMultisigAddress(cmd *cobra.Command, args []string) error {
+	keys, err := multisigKeys(nil)
+	if err != nil {
+		return err
+	}
+	cmd.Println(types.EncodeAddress(keys.Address()))
+	return nil
+}
+
+func execMultisigSign(cmd *cobra.Command, args []string) error {
+	txs, err := util.ParseBase58Tx([]byte(jsonTx))
+	if err != nil {
+		return errors.New("Failed to parse --jsontx\n" + err.Error())
+	}
+	tx := txs[0]
+	keys, err := multisigKeys(tx)
+	if err != nil {
+		return err
+	}
+	tx.Body.Type = types.TxType_MULTISIG
+	tx.Body.Account = keys.Address()
+	tx.Body.Multisig = keys
+
+	if privKey != "" {
+		rawKey, err := base58.Decode(privKey)
+		if err != nil {
+			return errors.New("Wrong value in --key flag\n" + err.Error())
+		}
+		signKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), rawKey)
+		if err = key.SignMultisigTx(tx, signKey, keys); err != nil {
+			return err
+		}
+	} else {
+		addr, err := types.DecodeAddress(address)
+		if err != nil {
+			return errors.New("Wrong address in --address flag\n" + err.Error())
+		}
+		index := keys.IndexOf(addr)
+		if index < 0 {
+			return key.ErrNotMultisigSigner
+		}
+		ks := key.NewStore(os.ExpandEnv(dataDir), 0)
+		defer ks.CloseStore()
+		sign, err := ks.Sign(addr, pw, key.CalculateHashWithoutSign(tx.Body))
+		if err != nil {
+			return err
+		}
+		tx.Body.AddSignature(uint32(index), sign)
+		tx.Hash = tx.CalculateTxHash()
+	}
+	cmd.Println(util.TxConvBase58Addr(tx))
+	return nil
+}
+
+func execMultisigCombine(cmd *cobra.Command, args []string) error {
+	txs, err := util.ParseBase58Tx([]byte(jsonTx))
+	if err != nil {
+		return errors.New("Failed to parse --jsontx\n" + err.Error())
+	}
+	tx, err := key.CombineMultisigTxs(txs...)
+	if err != nil {
+		return err
+	}
+	if len(tx.GetBody().GetSignatures()) < int(tx.GetBody().GetMultisig().GetThreshold()) {
+		cmd.Println("Warning:", key.ErrNotEnoughSignatures.Error())
+	}
+	cmd.Println(util.TxConvBase58Addr(tx))
+	return nil
+}
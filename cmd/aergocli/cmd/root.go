@@ -40,6 +40,7 @@ var (
 
 	address    string
 	stateroot  string
+	atBlock    string
 	proof      bool
 	compressed bool
 
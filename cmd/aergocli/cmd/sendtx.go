@@ -22,6 +22,7 @@ var sendtxCmd = &cobra.Command{
 	RunE:  execSendTX,
 }
 var chainIdHash string
+var notBefore, validUntil uint64
 
 func init() {
 	rootCmd.AddCommand(sendtxCmd)
@@ -33,6 +34,8 @@ func init() {
 	sendtxCmd.MarkFlagRequired("amount")
 	sendtxCmd.Flags().Uint64Var(&nonce, "nonce", 0, "setting nonce manually")
 	sendtxCmd.Flags().StringVar(&chainIdHash, "chainidhash", "", "hash value of chain id in the block")
+	sendtxCmd.Flags().Uint64Var(&notBefore, "notbefore", 0, "first block number the tx can be included in (0 for no limit)")
+	sendtxCmd.Flags().Uint64Var(&validUntil, "validuntil", 0, "last block number the tx can be included in (0 for no limit)")
 }
 
 func execSendTX(cmd *cobra.Command, args []string) error {
@@ -49,10 +52,12 @@ func execSendTX(cmd *cobra.Command, args []string) error {
 		return errors.New("Wrong value in --amount flag\n" + err.Error())
 	}
 	tx := &types.Tx{Body: &types.TxBody{
-		Account:   account,
-		Recipient: recipient,
-		Amount:    amountBigInt.Bytes(),
-		Nonce:     nonce,
+		Account:    account,
+		Recipient:  recipient,
+		Amount:     amountBigInt.Bytes(),
+		Nonce:      nonce,
+		NotBefore:  notBefore,
+		ValidUntil: validUntil,
 	}}
 	if chainIdHash != "" {
 		cid, err := base58.Decode(chainIdHash)
@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var asFeePayer bool
+
 func init() {
 	rootCmd.AddCommand(signCmd)
 	signCmd.Flags().StringVar(&jsonTx, "jsontx", "", "transaction json to sign")
@@ -19,6 +21,7 @@ func init() {
 	signCmd.Flags().StringVar(&address, "address", "1", "address of account to use for signing")
 	signCmd.Flags().StringVar(&pw, "password", "", "local account password")
 	signCmd.Flags().StringVar(&privKey, "key", "", "base58 encoded key for sign")
+	signCmd.Flags().BoolVar(&asFeePayer, "feepayer", false, "co-sign the fee delegation transaction as its fee payer")
 	rootCmd.AddCommand(verifyCmd)
 	verifyCmd.Flags().StringVar(&jsonTx, "jsontx", "", "transaction list json to verify")
 	verifyCmd.Flags().BoolVar(&remote, "remote", false, "verify in the node")
@@ -50,7 +53,11 @@ var signCmd = &cobra.Command{
 			}
 			tx := &types.Tx{Body: param}
 			signKey, pubkey := btcec.PrivKeyFromBytes(btcec.S256(), rawKey)
-			err = key.SignTx(tx, signKey)
+			if asFeePayer {
+				err = key.SignFeePayerTx(tx, signKey)
+			} else {
+				err = key.SignTx(tx, signKey)
+			}
 			if err != nil {
 				cmd.Printf("Failed: %s\n", err.Error())
 				return
@@ -58,10 +65,14 @@ var signCmd = &cobra.Command{
 			cmd.Println(types.EncodeAddress(key.GenerateAddress(pubkey.ToECDSA())))
 			msg = tx
 		} else if cmd.Flags().Changed("path") == false {
+			if asFeePayer {
+				cmd.Println("Failed: the fee payer should sign with --key or --path")
+				return
+			}
 			msg, err = client.SignTX(context.Background(), &types.Tx{Body: param})
 		} else {
 			tx := &types.Tx{Body: param}
-			if tx.Body.Sign != nil {
+			if !asFeePayer && tx.Body.Sign != nil {
 				tx.Body.Sign = nil
 			}
 			hash := key.CalculateHashWithoutSign(param)
@@ -79,11 +90,16 @@ var signCmd = &cobra.Command{
 				cmd.Printf("Failed: %s\n", err.Error())
 				return
 			}
-			tx.Body.Sign, err = ks.Sign(addr, pw, hash)
+			sign, err := ks.Sign(addr, pw, hash)
 			if err != nil {
 				cmd.Printf("Failed: %s\n", err.Error())
 				return
 			}
+			if asFeePayer {
+				tx.Body.PayerSign = sign
+			} else {
+				tx.Body.Sign = sign
+			}
 			tx.Hash = tx.CalculateTxHash()
 			msg = tx
 
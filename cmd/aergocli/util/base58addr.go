@@ -27,6 +27,46 @@ type InOutTxBody struct {
 	Type        types.TxType
 	ChainIdHash string
 	Sign        string
+	NotBefore   uint64                    `json:",omitempty"`
+	ValidUntil  uint64                    `json:",omitempty"`
+	FeePayer    string                    `json:",omitempty"`
+	PayerSign   string                    `json:",omitempty"`
+	Multisig    *InOutMultisigKeys        `json:",omitempty"`
+	Signatures  []*InOutMultisigSignature `json:",omitempty"`
+	Ops         []*InOutBatchOp           `json:",omitempty"`
+}
+
+type InOutMultisigKeys struct {
+	Threshold uint32
+	PubKeys   []string
+}
+
+type InOutMultisigSignature struct {
+	PubKeyIndex uint32
+	Sign        string
+}
+
+type InOutBatchOp struct {
+	Type      types.TxType
+	Recipient string
+	Amount    string
+	Payload   string
+}
+
+type InOutCodeVersion struct {
+	Version  uint64
+	CodeHash string
+	BlockNo  uint64 `json:",omitempty"`
+	TxHash   string `json:",omitempty"`
+	Deployer string `json:",omitempty"`
+}
+
+type InOutContractSource struct {
+	ContractAddress string
+	CodeHash        string
+	CompilerVersion string `json:",omitempty"`
+	BlockNo         uint64
+	Source          string `json:",omitempty"`
 }
 
 type InOutTxIdx struct {
@@ -39,6 +79,22 @@ type InOutTxInBlock struct {
 	Tx    *InOutTx
 }
 
+type InOutMempoolTx struct {
+	Tx     *InOutTx
+	Ready  bool
+	Reason string `json:",omitempty"`
+}
+
+type InOutMempoolAccount struct {
+	Account string
+	Nonce   uint64
+	Balance string
+	Ready   uint32
+	Orphan  uint32
+	Gaps    []*types.NonceGap
+	Txs     []*InOutMempoolTx
+}
+
 type InOutBlockHeader struct {
 	ChainID          string
 	PrevBlockHash    string
@@ -136,6 +192,60 @@ func FillTxBody(source *InOutTxBody, target *types.TxBody) error {
 		}
 	}
 	target.Type = source.Type
+	target.NotBefore = source.NotBefore
+	target.ValidUntil = source.ValidUntil
+	if source.FeePayer != "" {
+		target.FeePayer, err = types.DecodeAddress(source.FeePayer)
+		if err != nil {
+			return err
+		}
+	}
+	if source.PayerSign != "" {
+		target.PayerSign, err = base58.Decode(source.PayerSign)
+		if err != nil {
+			return err
+		}
+	}
+	if source.Multisig != nil {
+		target.Multisig = &types.MultisigKeys{Threshold: source.Multisig.Threshold}
+		for _, pubKey := range source.Multisig.PubKeys {
+			decoded, err := types.DecodeAddress(pubKey)
+			if err != nil {
+				return err
+			}
+			target.Multisig.PubKeys = append(target.Multisig.PubKeys, decoded)
+		}
+	}
+	for _, sig := range source.Signatures {
+		sign, err := base58.Decode(sig.Sign)
+		if err != nil {
+			return err
+		}
+		target.Signatures = append(target.Signatures, &types.MultisigSignature{PubKeyIndex: sig.PubKeyIndex, Sign: sign})
+	}
+	for _, op := range source.Ops {
+		targetOp := &types.BatchOp{Type: op.Type}
+		if op.Recipient != "" {
+			targetOp.Recipient, err = types.DecodeAddress(op.Recipient)
+			if err != nil {
+				return err
+			}
+		}
+		if op.Amount != "" {
+			amount, err := ParseUnit(op.Amount)
+			if err != nil {
+				return err
+			}
+			targetOp.Amount = amount.Bytes()
+		}
+		if op.Payload != "" {
+			targetOp.Payload, err = base58.Decode(op.Payload)
+			if err != nil {
+				return err
+			}
+		}
+		target.Ops = append(target.Ops, targetOp)
+	}
 	return nil
 }
 
@@ -206,6 +316,29 @@ func ConvTx(tx *types.Tx) *InOutTx {
 	out.Body.ChainIdHash = base58.Encode(tx.Body.ChainIdHash)
 	out.Body.Sign = base58.Encode(tx.Body.Sign)
 	out.Body.Type = tx.Body.Type
+	out.Body.NotBefore = tx.Body.NotBefore
+	out.Body.ValidUntil = tx.Body.ValidUntil
+	if tx.Body.FeePayer != nil {
+		out.Body.FeePayer = types.EncodeAddress(tx.Body.FeePayer)
+		out.Body.PayerSign = base58.Encode(tx.Body.PayerSign)
+	}
+	if tx.Body.Multisig != nil {
+		out.Body.Multisig = &InOutMultisigKeys{Threshold: tx.Body.Multisig.Threshold}
+		for _, pubKey := range tx.Body.Multisig.PubKeys {
+			out.Body.Multisig.PubKeys = append(out.Body.Multisig.PubKeys, types.EncodeAddress(pubKey))
+		}
+	}
+	for _, sig := range tx.Body.Signatures {
+		out.Body.Signatures = append(out.Body.Signatures, &InOutMultisigSignature{PubKeyIndex: sig.PubKeyIndex, Sign: base58.Encode(sig.Sign)})
+	}
+	for _, op := range tx.Body.Ops {
+		out.Body.Ops = append(out.Body.Ops, &InOutBatchOp{
+			Type:      op.Type,
+			Recipient: types.EncodeAddress(op.Recipient),
+			Amount:    new(big.Int).SetBytes(op.Amount).String(),
+			Payload:   base58.Encode(op.Payload),
+		})
+	}
 	return out
 }
 
@@ -217,6 +350,25 @@ func ConvTxInBlock(txInBlock *types.TxInBlock) *InOutTxInBlock {
 	return out
 }
 
+func ConvMempoolTx(tx *types.MempoolTx) *InOutMempoolTx {
+	return &InOutMempoolTx{Tx: ConvTx(tx.GetTx()), Ready: tx.GetReady(), Reason: tx.GetReason()}
+}
+
+func ConvMempoolAccount(acc *types.MempoolAccount) *InOutMempoolAccount {
+	out := &InOutMempoolAccount{
+		Account: types.EncodeAddress(acc.GetAccount()),
+		Nonce:   acc.GetNonce(),
+		Balance: new(big.Int).SetBytes(acc.GetBalance()).String(),
+		Ready:   acc.GetReady(),
+		Orphan:  acc.GetOrphan(),
+		Gaps:    acc.GetGaps(),
+	}
+	for _, tx := range acc.GetTxs() {
+		out.Txs = append(out.Txs, ConvMempoolTx(tx))
+	}
+	return out
+}
+
 func ConvBlock(b *types.Block) *InOutBlock {
 	out := &InOutBlock{}
 	if b != nil {
@@ -294,6 +446,14 @@ func TxInBlockConvBase58Addr(txInBlock *types.TxInBlock) string {
 	return toString(ConvTxInBlock(txInBlock))
 }
 
+func MempoolTxConvBase58Addr(tx *types.MempoolTx) string {
+	return toString(ConvMempoolTx(tx))
+}
+
+func MempoolAccountConvBase58Addr(acc *types.MempoolAccount) string {
+	return toString(ConvMempoolAccount(acc))
+}
+
 func BlockConvBase58Addr(b *types.Block) string {
 	return toString(ConvBlock(b))
 }
@@ -306,6 +466,34 @@ func PeerListToString(p *types.PeerList) string {
 	return toString(peers)
 }
 
+func CodeVersionsToString(l *types.CodeVersionList) string {
+	versions := []*InOutCodeVersion{}
+	for _, v := range l.GetVersions() {
+		out := &InOutCodeVersion{Version: v.GetVersion(), CodeHash: base58.Encode(v.GetCodeHash()), BlockNo: v.GetBlockNo()}
+		if len(v.GetTxHash()) != 0 {
+			out.TxHash = base58.Encode(v.GetTxHash())
+		}
+		if len(v.GetDeployer()) != 0 {
+			out.Deployer = types.EncodeAddress(v.GetDeployer())
+		}
+		versions = append(versions, out)
+	}
+	return toString(versions)
+}
+
+func ContractSourceToString(src *types.ContractSource, withSource bool) string {
+	out := &InOutContractSource{
+		ContractAddress: types.EncodeAddress(src.GetContractAddress()),
+		CodeHash:        base58.Encode(src.GetCodeHash()),
+		CompilerVersion: src.GetCompilerVersion(),
+		BlockNo:         src.GetBlockNo(),
+	}
+	if withSource {
+		out.Source = src.GetSource()
+	}
+	return toString(out)
+}
+
 func toString(out interface{}) string {
 	jsonout, err := json.MarshalIndent(out, "", " ")
 	if err != nil {
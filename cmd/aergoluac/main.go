@@ -10,7 +10,8 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/aergoio/aergo/cmd/aergoluac/util"
+	"github.com/aergoio/aergo/cmd/aergoluac/encoding"
+	"github.com/aergoio/aergo/pkg/luac"
 	"github.com/spf13/cobra"
 )
 
@@ -36,16 +37,20 @@ func init() {
 				return nil
 			}
 			if payload {
+				var code []byte
 				if len(args) == 0 {
-					err = util.DumpFromStdin()
+					code, err = luac.DumpFromStdin()
 				} else {
-					err = util.DumpFromFile(args[0])
+					code, err = luac.DumpFromFile(args[0])
+				}
+				if err == nil {
+					fmt.Println(encoding.EncodeCode(code))
 				}
 			} else {
 				if len(args) < 2 {
 					return errors.New("2 arguments required: <srcfile> <bcfile>")
 				}
-				err = util.CompileFromFile(args[0], args[1], abiFile)
+				err = luac.CompileFromFile(args[0], args[1], abiFile)
 			}
 			if err != nil {
 				fmt.Fprintln(os.Stderr, "Error:", err)
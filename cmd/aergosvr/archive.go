@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aergoio/aergo-actor/actor"
+	"github.com/aergoio/aergo-lib/log"
+	"github.com/aergoio/aergo/chain"
+	"github.com/aergoio/aergo/consensus/impl"
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/mempool"
+	"github.com/aergoio/aergo/message"
+	"github.com/aergoio/aergo/pkg/component"
+	"github.com/spf13/cobra"
+)
+
+const archiveAddBlockTimeout = time.Minute * 10
+
+var (
+	exportFrom uint64
+	exportTo   uint64
+	exportOut  string
+)
+
+func init() {
+	exportChain.Flags().Uint64Var(&exportFrom, "from", 1, "first block number to export")
+	exportChain.Flags().Uint64Var(&exportTo, "to", 0, "last block number to export (0 for the best block)")
+	exportChain.Flags().StringVar(&exportOut, "out", "", "path of the archive file to write")
+	_ = exportChain.MarkFlagRequired("out")
+
+	rootCmd.AddCommand(exportChain, importChain)
+}
+
+var exportChain = &cobra.Command{
+	Use:   "export",
+	Short: "Export blocks and receipts to an archive file",
+	Long:  "Export a range of blocks with their receipts to a chain archive file. The server must not be running on the data directory.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		core := getCore(cfg.DataDir)
+		if core == nil {
+			return
+		}
+		defer core.Close()
+
+		if core.GetGenesisInfo() == nil {
+			fmt.Printf("no chain in %s\n", cfg.DataDir)
+			return
+		}
+
+		file, err := os.Create(exportOut)
+		if err != nil {
+			fmt.Printf("fail to create %s (error:%s)\n", exportOut, err)
+			return
+		}
+		defer file.Close()
+
+		header, err := core.ExportArchive(file, exportFrom, exportTo)
+		if err != nil {
+			fmt.Printf("fail to export blocks (error:%s)\n", err)
+			return
+		}
+		fmt.Printf("blocks %d..%d of chain[%s] are exported to %s\n", header.From, header.To,
+			enc.ToString(header.GenesisHash), exportOut)
+	},
+}
+
+var importChain = &cobra.Command{
+	Use:   "import <archive file>",
+	Short: "Import blocks from an archive file",
+	Long: "Import the blocks of a chain archive file by executing them. The data directory must be empty, " +
+		"or its best block must be the one right before the first block of the archive.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf("fail to open %s (error:%s)\n", args[0], err)
+			return
+		}
+		defer file.Close()
+
+		reader, err := chain.NewArchiveReader(file)
+		if err != nil {
+			fmt.Printf("fail to read %s (error:%s)\n", args[0], err)
+			return
+		}
+		header := reader.Header()
+
+		core := getCore(cfg.DataDir)
+		if core == nil {
+			return
+		}
+		err = core.InitArchiveGenesis(header)
+		core.Close()
+		if err != nil {
+			fmt.Printf("fail to import %s (error:%s)\n", args[0], err)
+			return
+		}
+
+		if err := importBlocks(reader); err != nil {
+			fmt.Printf("fail to import %s (error:%s)\n", args[0], err)
+			return
+		}
+		fmt.Printf("blocks %d..%d are imported to %s\n", header.From, header.To, cfg.DataDir)
+	},
+}
+
+// importBlocks runs the chain service without the p2p network, and adds the
+// blocks of the archive to the chain one by one.
+func importBlocks(reader *chain.ArchiveReader) error {
+	svrlog = log.NewLogger("asvr")
+
+	// the blocks are only verified and executed
+	cfg.Consensus.EnableBp = false
+
+	compMng := component.NewComponentHub()
+
+	chainSvc := chain.NewChainService(cfg)
+	mpoolSvc := mempool.NewMemPoolService(cfg, chainSvc)
+
+	compMng.Register(chainSvc, mpoolSvc, newNullService(message.P2PSvc), newNullService(message.RPCSvc),
+		newNullService(message.SyncerSvc))
+
+	if _, err := impl.NewOffline(cfg, compMng, chainSvc); err != nil {
+		return err
+	}
+
+	compMng.Start()
+	defer compMng.Stop()
+
+	header := reader.Header()
+	next := header.From
+	for {
+		block, err := reader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if block.Block.BlockNo() != next {
+			return chain.ErrArchiveInvalidRange
+		}
+		// the block is verified before it is written to the chain
+		if err := chainSvc.PrepareArchiveBlock(block); err != nil {
+			return err
+		}
+
+		result, err := compMng.RequestFutureResult(message.ChainSvc, &message.AddBlock{Block: block.Block, IsSync: true},
+			archiveAddBlockTimeout, "aergosvr/importBlocks")
+		if err != nil {
+			return err
+		}
+		if err := result.(*message.AddBlockRsp).Err; err != nil {
+			return err
+		}
+
+		if next%1000 == 0 {
+			fmt.Printf("block %d is imported\n", next)
+		}
+		next++
+	}
+
+	if next != header.To+1 {
+		return fmt.Errorf("archive ends at block %d, but expected %d", next-1, header.To)
+	}
+	return nil
+}
+
+// nullService takes the place of a service which is not run by the import,
+// and drops all the messages to it.
+type nullService struct {
+	*component.BaseComponent
+}
+
+func newNullService(name string) *nullService {
+	ns := &nullService{}
+	ns.BaseComponent = component.NewBaseComponent(name, ns, svrlog)
+
+	return ns
+}
+
+func (ns *nullService) BeforeStart() {}
+
+func (ns *nullService) AfterStart() {}
+
+func (ns *nullService) BeforeStop() {}
+
+func (ns *nullService) Receive(context actor.Context) {}
+
+func (ns *nullService) Statistics() *map[string]interface{} {
+	return nil
+}
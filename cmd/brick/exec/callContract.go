@@ -1,6 +1,8 @@
 package exec
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -8,6 +10,7 @@ import (
 
 	"github.com/aergoio/aergo/cmd/brick/context"
 	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/types"
 )
 
 func init() {
@@ -82,7 +85,10 @@ func (c *callContract) Run(args string) (string, error) {
 
 	accountName, amount, contractName, funcName, callCode, expectedResult, _ := c.parse(args)
 
-	formattedQuery := fmt.Sprintf("{\"name\":\"%s\",\"args\":%s}", funcName, callCode)
+	formattedQuery, err := encodeCallCode(contractName, funcName, callCode)
+	if err != nil {
+		return "", err
+	}
 
 	callTx := contract.NewLuaTxCallBig(accountName, contractName, amount, formattedQuery)
 
@@ -92,7 +98,7 @@ func (c *callContract) Run(args string) (string, error) {
 		callTx.Fail(expectedResult)
 		zerolog.SetGlobalLevel(zerolog.ErrorLevel) // turn off log
 	}
-	err := context.Get().ConnectBlock(callTx)
+	err = context.Get().ConnectBlock(callTx)
 
 	if expectedResult != "" {
 		zerolog.SetGlobalLevel(logLevel) // restore log level
@@ -103,3 +109,29 @@ func (c *callContract) Run(args string) (string, error) {
 
 	return "call a smart contract successfully", nil
 }
+
+// encodeCallCode formats the call of the function, whose arguments are
+// validated and encoded by the types in the ABI of the contract. The call code
+// which isn't a json array is left for the contract to reject.
+func encodeCallCode(contractName, funcName, callCode string) (string, error) {
+	formattedQuery := fmt.Sprintf("{\"name\":\"%s\",\"args\":%s}", funcName, callCode)
+
+	ci := types.CallInfo{Name: funcName}
+	d := json.NewDecoder(bytes.NewBufferString(callCode))
+	d.UseNumber()
+	if err := d.Decode(&ci.Args); err != nil {
+		return formattedQuery, nil
+	}
+	abi, err := context.Get().GetABI(contractName)
+	if err != nil {
+		return formattedQuery, nil
+	}
+	if err := abi.EncodeCallInfo(&ci); err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(ci)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
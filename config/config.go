@@ -68,6 +68,8 @@ func (ctx *ServerContext) GetDefaultRPCConfig() *RPCConfig {
 		NetServicePort:  7845,
 		NetServiceTrace: false,
 		NSKey:           "",
+		JSONRPCEnable:   false,
+		JSONRPCPort:     7847,
 	}
 }
 
@@ -98,12 +100,17 @@ func (ctx *ServerContext) GetDefaultPolarisConfig() *PolarisConfig {
 
 func (ctx *ServerContext) GetDefaultBlockchainConfig() *BlockchainConfig {
 	return &BlockchainConfig{
-		MaxBlockSize:     types.DefaultMaxBlockSize,
-		CoinbaseAccount:  "",
-		MaxAnchorCount:   20,
-		VerifierCount:    types.DefaultVerifierCnt,
-		ForceResetHeight: 0,
-		ZeroFee:          true,
+		MaxBlockSize:       types.DefaultMaxBlockSize,
+		CoinbaseAccount:    "",
+		MaxAnchorCount:     20,
+		VerifierCount:      types.DefaultVerifierCnt,
+		ForceResetHeight:   0,
+		ZeroFee:            true,
+		StateMode:          "archive",
+		StateRetention:     128,
+		SnapshotSync:       false,
+		SnapshotCheckpoint: "",
+		AccountTxIndex:     false,
 	}
 }
 
@@ -114,6 +121,10 @@ func (ctx *ServerContext) GetDefaultMempoolConfig() *MempoolConfig {
 		FadeoutPeriod:  types.DefaultEvictPeriod,
 		VerifierNumber: runtime.NumCPU(),
 		DumpFilePath:   ctx.ExpandPathEnv("$HOME/mempool.dump"),
+		PriceBump:      10,
+		MaxTxCount:     100000,
+		MaxTxBytes:     256 * 1024 * 1024,
+		MaxAccountTxs:  1000,
 	}
 }
 
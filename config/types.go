@@ -47,6 +47,9 @@ type RPCConfig struct {
 	NSCert      string `mapstructure:"nscert" description:"Certificate file for RPC or REST API"`
 	NSKey       string `mapstructure:"nskey" description:"Private Key file for RPC or REST API"`
 	NSAllowCORS bool   `mapstructure:"nsallowcors" description:"Allow CORS to RPC or REST API"`
+	// JSON-RPC 2.0 gateway
+	JSONRPCEnable bool `mapstructure:"jsonrpc" description:"Enable JSON-RPC 2.0 gateway over HTTP and websocket"`
+	JSONRPCPort   int  `mapstructure:"jsonrpcport" description:"JSON-RPC gateway port"`
 }
 
 // P2PConfig defines configurations for p2p service
@@ -81,12 +84,17 @@ type PolarisConfig struct {
 
 // BlockchainConfig defines configurations for blockchain service
 type BlockchainConfig struct {
-	MaxBlockSize     uint32 `mapstructure:"maxblocksize"  description:"maximum block size in bytes"`
-	CoinbaseAccount  string `mapstructure:"coinbaseaccount" description:"wallet address for coinbase"`
-	MaxAnchorCount   int    `mapstructure:"maxanchorcount" description:"maximun anchor count for sync"`
-	VerifierCount    int    `mapstructure:"verifiercount" description:"maximun transaction verifier count"`
-	ForceResetHeight uint64 `mapstructure:"forceresetheight" description:"best height to reset chain manually"`
-	ZeroFee          bool   `mapstructure:"zerofee" description:"enable zero-fee mode(works only on private network)"`
+	MaxBlockSize       uint32 `mapstructure:"maxblocksize"  description:"maximum block size in bytes"`
+	CoinbaseAccount    string `mapstructure:"coinbaseaccount" description:"wallet address for coinbase"`
+	MaxAnchorCount     int    `mapstructure:"maxanchorcount" description:"maximun anchor count for sync"`
+	VerifierCount      int    `mapstructure:"verifiercount" description:"maximun transaction verifier count"`
+	ForceResetHeight   uint64 `mapstructure:"forceresetheight" description:"best height to reset chain manually"`
+	ZeroFee            bool   `mapstructure:"zerofee" description:"enable zero-fee mode(works only on private network)"`
+	StateMode          string `mapstructure:"statemode" description:"state storage mode(archive or pruned)"`
+	StateRetention     uint64 `mapstructure:"stateretention" description:"number of recent block states kept in pruned mode"`
+	SnapshotSync       bool   `mapstructure:"snapshotsync" description:"download the state of the checkpoint block instead of executing all the blocks when starting from an empty chain(not available for a chain with SQL contracts)"`
+	SnapshotCheckpoint string `mapstructure:"snapshotcheckpoint" description:"hash of the finalized block whose state is downloaded by the snapshot sync"`
+	AccountTxIndex     bool   `mapstructure:"accounttxindex" description:"keep the index of txs by account for the account tx history"`
 }
 
 // MempoolConfig defines configurations for mempool service
@@ -95,7 +103,11 @@ type MempoolConfig struct {
 	EnableFadeout  bool   `mapstructure:"enablefadeout" description:"Enable transaction fadeout over timeout period"`
 	FadeoutPeriod  int    `mapstructure:"fadeoutperiod" description:"time period for evict transactions(in hour)"`
 	VerifierNumber int    `mapstructure:"verifiers" description:"number of concurrent verifier"`
-	DumpFilePath   string `mapstructure:"dumpfilepath" description:"file path for recording mempool at process termintation"`
+	DumpFilePath   string `mapstructure:"dumpfilepath" description:"file path of the journal which keeps the pending txs across restarts"`
+	PriceBump      uint32 `mapstructure:"pricebump" description:"minimum gas price increase (in percent) to replace a pending tx with the same nonce"`
+	MaxTxCount     int    `mapstructure:"maxtxcount" description:"maximum number of txs in mempool (0 for no limit)"`
+	MaxTxBytes     int    `mapstructure:"maxtxbytes" description:"maximum total size of txs in mempool in bytes (0 for no limit)"`
+	MaxAccountTxs  int    `mapstructure:"maxaccounttxs" description:"maximum number of pending txs of an account (0 for no limit)"`
 }
 
 // ConsensusConfig defines configurations for consensus service
@@ -163,6 +175,8 @@ nstls = {{.RPC.NSEnableTLS}}
 nscert = "{{.RPC.NSCert}}"
 nskey = "{{.RPC.NSKey}}"
 nsallowcors = {{.RPC.NSAllowCORS}}
+jsonrpc = {{.RPC.JSONRPCEnable}}
+jsonrpcport = {{.RPC.JSONRPCPort}}
 
 [p2p]
 # Set address and port to which the inbound peers connect, and don't set loopback address or private network unless used in local network 
@@ -198,6 +212,11 @@ coinbaseaccount = "{{.Blockchain.CoinbaseAccount}}"
 maxanchorcount = "{{.Blockchain.MaxAnchorCount}}"
 verifiercount = "{{.Blockchain.VerifierCount}}"
 forceresetheight = "{{.Blockchain.ForceResetHeight}}"
+statemode = "{{.Blockchain.StateMode}}"
+stateretention = {{.Blockchain.StateRetention}}
+snapshotsync = {{.Blockchain.SnapshotSync}}
+snapshotcheckpoint = "{{.Blockchain.SnapshotCheckpoint}}"
+accounttxindex = {{.Blockchain.AccountTxIndex}}
 
 [mempool]
 showmetrics = {{.Mempool.ShowMetrics}}
@@ -205,6 +224,10 @@ enablefadeout = {{.Mempool.EnableFadeout}}
 fadeoutperiod = {{.Mempool.FadeoutPeriod}}
 verifiers = {{.Mempool.VerifierNumber}}
 dumpfilepath = "{{.Mempool.DumpFilePath}}"
+pricebump = {{.Mempool.PriceBump}}
+maxtxcount = {{.Mempool.MaxTxCount}}
+maxtxbytes = {{.Mempool.MaxTxBytes}}
+maxaccounttxs = {{.Mempool.MaxAccountTxs}}
 
 [consensus]
 enablebp = {{.Consensus.EnableBp}}
@@ -111,6 +111,19 @@ type ChainConsensus interface {
 	Info() string
 }
 
+// ChainFinality is implemented by the consensus which finalizes blocks. The
+// blocks up to LibNo are irreversible.
+type ChainFinality interface {
+	LibNo() types.BlockNo
+}
+
+// ChainStatusResetter is implemented by the consensus which keeps a status
+// built from the past blocks. ResetStatus makes the status start over from a
+// block installed by the snapshot sync without its ancestors.
+type ChainStatusResetter interface {
+	ResetStatus(block *types.Block)
+}
+
 type TxWriter interface {
 	Set(key, value []byte)
 }
@@ -101,12 +101,38 @@ func (s *Status) Update(block *types.Block) {
 	s.bestBlock = block
 }
 
+// ResetStatus makes block the best block and the LIB. The blocks before block
+// are not in the DB since its state is installed by the snapshot sync.
+func (s *Status) ResetStatus(block *types.Block) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.load()
+
+	ls := newLibStatus(s.libState.confirmsRequired)
+	ls.genesisInfo = s.libState.genesisInfo
+	ls.Lib = &blockInfo{BlockHash: block.ID(), BlockNo: block.BlockNo()}
+	s.libState = ls
+	s.bestBlock = block
+
+	s.bps.UpdateCluster(block.BlockNo())
+}
+
 func (s *Status) libNo() types.BlockNo {
 	s.RLock()
 	defer s.RUnlock()
 	return s.libState.libNo()
 }
 
+// LibNo returns the block number of the last irreversible block. It returns
+// 0 if no block is irreversible yet.
+func (s *Status) LibNo() types.BlockNo {
+	if lib := s.lib(); lib != nil {
+		return lib.BlockNo
+	}
+	return 0
+}
+
 func (s *Status) lib() *blockInfo {
 	s.RLock()
 	defer s.RUnlock()
@@ -6,6 +6,8 @@
 package impl
 
 import (
+	"fmt"
+
 	"github.com/aergoio/aergo/chain"
 	"github.com/aergoio/aergo/config"
 	"github.com/aergoio/aergo/consensus"
@@ -23,17 +25,9 @@ func New(cfg *config.Config, hub *component.ComponentHub, cs *chain.ChainService
 	var (
 		c   consensus.Consensus
 		err error
-
-		blockInterval int64
 	)
 
-	if chain.IsPublic() {
-		blockInterval = 1
-	} else {
-		blockInterval = cfg.Consensus.BlockInterval
-	}
-
-	consensus.InitBlockInterval(blockInterval)
+	initBlockInterval(cfg)
 
 	if c, err = newConsensus(cfg, hub, cs, p2psvc.GetPeerAccessor()); err == nil {
 		// Link mutual references.
@@ -45,6 +39,37 @@ func New(cfg *config.Config, hub *component.ComponentHub, cs *chain.ChainService
 	return c, err
 }
 
+// NewOffline returns consensus.Consensus which is linked only to the chain
+// service. It is used to execute the blocks without the p2p network, such as
+// the import of a chain archive. The raft consensus is not supported since it
+// needs the peers.
+func NewOffline(cfg *config.Config, hub *component.ComponentHub, cs *chain.ChainService) (consensus.Consensus, error) {
+	if cs.CDB().GetGenesisInfo().ConsensusType() == raftv2.GetName() {
+		return nil, fmt.Errorf("%s consensus can not run offline", raftv2.GetName())
+	}
+
+	initBlockInterval(cfg)
+
+	c, err := newConsensus(cfg, hub, cs, nil)
+	if err == nil {
+		cs.SetChainConsensus(c)
+	}
+
+	return c, err
+}
+
+func initBlockInterval(cfg *config.Config) {
+	var blockInterval int64
+
+	if chain.IsPublic() {
+		blockInterval = 1
+	} else {
+		blockInterval = cfg.Consensus.BlockInterval
+	}
+
+	consensus.InitBlockInterval(blockInterval)
+}
+
 func newConsensus(cfg *config.Config, hub *component.ComponentHub,
 	cs *chain.ChainService, pa p2pcommon.PeerAccessor) (consensus.Consensus, error) {
 	cdb := cs.CDB()
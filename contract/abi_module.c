@@ -0,0 +1,22 @@
+#include <lauxlib.h>
+#include "abi_module.h"
+
+/* The types of the functions and the events are recorded in the ABI by the
+ * compiler, so that the annotations do nothing while the contract runs. */
+static int abi_annotate(lua_State *L)
+{
+    return 0;
+}
+
+int luaopen_abi_annotations(lua_State *L)
+{
+    static const luaL_Reg abi_lib[] = {
+        {"types", abi_annotate},
+        {"event", abi_annotate},
+        {NULL, NULL}
+    };
+
+    luaL_register(L, "abi", abi_lib);
+    lua_pop(L, 1);
+    return 1;
+}
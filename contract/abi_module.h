@@ -0,0 +1,7 @@
+#ifndef _ABI_MODULE_H
+#define _ABI_MODULE_H
+
+#include "lua.h"
+extern int luaopen_abi_annotations(lua_State *L);
+
+#endif /* _ABI_MODULE_H */
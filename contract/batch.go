@@ -0,0 +1,62 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+// The operations of a batch tx are executed atomically. A savepoint is set on
+// each contract database when an operation of the batch writes to it first,
+// so that the changes of the earlier operations are rolled back if a later
+// one fails. The changes of the state db are rolled back by its snapshot.
+
+const batchSavepoint = "batch"
+
+// batchTxs is the set of the database txs written by the current batch of
+// each service. It is nil out of a batch.
+var batchTxs [len(preLoadInfos)]map[string]Tx
+
+// BeginBatch starts a batch of the operations executed by the service.
+func BeginBatch(service int) {
+	if service < 0 || service >= len(batchTxs) {
+		return
+	}
+	batchTxs[service] = make(map[string]Tx)
+}
+
+// EndBatch ends the batch of the service. The changes of the contract
+// databases by the batch are rolled back unless commit is set.
+func EndBatch(service int, commit bool) error {
+	if service < 0 || service >= len(batchTxs) {
+		return nil
+	}
+	txs := batchTxs[service]
+	batchTxs[service] = nil
+	for _, tx := range txs {
+		if !commit {
+			if err := tx.RollbackToSubSavepoint(batchSavepoint); err != nil {
+				return newDbSystemError(err)
+			}
+		}
+		if err := tx.SubRelease(batchSavepoint); err != nil {
+			return newDbSystemError(err)
+		}
+	}
+	return nil
+}
+
+// joinBatch sets the savepoint of the batch on the database tx of dbName,
+// when the service is in a batch and the database isn't written by it yet.
+func joinBatch(service int, dbName string, tx Tx) error {
+	if service < 0 || service >= len(batchTxs) || batchTxs[service] == nil {
+		return nil
+	}
+	if _, ok := batchTxs[service][dbName]; ok {
+		return nil
+	}
+	if err := tx.SubSavepoint(batchSavepoint); err != nil {
+		return err
+	}
+	batchTxs[service][dbName] = tx
+	return nil
+}
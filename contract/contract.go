@@ -2,6 +2,7 @@ package contract
 
 import "C"
 import (
+	"bytes"
 	"math/big"
 	"strconv"
 
@@ -37,6 +38,10 @@ var (
 const BlockFactory = 0
 const ChainService = 1
 
+// Simulator is the service which executes txs without committing them, e.g.
+// for estimating the fee of a tx.
+const Simulator = -1
+
 func init() {
 	loadReqCh = make(chan *preLoadReq, 10)
 	preLoadInfos[BlockFactory].replyCh = make(chan *loadedReply, 4)
@@ -50,11 +55,21 @@ func SetPreloadTx(tx *types.Tx, service int) {
 }
 
 func Execute(bs *state.BlockState, cdb ChainAccessor, tx *types.Tx, blockNo uint64, ts int64, prevBlockHash []byte,
-	sender, receiver *state.V, preLoadService int) (rv string, events []*types.Event, usedFee *big.Int, err error) {
+	sender, receiver *state.V, preLoadService int) (rv string, events []*types.Event, usedFee *big.Int, usedGas uint64, err error) {
 
 	txBody := tx.GetBody()
 
-	usedFee = fee.PayloadTxFee(len(txBody.GetPayload()))
+	isGasMetered := fee.IsGasMetered(txBody.GetGasLimit())
+	if isGasMetered {
+		usedGas = fee.TxGas(len(txBody.GetPayload()))
+		usedFee = fee.GasFee(usedGas, txBody.GetGasPriceBigInt())
+		if usedGas > txBody.GetGasLimit() {
+			err = types.ErrTxGasLimitTooLow
+			return
+		}
+	} else {
+		usedFee = fee.PayloadTxFee(len(txBody.GetPayload()))
+	}
 
 	// Transfer balance
 	if sender.AccountID() != receiver.AccountID() {
@@ -66,7 +81,7 @@ func Execute(bs *state.BlockState, cdb ChainAccessor, tx *types.Tx, blockNo uint
 		receiver.AddBalance(txBody.GetAmountBigInt())
 	}
 
-	if !receiver.IsCreate() && len(receiver.State().CodeHash) == 0 {
+	if !receiver.IsCreate() && len(receiver.State().CodeHash) == 0 && txBody.Type != types.TxType_REDEPLOY {
 		return
 	}
 
@@ -75,8 +90,34 @@ func Execute(bs *state.BlockState, cdb ChainAccessor, tx *types.Tx, blockNo uint
 		return
 	}
 
+	isSimulation := preLoadService == Simulator
+
+	if txBody.Type == types.TxType_REDEPLOY {
+		// the redeploy tx isn't preloaded
+		stateSet := NewContext(bs, cdb, sender, receiver, contractState, sender.ID(),
+			tx.GetHash(), blockNo, ts, prevBlockHash, "", true,
+			false, receiver.RP(), preLoadService, txBody.GetAmountBigInt())
+		if isGasMetered {
+			stateSet.setGas(txBody.GetGasLimit()-usedGas, txBody.GetGasPriceBigInt())
+		}
+		var cFee *big.Int
+		rv, events, cFee, err = Redeploy(contractState, txBody.Payload, receiver.ID(), stateSet)
+		usedFee.Add(usedFee, cFee)
+		if isGasMetered {
+			usedGas += stateSet.totalGas()
+		}
+		if err != nil {
+			if isSystemError(err) {
+				return "", events, usedFee, usedGas, err
+			}
+			return "", events, usedFee, usedGas, newVmError(err)
+		}
+		err = bs.StageContractState(contractState)
+		return rv, events, usedFee, usedGas, err
+	}
+
 	var ex *Executor
-	if !receiver.IsCreate() && preLoadInfos[preLoadService].requestedTx == tx {
+	if !isSimulation && !receiver.IsCreate() && preLoadInfos[preLoadService].requestedTx == tx {
 		replyCh := preLoadInfos[preLoadService].replyCh
 		for {
 			preload := <-replyCh
@@ -91,15 +132,33 @@ func Execute(bs *state.BlockState, cdb ChainAccessor, tx *types.Tx, blockNo uint
 		if err != nil {
 			return
 		}
+		if ex != nil && !bytes.Equal(ex.stateSet.curContract.callState.ctrState.GetCodeHash(), receiver.State().GetCodeHash()) {
+			// the contract has been redeployed after it is preloaded
+			ex.close()
+			ex = nil
+		}
 	}
 
 	var cFee *big.Int
+	var stateSet *StateSet
 	if ex != nil {
+		stateSet = ex.stateSet
 		rv, events, cFee, err = PreCall(ex, bs, sender, contractState, blockNo, ts, receiver.RP(), prevBlockHash)
 	} else {
-		stateSet := NewContext(bs, cdb, sender, receiver, contractState, sender.ID(),
+		stateSet = NewContext(bs, cdb, sender, receiver, contractState, sender.ID(),
 			tx.GetHash(), blockNo, ts, prevBlockHash, "", true,
 			false, receiver.RP(), preLoadService, txBody.GetAmountBigInt())
+		if isGasMetered {
+			stateSet.setGas(txBody.GetGasLimit()-usedGas, txBody.GetGasPriceBigInt())
+		}
+		if isSimulation {
+			stateSet.isSimulation = true
+			setQueryContext(stateSet)
+			defer func() {
+				endSimulation(stateSet)
+				curStateSet[stateSet.service] = nil
+			}()
+		}
 
 		if receiver.IsCreate() {
 			rv, events, cFee, err = Create(contractState, txBody.Payload, receiver.ID(), stateSet)
@@ -109,20 +168,23 @@ func Execute(bs *state.BlockState, cdb ChainAccessor, tx *types.Tx, blockNo uint
 	}
 
 	usedFee.Add(usedFee, cFee)
+	if isGasMetered {
+		usedGas += stateSet.totalGas()
+	}
 
 	if err != nil {
 		if isSystemError(err) {
-			return "", events, usedFee, err
+			return "", events, usedFee, usedGas, err
 		}
-		return "", events, usedFee, newVmError(err)
+		return "", events, usedFee, usedGas, newVmError(err)
 	}
 
 	err = bs.StageContractState(contractState)
 	if err != nil {
-		return "", events, usedFee, err
+		return "", events, usedFee, usedGas, err
 	}
 
-	return rv, events, usedFee, nil
+	return rv, events, usedFee, usedGas, nil
 }
 
 func PreLoadRequest(bs *state.BlockState, tx *types.Tx, preLoadService int) {
@@ -148,7 +210,7 @@ func preLoadWorker() {
 		txBody := tx.GetBody()
 		recipient := txBody.Recipient
 
-		if txBody.Type != types.TxType_NORMAL || len(recipient) == 0 {
+		if !isPreloadable(txBody.Type) || len(recipient) == 0 {
 			continue
 		}
 
@@ -170,12 +232,30 @@ func preLoadWorker() {
 		stateSet := NewContext(bs, nil, nil, receiver, contractState, txBody.GetAccount(),
 			tx.GetHash(), 0, 0, nil, "", false,
 			false, receiver.RP(), reqInfo.preLoadService, txBody.GetAmountBigInt())
+		if fee.IsGasMetered(txBody.GetGasLimit()) {
+			txGas := fee.TxGas(len(txBody.GetPayload()))
+			if txGas > txBody.GetGasLimit() {
+				replyCh <- &loadedReply{tx, nil, types.ErrTxGasLimitTooLow}
+				continue
+			}
+			stateSet.setGas(txBody.GetGasLimit()-txGas, txBody.GetGasPriceBigInt())
+		}
 
 		ex, err := PreloadEx(bs, contractState, receiver.AccountID(), txBody.Payload, receiver.ID(), stateSet)
 		replyCh <- &loadedReply{tx, ex, err}
 	}
 }
 
+// isPreloadable reports whether the txs of txType call a contract by
+// Execute, so that the contract can be loaded in advance.
+func isPreloadable(txType types.TxType) bool {
+	switch txType {
+	case types.TxType_NORMAL, types.TxType_FEE_DELEGATION, types.TxType_MULTISIG:
+		return true
+	}
+	return false
+}
+
 func CreateContractID(account []byte, nonce uint64) []byte {
 	h := sha256.New()
 	h.Write(account)
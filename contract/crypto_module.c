@@ -1,6 +1,8 @@
 #include "_cgo_export.h"
 #include "util.h"
 
+extern const int *getLuaExecContext(lua_State *L);
+
 static int crypto_sha256(lua_State *L)
 {
     size_t len;
@@ -42,9 +44,155 @@ static int crypto_ecverify(lua_State *L)
 	return 1;
 }
 
+static int crypto_keccak256(lua_State *L)
+{
+    size_t len;
+    char *arg;
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaCryptoKeccak256_return ret;
+
+    luaL_checktype(L, 1, LUA_TSTRING);
+    arg = (char *)lua_tolstring(L, 1, &len);
+
+    ret = LuaCryptoKeccak256(L, service, arg, len);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        lua_error(L);
+    }
+    strPushAndRelease(L, ret.r0);
+	return 1;
+}
+
+static int crypto_ripemd160(lua_State *L)
+{
+    size_t len;
+    char *arg;
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaCryptoRipemd160_return ret;
+
+    luaL_checktype(L, 1, LUA_TSTRING);
+    arg = (char *)lua_tolstring(L, 1, &len);
+
+    ret = LuaCryptoRipemd160(L, service, arg, len);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        lua_error(L);
+    }
+    strPushAndRelease(L, ret.r0);
+	return 1;
+}
+
+static int crypto_blake2b(lua_State *L)
+{
+    size_t len;
+    char *arg;
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaCryptoBlake2b_return ret;
+
+    luaL_checktype(L, 1, LUA_TSTRING);
+    arg = (char *)lua_tolstring(L, 1, &len);
+
+    ret = LuaCryptoBlake2b(L, service, arg, len);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        lua_error(L);
+    }
+    strPushAndRelease(L, ret.r0);
+	return 1;
+}
+
+static int crypto_ed25519verify(lua_State *L)
+{
+    char *msg, *sig, *pubkey;
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaEd25519Verify_return ret;
+
+    luaL_checktype(L, 1, LUA_TSTRING);
+    luaL_checktype(L, 2, LUA_TSTRING);
+    luaL_checktype(L, 3, LUA_TSTRING);
+    msg = (char *)lua_tostring(L, 1);
+    sig = (char *)lua_tostring(L, 2);
+    pubkey = (char *)lua_tostring(L, 3);
+
+    ret = LuaEd25519Verify(L, service, msg, sig, pubkey);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        lua_error(L);
+    }
+
+    lua_pushboolean(L, ret.r0);
+
+	return 1;
+}
+
+static int crypto_ecrecover(lua_State *L)
+{
+    char *msg, *sig;
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaECRecover_return ret;
+
+    luaL_checktype(L, 1, LUA_TSTRING);
+    luaL_checktype(L, 2, LUA_TSTRING);
+    msg = (char *)lua_tostring(L, 1);
+    sig = (char *)lua_tostring(L, 2);
+
+    ret = LuaECRecover(L, service, msg, sig);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        lua_error(L);
+    }
+    strPushAndRelease(L, ret.r0);
+	return 1;
+}
+
+static int crypto_verifyProof(lua_State *L)
+{
+    char *key, *value, *root, *auditPath;
+    int i, n = lua_gettop(L);
+    int *service = (int *)getLuaExecContext(L);
+    luaL_Buffer b;
+    struct LuaVerifyProof_return ret;
+
+    luaL_checktype(L, 1, LUA_TSTRING);
+    luaL_checktype(L, 2, LUA_TSTRING);
+    luaL_checktype(L, 3, LUA_TSTRING);
+    key = (char *)lua_tostring(L, 1);
+    value = (char *)lua_tostring(L, 2);
+    root = (char *)lua_tostring(L, 3);
+
+    /* the nodes of the audit path are passed as the comma separated list */
+    luaL_buffinit(L, &b);
+    for (i = 4; i <= n; i++) {
+        luaL_checktype(L, i, LUA_TSTRING);
+        if (i > 4) {
+            luaL_addchar(&b, ',');
+        }
+        lua_pushvalue(L, i);
+        luaL_addvalue(&b);
+    }
+    luaL_pushresult(&b);
+    auditPath = (char *)lua_tostring(L, -1);
+
+    ret = LuaVerifyProof(L, service, key, value, root, auditPath);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        lua_error(L);
+    }
+
+    lua_pushboolean(L, ret.r0);
+
+	return 1;
+}
+
 static const luaL_Reg crypto_lib[] = {
 	{"sha256", crypto_sha256},
 	{"ecverify", crypto_ecverify},
+	{"keccak256", crypto_keccak256},
+	{"ripemd160", crypto_ripemd160},
+	{"blake2b", crypto_blake2b},
+	{"ed25519verify", crypto_ed25519verify},
+	{"ecrecover", crypto_ecrecover},
+	{"verifyProof", crypto_verifyProof},
 	{NULL, NULL}
 };
 
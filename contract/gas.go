@@ -0,0 +1,91 @@
+package contract
+
+/*
+#include "vm.h"
+*/
+import "C"
+import (
+	"errors"
+	"math/big"
+
+	"github.com/aergoio/aergo/fee"
+)
+
+var errNotEnoughGas = errors.New("not enough gas")
+
+// setGas makes the execution metered by the gas schedule. The gas limit is
+// the amount left for the execution after the intrinsic gas of the tx.
+func (s *StateSet) setGas(gasLimit uint64, gasPrice *big.Int) {
+	s.gasLimit = gasLimit
+	s.gasPrice = gasPrice
+	s.usedGas = 0
+}
+
+func (s *StateSet) isGasMetered() bool {
+	return s.gasPrice != nil
+}
+
+// totalGas returns the gas used by the VM instructions, the host functions
+// and the state database updates.
+func (s *StateSet) totalGas() uint64 {
+	return s.usedGas + fee.StateDbGas(s.dbUpdateTotalSize)
+}
+
+// instLimit returns the instruction limit of the top-level contract call.
+func (s *StateSet) instLimit() C.int {
+	if !s.isGasMetered() {
+		return callMaxInstLimit
+	}
+	if s.usedGas >= s.gasLimit {
+		return C.int(1)
+	}
+	remain := s.gasLimit - s.usedGas
+	if remain > fee.MaxGasLimit {
+		remain = fee.MaxGasLimit
+	}
+	return C.int(remain)
+}
+
+// meterGas accumulates the instructions executed by ce, which has been run
+// with the instruction limit of limit, and checks the gas limit.
+func (ce *Executor) meterGas(limit C.int) {
+	stateSet := ce.stateSet
+	if ce.L == nil || !stateSet.isGasMetered() {
+		return
+	}
+	used := limit - C.luaL_instcount(ce.L)
+	if used > 0 {
+		stateSet.usedGas += uint64(used)
+	}
+	if ce.err == nil && stateSet.totalGas() > stateSet.gasLimit {
+		ce.err = errNotEnoughGas
+	}
+}
+
+// useGas charges the gas of a host function to the instruction counter of L.
+func useGas(L *LState, stateSet *StateSet, gas C.int) *C.char {
+	if stateSet == nil || !stateSet.isGasMetered() {
+		return nil
+	}
+	if C.luaL_instcount(L) <= gas {
+		C.luaL_setuncatchablerror(L)
+		return C.CString(errNotEnoughGas.Error())
+	}
+	setInstMinusCount(L, gas)
+	return nil
+}
+
+// chargeGas charges the cost of a host function, which is the gas in the
+// metered execution and the instructions deducted from the limit otherwise.
+func chargeGas(L *LState, service *C.int, gas uint64) *C.char {
+	if gas > fee.MaxGasLimit {
+		gas = fee.MaxGasLimit
+	}
+	if service != nil {
+		if stateSet := curStateSet[*service]; stateSet != nil && stateSet.isGasMetered() {
+			return useGas(L, stateSet, C.int(gas))
+		}
+	}
+	setInstMinusCount(L, C.int(gas))
+	return nil
+}
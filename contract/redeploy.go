@@ -0,0 +1,157 @@
+package contract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+)
+
+// A contract is redeployed by the redeploy tx of its creator or its owner,
+// which replaces the code of the contract and keeps its storage and its sql
+// database. The constructor isn't called again. Each code of the contract is
+// recorded as a version, which is numbered from 1 for the deployed code.
+const (
+	creatorKey        = "Creator"
+	ownerKey          = "Owner"
+	codeVersionKey    = "CodeVersion"
+	codeVersionPrefix = "CodeVersion_"
+
+	upgradeEventName = "upgrade"
+)
+
+var (
+	errNotContract           = errors.New("not a contract")
+	errRedeployNotPermitted  = errors.New("only the creator or the owner can redeploy the contract")
+	errRedeployWithArguments = errors.New("constructor arguments are not allowed in redeploy")
+)
+
+// Redeploy replaces the code of the contract with the new code.
+func Redeploy(contractState *state.ContractState, code, contractAddress []byte,
+	stateSet *StateSet) (string, []*types.Event, *big.Int, error) {
+	if len(contractState.GetCodeHash()) == 0 {
+		return "", nil, stateSet.usedFee(), errNotContract
+	}
+	sender := stateSet.curContract.sender
+	permitted, err := isContractOwner(contractState, sender)
+	if err != nil {
+		return "", nil, stateSet.usedFee(), err
+	}
+	if !permitted {
+		return "", nil, stateSet.usedFee(), errRedeployNotPermitted
+	}
+
+	versions, err := GetCodeVersions(contractState)
+	if err != nil {
+		return "", nil, stateSet.usedFee(), err
+	}
+	if ctrLog.IsDebugEnabled() {
+		ctrLog.Debug().Str("contract", types.EncodeAddress(contractAddress)).Msg("redeploy")
+	}
+	if len(code) > 4 && uint32(len(code)) > codeLength(code) {
+		return "", nil, stateSet.usedFee(), errRedeployWithArguments
+	}
+	if _, _, err = setContract(contractState, contractAddress, code); err != nil {
+		return "", nil, stateSet.usedFee(), err
+	}
+	if len(versions) == 1 {
+		// the deployed code is recorded by the first redeploy
+		if err = putCodeVersion(contractState, versions[0]); err != nil {
+			return "", nil, stateSet.usedFee(), err
+		}
+	}
+	version := &types.CodeVersion{
+		Version:  uint64(len(versions) + 1),
+		CodeHash: contractState.GetCodeHash(),
+		BlockNo:  stateSet.blockHeight,
+		TxHash:   stateSet.txHash,
+		Deployer: sender,
+	}
+	if err = putCodeVersion(contractState, version); err != nil {
+		return "", nil, stateSet.usedFee(), err
+	}
+
+	event := &types.Event{
+		ContractAddress: contractAddress,
+		EventIdx:        0,
+		EventName:       upgradeEventName,
+		JsonArgs: fmt.Sprintf(`{"version":%d,"codeHash":"%s"}`, version.Version,
+			enc.ToString(version.CodeHash)),
+	}
+	return "", []*types.Event{event}, stateSet.usedFee(), nil
+}
+
+// isContractOwner reports whether the account is the creator or the owner of
+// the contract.
+func isContractOwner(contractState *state.ContractState, account []byte) (bool, error) {
+	encoded := []byte(types.EncodeAddress(account))
+	for _, key := range []string{creatorKey, ownerKey} {
+		value, err := contractState.GetData([]byte(key))
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(value, encoded) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetCodeVersions returns the code versions of the contract, of which the last
+// one is the current code. The contract, which has never been redeployed, has
+// the deployed code only, whose block and tx are unknown.
+func GetCodeVersions(contractState *state.ContractState) ([]*types.CodeVersion, error) {
+	if len(contractState.GetCodeHash()) == 0 {
+		return nil, errNotContract
+	}
+	count, err := contractState.GetData([]byte(codeVersionKey))
+	if err != nil {
+		return nil, err
+	}
+	if len(count) == 0 {
+		creator, err := contractState.GetData([]byte(creatorKey))
+		if err != nil {
+			return nil, err
+		}
+		deployer, _ := types.DecodeAddress(string(creator))
+		return []*types.CodeVersion{{Version: 1, CodeHash: contractState.GetCodeHash(), Deployer: deployer}}, nil
+	}
+	n, err := strconv.ParseUint(string(count), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*types.CodeVersion, 0, n)
+	for i := uint64(1); i <= n; i++ {
+		data, err := contractState.GetData(codeVersionDataKey(i))
+		if err != nil {
+			return nil, err
+		}
+		version := &types.CodeVersion{}
+		if err := proto.Unmarshal(data, version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func putCodeVersion(contractState *state.ContractState, version *types.CodeVersion) error {
+	data, err := proto.Marshal(version)
+	if err != nil {
+		return err
+	}
+	if err := contractState.SetData(codeVersionDataKey(version.Version), data); err != nil {
+		return err
+	}
+	return contractState.SetData([]byte(codeVersionKey), []byte(strconv.FormatUint(version.Version, 10)))
+}
+
+func codeVersionDataKey(version uint64) []byte {
+	return []byte(codeVersionPrefix + strconv.FormatUint(version, 10))
+}
@@ -7,7 +7,9 @@
 #include <stdlib.h>
 #include <stdint.h>
 #include "vm.h"
+#include "util.h"
 #include "system_module.h"
+#include "_cgo_export.h"
 
 #define STATE_MAP_ID            "__state_map__"
 #define STATE_ARRAY_ID          "__state_array__"
@@ -21,27 +23,57 @@
 #define TYPE_LEN                "_len_"
 #define KEY_TYPE_NAME           "_key_type_"
 
+extern const int *getLuaExecContext(lua_State *L);
+
 static int state_map_delete(lua_State *L);
+static int state_map_pairs(lua_State *L);
+static int state_map_keys(lua_State *L);
+static int state_map_length(lua_State *L);
 static int state_array_append(lua_State *L);
 static int state_array_pairs(lua_State *L);
 
 /* map */
 
+/*
+ * The keys of an iterable map are kept in the key index of the contract
+ * storage in the order of the insertion, so that they can be enumerated by
+ * the pairs and the keys method.
+ */
 typedef struct {
     char *id;
     int key_type;
+    int iterable;
 } state_map_t;
 
 static int state_map(lua_State *L)
 {
-    state_map_t *m = lua_newuserdata(L, sizeof(state_map_t));   /* m */
+    int iterable = 0;
+    state_map_t *m;
+
+    if (!lua_isnoneornil(L, 1)) {
+        luaL_checktype(L, 1, LUA_TTABLE);                       /* opts */
+        lua_getfield(L, 1, "iterable");                         /* opts iterable */
+        iterable = lua_toboolean(L, -1);
+        lua_pop(L, 1);                                          /* opts */
+    }
+    m = lua_newuserdata(L, sizeof(state_map_t));                /* m */
     m->id = NULL;
     m->key_type = LUA_TNONE;
+    m->iterable = iterable;
     luaL_getmetatable(L, STATE_MAP_ID);                         /* m mt */
     lua_setmetatable(L, -2);                                    /* m */
     return 1;
 }
 
+static int state_map_is_method(state_map_t *m, const char *name)
+{
+    if (strcmp(name, "delete") == 0) {
+        return 1;
+    }
+    return m->iterable && (strcmp(name, "pairs") == 0 || strcmp(name, "keys") == 0 ||
+                           strcmp(name, "length") == 0);
+}
+
 static void state_map_check_index(lua_State *L, state_map_t *m)
 {
     /* m key */
@@ -79,6 +111,30 @@ static void state_map_push_key(lua_State *L, state_map_t *m)
     lua_concat(L, 3);                               /* m key value f id-key */
 }
 
+/* add or remove the key at the index 2 in the key index of the map */
+static void state_map_index_key(lua_State *L, state_map_t *m, int add)
+{
+    char *key, *errStr;
+    size_t len;
+    int *service = (int *)getLuaExecContext(L);
+
+    if (service == NULL) {
+        luaL_error(L, "cannot find execution context");
+    }
+    lua_pushvalue(L, 2);
+    key = (char *)lua_tolstring(L, -1, &len);
+    if (add) {
+        errStr = LuaAddMapKey(L, service, m->id, key, len);
+    } else {
+        errStr = LuaRemoveMapKey(L, service, m->id, key, len);
+    }
+    if (errStr != NULL) {
+        strPushAndRelease(L, errStr);
+        luaL_throwerror(L);
+    }
+    lua_pop(L, 1);
+}
+
 static int state_map_get(lua_State *L)
 {
     int key_type = LUA_TNONE;
@@ -88,8 +144,16 @@ static int state_map_get(lua_State *L)
     key_type = lua_type(L, 2);
     if (key_type == LUA_TSTRING) {
         const char *method = lua_tostring(L, 2);
-        if (method != NULL && strcmp(method, "delete") == 0) {
-            lua_pushcfunction(L, state_map_delete);
+        if (method != NULL && state_map_is_method(m, method)) {
+            if (strcmp(method, "delete") == 0) {
+                lua_pushcfunction(L, state_map_delete);
+            } else if (strcmp(method, "pairs") == 0) {
+                lua_pushcfunction(L, state_map_pairs);
+            } else if (strcmp(method, "keys") == 0) {
+                lua_pushcfunction(L, state_map_keys);
+            } else {
+                lua_pushcfunction(L, state_map_length);
+            }
             return 1;
         }
     }
@@ -114,11 +178,14 @@ static int state_map_set(lua_State *L)
     key_type = lua_type(L, 2);
     if (key_type == LUA_TSTRING) {
         const char *method = lua_tostring(L, 2);
-        if (method != NULL && strcmp(method, "delete") == 0) {
-            luaL_error(L, "can't use " LUA_QL("delete") " as a key");
+        if (method != NULL && state_map_is_method(m, method)) {
+            luaL_error(L, "can't use " LUA_QS " as a key", method);
         }
     }
     state_map_check_index(L, m);
+    if (m->iterable && lua_isnil(L, 3)) {
+        return state_map_delete(L);
+    }
     if (m->key_type == LUA_TNONE) {
         lua_pushcfunction(L, setItemWithPrefix);    /* m key f */
         lua_pushstring(L, m->id);                   /* m key f id */
@@ -133,6 +200,9 @@ static int state_map_set(lua_State *L)
     lua_pushvalue(L, 3);                            /* m key value f id-key value */
     lua_pushstring(L, STATE_VAR_KEY_PREFIX);        /* m key value f id-key value prefix */
     lua_call(L, 3, 0);                              /* t key value */
+    if (m->iterable) {
+        state_map_index_key(L, m, 1);
+    }
     return 0;
 }
 
@@ -145,9 +215,138 @@ static int state_map_delete(lua_State *L)
     state_map_push_key(L, m);                       /* m key f id-key */
     lua_pushstring(L, STATE_VAR_KEY_PREFIX);        /* m key f id-key prefix */
     lua_call(L, 2, 1);                              /* m key rv */
+    if (m->iterable) {
+        state_map_index_key(L, m, 0);
+    }
     return 0;
 }
 
+static int state_map_load_key_type(lua_State *L, state_map_t *m)
+{
+    int key_type = m->key_type;
+
+    if (key_type == LUA_TNONE) {
+        lua_pushcfunction(L, getItemWithPrefix);    /* f */
+        lua_pushstring(L, m->id);                   /* f id */
+        lua_pushstring(L, STATE_VAR_META_TYPE);     /* f id prefix */
+        lua_call(L, 2, 1);                          /* t */
+        if (!lua_isnil(L, -1)) {
+            key_type = luaL_checkint(L, -1);
+        }
+        lua_pop(L, 1);
+    }
+    return key_type;
+}
+
+/* push the table of the keys following the cursor at the index cursor */
+static void state_map_push_keys(lua_State *L, state_map_t *m, int cursor)
+{
+    int i, key_type, limit;
+    char *p, *end, *cursor_key = NULL;
+    size_t cursor_len = 0;
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaGetMapKeys_return ret;
+
+    if (service == NULL) {
+        luaL_error(L, "cannot find execution context");
+    }
+    if (!lua_isnoneornil(L, cursor)) {
+        int type = lua_type(L, cursor);
+        if (type != LUA_TNUMBER && type != LUA_TSTRING) {
+            luaL_typerror(L, cursor, "number or string");
+        }
+        lua_pushvalue(L, cursor);
+        cursor_key = (char *)lua_tolstring(L, -1, &cursor_len);
+    }
+    limit = luaL_optint(L, cursor + 1, 0);
+    luaL_argcheck(L, limit >= 0, cursor + 1, "the limit must not be negative");
+    key_type = state_map_load_key_type(L, m);
+
+    ret = LuaGetMapKeys(L, service, m->id, cursor_key, cursor_len, limit);
+    if (ret.r2 != NULL) {
+        strPushAndRelease(L, ret.r2);
+        luaL_throwerror(L);
+    }
+    lua_newtable(L);                                /* keys */
+    for (p = ret.r0, end = p + ret.r1, i = 1; p + 4 <= end; i++) {
+        const unsigned char *l = (const unsigned char *)p;
+        size_t len = ((size_t)l[0] << 24) | ((size_t)l[1] << 16) | ((size_t)l[2] << 8) | l[3];
+        p += 4;
+        lua_pushlstring(L, p, len);                 /* keys key */
+        if (key_type == LUA_TNUMBER) {
+            lua_Number n = lua_tonumber(L, -1);
+            lua_pop(L, 1);
+            lua_pushnumber(L, n);
+        }
+        lua_rawseti(L, -2, i);                      /* keys */
+        p += len;
+    }
+    free(ret.r0);
+    if (cursor_key != NULL) {
+        lua_remove(L, -2);                          /* remove the copy of the cursor */
+    }
+}
+
+static int state_map_keys(lua_State *L)
+{
+    /* m [cursor [limit]] */
+    state_map_t *m = luaL_checkudata(L, 1, STATE_MAP_ID);
+    state_map_push_keys(L, m, 2);                   /* m cursor limit keys */
+    return 1;
+}
+
+static int state_map_iter(lua_State *L)
+{
+    int i = lua_tointeger(L, lua_upvalueindex(3));
+
+    for (;;) {
+        lua_rawgeti(L, lua_upvalueindex(2), ++i);   /* key */
+        if (lua_isnil(L, -1)) {
+            return 0;
+        }
+        lua_pushinteger(L, i);
+        lua_replace(L, lua_upvalueindex(3));
+        lua_pushcfunction(L, state_map_get);        /* key f */
+        lua_pushvalue(L, lua_upvalueindex(1));      /* key f m */
+        lua_pushvalue(L, -3);                       /* key f m key */
+        lua_call(L, 2, 1);                          /* key value */
+        /* skip the entry deleted during the iteration */
+        if (!lua_isnil(L, -1)) {
+            return 2;
+        }
+        lua_pop(L, 2);
+    }
+}
+
+static int state_map_pairs(lua_State *L)
+{
+    /* m [cursor [limit]] */
+    state_map_t *m = luaL_checkudata(L, 1, STATE_MAP_ID);
+    lua_pushvalue(L, 1);                            /* m cursor limit m */
+    state_map_push_keys(L, m, 2);                   /* m cursor limit m keys */
+    lua_pushinteger(L, 0);                          /* m cursor limit m keys 0 */
+    lua_pushcclosure(L, state_map_iter, 3);         /* m cursor limit iter */
+    return 1;
+}
+
+static int state_map_length(lua_State *L)
+{
+    state_map_t *m = luaL_checkudata(L, 1, STATE_MAP_ID);
+    int *service = (int *)getLuaExecContext(L);
+    struct LuaGetMapLength_return ret;
+
+    if (service == NULL) {
+        luaL_error(L, "cannot find execution context");
+    }
+    ret = LuaGetMapLength(L, service, m->id);
+    if (ret.r1 != NULL) {
+        strPushAndRelease(L, ret.r1);
+        luaL_throwerror(L);
+    }
+    lua_pushnumber(L, ret.r0);
+    return 1;
+}
+
 static int state_map_gc(lua_State *L)
 {
     state_map_t *m = luaL_checkudata(L, 1, STATE_MAP_ID);
@@ -21,9 +21,10 @@ import (
 )
 
 var (
-	ErrDBOpen = errors.New("failed to open the sql database")
-	ErrUndo   = errors.New("failed to undo the sql database")
-	ErrFindRp = errors.New("cannot find a recover point")
+	ErrDBOpen  = errors.New("failed to open the sql database")
+	ErrUndo    = errors.New("failed to undo the sql database")
+	ErrFindRp  = errors.New("cannot find a recover point")
+	ErrDBInUse = errors.New("the sql database is in use by a block")
 
 	database = &Database{}
 	load     sync.Once
@@ -146,6 +147,36 @@ func BeginTx(dbName string, rp uint64) (Tx, error) {
 	return db.beginTx(rp)
 }
 
+// beginSimulationTx begins the tx of a simulated tx on dbName. Nothing written
+// by the tx is kept, since it is ended by endSimulationTx.
+func beginSimulationTx(dbName string, rp uint64) (Tx, error) {
+	db, err := conn(dbName)
+	if err != nil {
+		return nil, err
+	}
+	if db.tx != nil {
+		return nil, ErrDBInUse
+	}
+	return db.beginTx(rp)
+}
+
+// endSimulationTx rolls back the tx of a simulated tx and closes its
+// database.
+func endSimulationTx(tx Tx) error {
+	wtx, ok := tx.(*WritableTx)
+	if !ok {
+		return tx.Rollback()
+	}
+	db := wtx.db
+	err := wtx.Rollback()
+	db.tx = nil
+	if cErr := db.close(); err == nil {
+		err = cErr
+	}
+	delete(database.DBs, db.name)
+	return err
+}
+
 func BeginReadOnly(dbName string, rp uint64) (Tx, error) {
 	db, err := readOnlyConn(dbName)
 	if err != nil {
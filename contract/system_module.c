@@ -218,6 +218,40 @@ static int getCreator(lua_State *L)
 	return 1;
 }
 
+static int getOwner(lua_State *L)
+{
+	int *service = (int *)getLuaExecContext(L);
+	struct LuaGetDB_return ret;
+
+	if (service == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	ret = LuaGetDB(L, service, "Owner", 0);
+	if (ret.r1 != NULL) {
+	    strPushAndRelease(L, ret.r1);
+		luaL_throwerror(L);
+	}
+	if (ret.r0 == NULL)
+		return 0;
+	strPushAndRelease(L, ret.r0);
+	return 1;
+}
+
+static int setOwner(lua_State *L)
+{
+	int *service = (int *)getLuaExecContext(L);
+	char *errStr;
+
+	if (service == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	if ((errStr = LuaSetOwner(L, service, (char *)luaL_checkstring(L, 1))) != NULL) {
+		strPushAndRelease(L, errStr);
+		luaL_throwerror(L);
+	}
+	return 0;
+}
+
 static int getAmount(lua_State *L)
 {
 	int *service = (int *)getLuaExecContext(L);
@@ -460,6 +494,8 @@ static const luaL_Reg sys_lib[] = {
 	{"getItem", getItem},
 	{"getSender", getSender},
 	{"getCreator", getCreator},
+	{"getOwner", getOwner},
+	{"setOwner", setOwner},
 	{"getTxhash", getTxhash},
 	{"getBlockheight", getBlockHeight},
 	{"getTimestamp", getTimestamp},
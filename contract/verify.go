@@ -0,0 +1,60 @@
+package contract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo/pkg/luac"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	errBytecodeMismatch = errors.New("the bytecode of the source doesn't match the contract")
+	errABIMismatch      = errors.New("the abi of the source doesn't match the contract")
+	errSourceTooLarge   = fmt.Errorf("the source is larger than %d bytes", types.TxMaxSize)
+)
+
+// VerifySource compiles the source in the same way as the deployment of the
+// source, and compares the bytecode and the ABI with the code of the contract.
+func VerifySource(contractState *state.ContractState, source string) error {
+	// a deployed source can't be larger than a tx
+	if len(source) > types.TxMaxSize {
+		return errSourceTooLarge
+	}
+	if len(contractState.GetCodeHash()) == 0 {
+		return errNotContract
+	}
+	deployed := getContract(contractState, nil)
+	if deployed == nil {
+		return errors.New("cannot load contract")
+	}
+	abi, err := GetABI(contractState)
+	if err != nil {
+		return err
+	}
+
+	L := luac.NewLState()
+	if L == nil {
+		return newVmStartError()
+	}
+	defer luac.CloseLState(L)
+	code, err := luac.Compile(L, source)
+	if err != nil {
+		return fmt.Errorf("compile error: %s", err.Error())
+	}
+
+	if !bytes.Equal(getContract(contractState, code), deployed) {
+		return errBytecodeMismatch
+	}
+	compiledABI, err := decodeABI(code)
+	if err != nil {
+		return err
+	}
+	if !proto.Equal(compiledABI, abi) {
+		return errABIMismatch
+	}
+	return nil
+}
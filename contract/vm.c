@@ -6,6 +6,7 @@
 #include "db_module.h"
 #include "state_module.h"
 #include "crypto_module.h"
+#include "abi_module.h"
 #include "util.h"
 #include "lgmp.h"
 #include "_cgo_export.h"
@@ -22,6 +23,7 @@ static void preloadModules(lua_State *L)
 	luaopen_state(L);
 	luaopen_json(L);
 	luaopen_crypto(L);
+	luaopen_abi_annotations(L);
 	luaopen_gmp(L);
 	if (!IsPublic()) {
         luaopen_db(L);
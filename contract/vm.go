@@ -87,6 +87,7 @@ type StateSet struct {
 	node              string
 	confirmed         bool
 	isQuery           bool
+	isSimulation      bool
 	prevBlockHash     []byte
 	service           C.int
 	callState         map[types.AccountID]*CallState
@@ -96,6 +97,9 @@ type StateSet struct {
 	events            []*types.Event
 	eventCount        int32
 	callDepth         int32
+	gasLimit          uint64
+	gasPrice          *big.Int
+	usedGas           uint64
 }
 
 type recoveryEntry struct {
@@ -193,6 +197,9 @@ func (s *StateSet) usedFee() *big.Int {
 	if fee.IsZeroFee() {
 		return zeroFee
 	}
+	if s.isGasMetered() {
+		return fee.GasFee(s.totalGas(), s.gasPrice)
+	}
 	size := fee.PaymentDataSize(s.dbUpdateTotalSize)
 	return new(big.Int).Mul(big.NewInt(size), fee.AerPerByte)
 }
@@ -507,7 +514,11 @@ func (ce *Executor) commitCalledContract() error {
 	var err error
 	for k, v := range stateSet.callState {
 		if v.tx != nil {
-			err = v.tx.Release()
+			if stateSet.isSimulation {
+				err = v.tx.RollbackToSavepoint()
+			} else {
+				err = v.tx.Release()
+			}
 			if err != nil {
 				return newDbSystemError(err)
 			}
@@ -553,6 +564,19 @@ func (ce *Executor) rollbackToSavepoint() error {
 	return nil
 }
 
+// endSimulation rolls back the sql database txs of a simulated tx.
+func endSimulation(stateSet *StateSet) {
+	for _, v := range stateSet.callState {
+		if v.tx == nil {
+			continue
+		}
+		if err := endSimulationTx(v.tx); err != nil {
+			ctrLog.Error().Err(err).Msg("end the simulation tx")
+		}
+		v.tx = nil
+	}
+}
+
 func (ce *Executor) close() {
 	if ce != nil {
 		if ce.stateSet != nil {
@@ -601,9 +625,11 @@ func Call(contractState *state.ContractState, code, contractAddress []byte,
 	curStateSet[stateSet.service] = stateSet
 	ce := newExecutor(contract, contractAddress, stateSet, &ci, stateSet.curContract.amount, false, contractState)
 	defer ce.close()
-	ce.setCountHook(callMaxInstLimit)
+	instLimit := stateSet.instLimit()
+	ce.setCountHook(instLimit)
 
 	ce.call(nil)
+	ce.meterGas(instLimit)
 	err = ce.err
 	if err != nil {
 		if dbErr := ce.rollbackToSavepoint(); dbErr != nil {
@@ -653,6 +679,7 @@ func PreCall(ce *Executor, bs *state.BlockState, sender *state.V, contractState
 
 	curStateSet[stateSet.service] = stateSet
 	ce.call(nil)
+	ce.meterGas(stateSet.instLimit())
 	err = ce.err
 	if err == nil {
 		err = ce.commitCalledContract()
@@ -681,13 +708,16 @@ func PreloadEx(bs *state.BlockState, contractState *state.ContractState, contrac
 	var ci types.CallInfo
 	var contractCode []byte
 
+	// the code is cached by its hash, since a redeployed contract has the
+	// new code under the same account
+	codeID := types.ToHashID(contractState.GetCodeHash())
 	if bs != nil {
-		contractCode = bs.CodeMap[contractAid]
+		contractCode = bs.CodeMap[codeID]
 	}
 	if contractCode == nil {
 		contractCode = getContract(contractState, nil)
 		if contractCode != nil && bs != nil {
-			bs.CodeMap[contractAid] = contractCode
+			bs.CodeMap[codeID] = contractCode
 		}
 	}
 
@@ -707,7 +737,7 @@ func PreloadEx(bs *state.BlockState, contractState *state.ContractState, contrac
 		ctrLog.Debug().Str("abi", string(code)).Str("contract", types.EncodeAddress(contractAddress)).Msg("preload")
 	}
 	ce := newExecutor(contractCode, contractAddress, stateSet, &ci, stateSet.curContract.amount, false, contractState)
-	ce.setCountHook(callMaxInstLimit)
+	ce.setCountHook(stateSet.instLimit())
 
 	return ce, nil
 
@@ -757,7 +787,7 @@ func Create(contractState *state.ContractState, code, contractAddress []byte,
 	if err != nil {
 		return "", nil, stateSet.usedFee(), err
 	}
-	err = contractState.SetData([]byte("Creator"), []byte(types.EncodeAddress(stateSet.curContract.sender)))
+	err = contractState.SetData([]byte(creatorKey), []byte(types.EncodeAddress(stateSet.curContract.sender)))
 	if err != nil {
 		return "", nil, stateSet.usedFee(), err
 	}
@@ -784,9 +814,11 @@ func Create(contractState *state.ContractState, code, contractAddress []byte,
 		return "", nil, stateSet.usedFee(), nil
 	}
 	defer ce.close()
-	ce.setCountHook(callMaxInstLimit)
+	instLimit := stateSet.instLimit()
+	ce.setCountHook(instLimit)
 
 	ce.call(nil)
+	ce.meterGas(instLimit)
 	err = ce.err
 	if err != nil {
 		logger.Warn().Msg("constructor is failed")
@@ -834,6 +866,9 @@ func Query(contractAddress []byte, bs *state.BlockState, cdb ChainAccessor, cont
 	contract := getContract(contractState, nil)
 	if contract != nil {
 		err = getCallInfo(&ci, queryInfo, contractAddress)
+		if err == nil {
+			err = encodeCallInfo(contractState, &ci)
+		}
 	} else {
 		addr := types.EncodeAddress(contractAddress)
 		ctrLog.Warn().Str("error", "not found contract").Str("contract", addr).Msg("query")
@@ -891,11 +926,15 @@ func GetABI(contractState *state.ContractState) (*types.ABI, error) {
 	if err != nil {
 		return nil, err
 	}
-	valLen := len(val)
-	if valLen == 0 {
+	if len(val) == 0 {
 		return nil, errors.New("cannot find contract")
 	}
-	if valLen <= 4 {
+	return decodeABI(val)
+}
+
+// decodeABI returns the ABI following the bytecode of the code.
+func decodeABI(val []byte) (*types.ABI, error) {
+	if len(val) <= 4 {
 		return nil, errors.New("cannot find abi")
 	}
 	l := codeLength(val)
@@ -909,6 +948,16 @@ func GetABI(contractState *state.ContractState) (*types.ABI, error) {
 	return abi, nil
 }
 
+// encodeCallInfo validates and encodes the arguments by the types of the
+// function in the ABI of the contract.
+func encodeCallInfo(contractState *state.ContractState, ci *types.CallInfo) error {
+	abi, err := GetABI(contractState)
+	if err != nil {
+		return nil
+	}
+	return abi.EncodeCallInfo(ci)
+}
+
 func codeLength(val []byte) uint32 {
 	return binary.LittleEndian.Uint32(val[0:])
 }
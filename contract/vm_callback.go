@@ -12,6 +12,7 @@ package contract
 import "C"
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -22,14 +23,20 @@ import (
 	"strings"
 	"unsafe"
 
-	luacUtil "github.com/aergoio/aergo/cmd/aergoluac/util"
 	"github.com/aergoio/aergo/contract/name"
 	"github.com/aergoio/aergo/contract/system"
+	"github.com/aergoio/aergo/fee"
 	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/pkg/luac"
+	"github.com/aergoio/aergo/pkg/trie"
 	"github.com/aergoio/aergo/state"
 	"github.com/aergoio/aergo/types"
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/minio/sha256-simd"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
 )
 
 var mulAergo, mulGaer, zeroBig *big.Int
@@ -62,6 +69,9 @@ func LuaSetDB(L *LState, service *C.int, key *C.char, value *C.char) *C.char {
 	if stateSet.isQuery == true {
 		return C.CString("[System.LuaSetDB] set not permitted in query")
 	}
+	if errMsg := useGas(L, stateSet, fee.SetDbGas); errMsg != nil {
+		return errMsg
+	}
 	val := []byte(C.GoString(value))
 	if err := stateSet.curContract.callState.ctrState.SetData([]byte(C.GoString(key)), val); err != nil {
 		return C.CString(err.Error())
@@ -73,12 +83,42 @@ func LuaSetDB(L *LState, service *C.int, key *C.char, value *C.char) *C.char {
 	return nil
 }
 
+//export LuaSetOwner
+func LuaSetOwner(L *LState, service *C.int, owner *C.char) *C.char {
+	stateSet := curStateSet[*service]
+	if stateSet == nil {
+		return C.CString("[System.LuaSetOwner] contract state not found")
+	}
+	if stateSet.isQuery == true {
+		return C.CString("[System.LuaSetOwner] set not permitted in query")
+	}
+	if errMsg := useGas(L, stateSet, fee.SetDbGas); errMsg != nil {
+		return errMsg
+	}
+	ownerAddress := C.GoString(owner)
+	if address, err := types.DecodeAddress(ownerAddress); err != nil || len(address) != types.AddressLength {
+		return C.CString("[System.LuaSetOwner] invalid owner address: " + ownerAddress)
+	}
+	val := []byte(ownerAddress)
+	if err := stateSet.curContract.callState.ctrState.SetData([]byte(ownerKey), val); err != nil {
+		return C.CString(err.Error())
+	}
+	if err := addUpdateSize(stateSet, int64(types.HashIDLength+len(val))); err != nil {
+		C.luaL_setuncatchablerror(L)
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
 //export LuaGetDB
 func LuaGetDB(L *LState, service *C.int, key *C.char, blkno *C.char) (*C.char, *C.char) {
 	stateSet := curStateSet[*service]
 	if stateSet == nil {
 		return nil, C.CString("[System.LuaGetDB] contract state not found")
 	}
+	if errMsg := useGas(L, stateSet, fee.GetDbGas); errMsg != nil {
+		return nil, errMsg
+	}
 	if blkno != nil {
 		bigNo, _ := new(big.Int).SetString(strings.TrimSpace(C.GoString(blkno)), 10)
 		if bigNo == nil || bigNo.Sign() < 0 {
@@ -139,6 +179,9 @@ func LuaDelDB(L *LState, service *C.int, key *C.char) *C.char {
 	if stateSet.isQuery {
 		return C.CString("[System.LuaDelDB] delete not permitted in query")
 	}
+	if errMsg := useGas(L, stateSet, fee.DelDbGas); errMsg != nil {
+		return errMsg
+	}
 	if err := stateSet.curContract.callState.ctrState.DeleteData([]byte(C.GoString(key))); err != nil {
 		return C.CString(err.Error())
 	}
@@ -149,6 +192,103 @@ func LuaDelDB(L *LState, service *C.int, key *C.char) *C.char {
 	return nil
 }
 
+//export LuaAddMapKey
+func LuaAddMapKey(L *LState, service *C.int, index *C.char, key *C.char, keyLen C.size_t) *C.char {
+	stateSet := curStateSet[*service]
+	if stateSet == nil {
+		return C.CString("[System.LuaAddMapKey] contract state not found")
+	}
+	if stateSet.isQuery {
+		return C.CString("[System.LuaAddMapKey] set not permitted in query")
+	}
+	size, err := stateSet.curContract.callState.ctrState.AddIndexedKey([]byte(C.GoString(index)),
+		C.GoBytes(unsafe.Pointer(key), C.int(keyLen)))
+	if err != nil {
+		return C.CString(err.Error())
+	}
+	return useKeyIndexGas(L, stateSet, size)
+}
+
+//export LuaRemoveMapKey
+func LuaRemoveMapKey(L *LState, service *C.int, index *C.char, key *C.char, keyLen C.size_t) *C.char {
+	stateSet := curStateSet[*service]
+	if stateSet == nil {
+		return C.CString("[System.LuaRemoveMapKey] contract state not found")
+	}
+	if stateSet.isQuery {
+		return C.CString("[System.LuaRemoveMapKey] delete not permitted in query")
+	}
+	size, err := stateSet.curContract.callState.ctrState.RemoveIndexedKey([]byte(C.GoString(index)),
+		C.GoBytes(unsafe.Pointer(key), C.int(keyLen)))
+	if err != nil {
+		return C.CString(err.Error())
+	}
+	return useKeyIndexGas(L, stateSet, size)
+}
+
+func useKeyIndexGas(L *LState, stateSet *StateSet, updateSize int64) *C.char {
+	if updateSize == 0 {
+		return nil
+	}
+	if errMsg := useGas(L, stateSet, fee.SetDbGas); errMsg != nil {
+		return errMsg
+	}
+	if err := addUpdateSize(stateSet, updateSize); err != nil {
+		C.luaL_setuncatchablerror(L)
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
+//export LuaGetMapKeys
+func LuaGetMapKeys(L *LState, service *C.int, index *C.char, cursor *C.char, cursorLen C.size_t,
+	limit C.int) (unsafe.Pointer, C.int, *C.char) {
+	stateSet := curStateSet[*service]
+	if stateSet == nil {
+		return nil, 0, C.CString("[System.LuaGetMapKeys] contract state not found")
+	}
+	// the keys to be read are bounded by the remaining gas or instructions
+	maxKeys := int(C.luaL_instcount(L)/fee.GetDbGas) + 1
+	if limit <= 0 || int(limit) > maxKeys {
+		limit = C.int(maxKeys)
+	}
+	var after []byte
+	if cursor != nil {
+		after = C.GoBytes(unsafe.Pointer(cursor), C.int(cursorLen))
+	}
+	keys, err := stateSet.curContract.callState.ctrState.GetIndexedKeys([]byte(C.GoString(index)), after, int(limit))
+	if err != nil {
+		return nil, 0, C.CString("[System.LuaGetMapKeys] " + err.Error())
+	}
+	if errMsg := chargeGas(L, service, uint64(len(keys)+1)*fee.GetDbGas); errMsg != nil {
+		return nil, 0, errMsg
+	}
+	// each key is prefixed by its length, since it may have NUL
+	var buf []byte
+	l := make([]byte, 4)
+	for _, key := range keys {
+		binary.BigEndian.PutUint32(l, uint32(len(key)))
+		buf = append(append(buf, l...), key...)
+	}
+	return C.CBytes(buf), C.int(len(buf)), nil
+}
+
+//export LuaGetMapLength
+func LuaGetMapLength(L *LState, service *C.int, index *C.char) (C.double, *C.char) {
+	stateSet := curStateSet[*service]
+	if stateSet == nil {
+		return 0, C.CString("[System.LuaGetMapLength] contract state not found")
+	}
+	if errMsg := useGas(L, stateSet, fee.GetDbGas); errMsg != nil {
+		return 0, errMsg
+	}
+	count, err := stateSet.curContract.callState.ctrState.GetIndexedKeyCount([]byte(C.GoString(index)))
+	if err != nil {
+		return 0, C.CString(err.Error())
+	}
+	return C.double(count), nil
+}
+
 func getCallState(stateSet *StateSet, aid types.AccountID) (*CallState, error) {
 	callState := stateSet.callState[aid]
 	if callState == nil {
@@ -204,6 +344,9 @@ func LuaCallContract(L *LState, service *C.int, contractId *C.char, fname *C.cha
 	if stateSet == nil {
 		return -1, C.CString("[Contract.LuaCallContract] contract state not found")
 	}
+	if errMsg := useGas(L, stateSet, fee.CallGas); errMsg != nil {
+		return -1, errMsg
+	}
 	contractAddress := C.GoString(contractId)
 	cid, err := getAddressNameResolved(contractAddress, stateSet.bs)
 	if err != nil {
@@ -291,6 +434,9 @@ func LuaDelegateCallContract(L *LState, service *C.int, contractId *C.char,
 	if stateSet == nil {
 		return -1, C.CString("[Contract.LuaDelegateCallContract] contract state not found")
 	}
+	if errMsg := useGas(L, stateSet, fee.DelegateCallGas); errMsg != nil {
+		return -1, errMsg
+	}
 	cid, err := getAddressNameResolved(contractIdStr, stateSet.bs)
 	if err != nil {
 		return -1, C.CString("[Contract.LuaDelegateCallContract] invalid contractId: " + err.Error())
@@ -358,6 +504,9 @@ func LuaSendAmount(L *LState, service *C.int, contractId *C.char, amount *C.char
 	if stateSet == nil {
 		return C.CString("[Contract.LuaSendAmount] contract state not found")
 	}
+	if errMsg := useGas(L, stateSet, fee.SendGas); errMsg != nil {
+		return errMsg
+	}
 	amountBig, err := transformAmount(C.GoString(amount))
 	if err != nil {
 		return C.CString("[Contract.LuaSendAmount] invalid amount: " + err.Error())
@@ -545,6 +694,9 @@ func LuaClearRecovery(L *LState, service *C.int, start int, error bool) *C.char
 //export LuaGetBalance
 func LuaGetBalance(L *LState, service *C.int, contractId *C.char) (*C.char, *C.char) {
 	stateSet := curStateSet[*service]
+	if errMsg := useGas(L, stateSet, fee.GetBalanceGas); errMsg != nil {
+		return nil, errMsg
+	}
 	if contractId == nil {
 		return C.CString(stateSet.curContract.callState.ctrState.GetBalanceBigInt().String()), nil
 	}
@@ -629,8 +781,12 @@ func LuaGetDbHandle(service *C.int) *C.sqlite3 {
 	var err error
 
 	aid := types.ToAccountID(curContract.contractId)
-	if stateSet.isQuery == true {
+	readOnly := stateSet.isQuery
+	if readOnly {
 		tx, err = BeginReadOnly(aid.String(), curContract.rp)
+	} else if stateSet.isSimulation {
+		// the changes of a simulated tx are rolled back by endSimulationTx
+		tx, err = beginSimulationTx(aid.String(), curContract.rp)
 	} else {
 		tx, err = BeginTx(aid.String(), curContract.rp)
 	}
@@ -638,8 +794,11 @@ func LuaGetDbHandle(service *C.int) *C.sqlite3 {
 		logger.Error().Err(err).Msg("Begin SQL Transaction")
 		return nil
 	}
-	if stateSet.isQuery == false {
-		err = tx.Savepoint()
+	if !readOnly {
+		err = joinBatch(int(stateSet.service), aid.String(), tx)
+		if err == nil {
+			err = tx.Savepoint()
+		}
 		if err != nil {
 			logger.Error().Err(err).Msg("Begin SQL Transaction")
 			return nil
@@ -752,6 +911,149 @@ func LuaECVerify(L *LState, msg *C.char, sig *C.char, addr *C.char) (C.int, *C.c
 	return C.int(0), nil
 }
 
+func luaCryptoHash(L *LState, service *C.int, fname string, arg unsafe.Pointer, argLen C.int,
+	hash func([]byte) []byte) (*C.char, *C.char) {
+	data := C.GoBytes(arg, argLen)
+	if checkHexString(string(data)) {
+		var err error
+		data, err = hex.DecodeString(string(data[2:]))
+		if err != nil {
+			return nil, C.CString("[Contract." + fname + "] hex decoding error: " + err.Error())
+		}
+	}
+	if errMsg := chargeGas(L, service, fee.HashDataGas(len(data))); errMsg != nil {
+		return nil, errMsg
+	}
+	return C.CString("0x" + hex.EncodeToString(hash(data))), nil
+}
+
+//export LuaCryptoKeccak256
+func LuaCryptoKeccak256(L *LState, service *C.int, arg unsafe.Pointer, argLen C.int) (*C.char, *C.char) {
+	return luaCryptoHash(L, service, "LuaCryptoKeccak256", arg, argLen, func(data []byte) []byte {
+		h := sha3.NewLegacyKeccak256()
+		h.Write(data)
+		return h.Sum(nil)
+	})
+}
+
+//export LuaCryptoRipemd160
+func LuaCryptoRipemd160(L *LState, service *C.int, arg unsafe.Pointer, argLen C.int) (*C.char, *C.char) {
+	return luaCryptoHash(L, service, "LuaCryptoRipemd160", arg, argLen, func(data []byte) []byte {
+		h := ripemd160.New()
+		h.Write(data)
+		return h.Sum(nil)
+	})
+}
+
+//export LuaCryptoBlake2b
+func LuaCryptoBlake2b(L *LState, service *C.int, arg unsafe.Pointer, argLen C.int) (*C.char, *C.char) {
+	return luaCryptoHash(L, service, "LuaCryptoBlake2b", arg, argLen, func(data []byte) []byte {
+		h := blake2b.Sum256(data)
+		return h[:]
+	})
+}
+
+//export LuaEd25519Verify
+func LuaEd25519Verify(L *LState, service *C.int, msg *C.char, sig *C.char, pubkey *C.char) (C.int, *C.char) {
+	bMsg, err := decodeHex(C.GoString(msg))
+	if err != nil {
+		return -1, C.CString("[Contract.LuaEd25519Verify] invalid message format: " + err.Error())
+	}
+	bSig, err := decodeHex(C.GoString(sig))
+	if err != nil {
+		return -1, C.CString("[Contract.LuaEd25519Verify] invalid signature format: " + err.Error())
+	}
+	bPub, err := decodeHex(C.GoString(pubkey))
+	if err != nil {
+		return -1, C.CString("[Contract.LuaEd25519Verify] invalid public key format: " + err.Error())
+	}
+	if len(bPub) != ed25519.PublicKeySize {
+		return -1, C.CString("[Contract.LuaEd25519Verify] invalid public key length")
+	}
+	if errMsg := chargeGas(L, service, fee.Ed25519VerifyGas); errMsg != nil {
+		return -1, errMsg
+	}
+	if ed25519.Verify(ed25519.PublicKey(bPub), bMsg, bSig) {
+		return C.int(1), nil
+	}
+	return C.int(0), nil
+}
+
+//export LuaECRecover
+func LuaECRecover(L *LState, service *C.int, msg *C.char, sig *C.char) (*C.char, *C.char) {
+	bMsg, err := decodeHex(C.GoString(msg))
+	if err != nil {
+		return nil, C.CString("[Contract.LuaECRecover] invalid message format: " + err.Error())
+	}
+	bSig, err := decodeHex(C.GoString(sig))
+	if err != nil {
+		return nil, C.CString("[Contract.LuaECRecover] invalid signature format: " + err.Error())
+	}
+	// the ethereum style signature of r, s and v, where v is 0, 1, 27 or 28
+	if len(bSig) != 65 {
+		return nil, C.CString("[Contract.LuaECRecover] invalid signature length")
+	}
+	v := bSig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, C.CString("[Contract.LuaECRecover] invalid recovery id")
+	}
+	if errMsg := chargeGas(L, service, fee.ECRecoverGas); errMsg != nil {
+		return nil, errMsg
+	}
+	btcsig := make([]byte, 65)
+	btcsig[0] = v + 27
+	copy(btcsig[1:], bSig)
+	pub, _, err := btcec.RecoverCompact(btcec.S256(), btcsig, bMsg)
+	if err != nil {
+		return nil, C.CString("[Contract.LuaECRecover] error recoverCompact: " + err.Error())
+	}
+	return C.CString("0x" + hex.EncodeToString(pub.SerializeUncompressed())), nil
+}
+
+//export LuaVerifyProof
+func LuaVerifyProof(L *LState, service *C.int, key, value, root, auditPath *C.char) (C.int, *C.char) {
+	// the inclusion proof of the trie key and the value, which is the hash of
+	// the stored data, in the pkg/trie sparse merkle trie such as the state and
+	// the storage trie. The audit path is the nodes of the uncompressed proof.
+	var hashes [3][]byte
+	for i, arg := range []*C.char{key, value, root} {
+		h, err := decodeHex(C.GoString(arg))
+		if err != nil {
+			return -1, C.CString("[Contract.LuaVerifyProof] invalid hash format: " + err.Error())
+		}
+		if len(h) != trie.HashLength {
+			return -1, C.CString("[Contract.LuaVerifyProof] invalid hash length")
+		}
+		hashes[i] = h
+	}
+	var ap [][]byte
+	if path := C.GoString(auditPath); len(path) > 0 {
+		for _, node := range strings.Split(path, ",") {
+			h, err := decodeHex(node)
+			if err != nil {
+				return -1, C.CString("[Contract.LuaVerifyProof] invalid proof format: " + err.Error())
+			}
+			if len(h) != trie.HashLength && !bytes.Equal(h, trie.DefaultLeaf) {
+				return -1, C.CString("[Contract.LuaVerifyProof] invalid proof node length")
+			}
+			ap = append(ap, h)
+		}
+	}
+	if len(ap) > trie.HashLength*8 {
+		return -1, C.CString("[Contract.LuaVerifyProof] too long proof")
+	}
+	if errMsg := chargeGas(L, service, uint64(len(ap)+1)*fee.MerkleNodeGas); errMsg != nil {
+		return -1, errMsg
+	}
+	if trie.NewTrie(hashes[2], common.Hasher, nil).VerifyInclusion(ap, hashes[0], hashes[1]) {
+		return C.int(1), nil
+	}
+	return C.int(0), nil
+}
+
 func transformAmount(amountStr string) (*big.Int, error) {
 	var ret *big.Int
 	var prev int
@@ -840,6 +1142,9 @@ func LuaDeployContract(
 	if stateSet.isQuery == true {
 		return -1, C.CString("[Contract.LuaDeployContract]send not permitted in query")
 	}
+	if errMsg := useGas(L, stateSet, fee.DeployGas); errMsg != nil {
+		return -1, errMsg
+	}
 	bs := stateSet.bs
 
 	// get code
@@ -861,12 +1166,12 @@ func LuaDeployContract(
 	}
 
 	if len(code) == 0 {
-		l := luacUtil.NewLState()
+		l := luac.NewLState()
 		if l == nil {
 			return -1, C.CString("[Contract.LuaDeployContract] get luaState error")
 		}
-		defer luacUtil.CloseLState(l)
-		code, err = luacUtil.Compile(l, contractStr)
+		defer luac.CloseLState(l)
+		code, err = luac.Compile(l, contractStr)
 		if err != nil {
 			return -1, C.CString("[Contract.LuaDeployContract]compile error:" + err.Error())
 		}
@@ -924,7 +1229,7 @@ func LuaDeployContract(
 	if err != nil {
 		return -1, C.CString("[Contract.LuaDeployContract]:" + err.Error())
 	}
-	err = contractState.SetData([]byte("Creator"), []byte(types.EncodeAddress(prevContractInfo.contractId)))
+	err = contractState.SetData([]byte(creatorKey), []byte(types.EncodeAddress(prevContractInfo.contractId)))
 	if err != nil {
 		return -1, C.CString("[Contract.LuaDeployContract]:" + err.Error())
 	}
@@ -985,6 +1290,9 @@ func LuaEvent(L *LState, service *C.int, eventName *C.char, args *C.char) *C.cha
 	if stateSet.isQuery == true {
 		return C.CString("[Contract.Event] event not permitted in query")
 	}
+	if errMsg := useGas(L, stateSet, fee.EventGas); errMsg != nil {
+		return errMsg
+	}
 	if stateSet.eventCount >= maxEventCnt {
 		return C.CString(fmt.Sprintf("[Contract.Event] exceeded the maximum number of events(%d)", maxEventCnt))
 	}
@@ -1032,6 +1340,9 @@ func LuaGovernance(L *LState, service *C.int, gType C.char, arg *C.char) *C.char
 	if stateSet == nil {
 		return C.CString("[Contract.LuaGovernance] contract state not found")
 	}
+	if errMsg := useGas(L, stateSet, fee.GovernanceGas); errMsg != nil {
+		return errMsg
+	}
 	var amountBig *big.Int
 	var payload []byte
 
@@ -14,8 +14,9 @@ import (
 	"time"
 
 	"github.com/aergoio/aergo-lib/db"
-	luac_util "github.com/aergoio/aergo/cmd/aergoluac/util"
 	"github.com/aergoio/aergo/contract/system"
+	"github.com/aergoio/aergo/fee"
+	"github.com/aergoio/aergo/pkg/luac"
 	"github.com/aergoio/aergo/state"
 	"github.com/aergoio/aergo/types"
 	"github.com/minio/sha256-simd"
@@ -91,6 +92,22 @@ func (bc *DummyChain) GetABI(contract string) (*types.ABI, error) {
 	return GetABI(cState)
 }
 
+func (bc *DummyChain) GetCodeVersions(contract string) ([]*types.CodeVersion, error) {
+	cState, err := bc.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(strHash(contract)))
+	if err != nil {
+		return nil, err
+	}
+	return GetCodeVersions(cState)
+}
+
+func (bc *DummyChain) VerifySource(contract, source string) error {
+	cState, err := bc.sdb.GetStateDB().OpenContractStateAccount(types.ToAccountID(strHash(contract)))
+	if err != nil {
+		return err
+	}
+	return VerifySource(cState, source)
+}
+
 func (bc *DummyChain) getReceipt(txHash []byte) *types.Receipt {
 	r := new(types.Receipt)
 	r.UnmarshalBinary(bc.testReceiptDB.Get(txHash))
@@ -214,12 +231,12 @@ type luaTxDef struct {
 }
 
 func NewLuaTxDef(sender, contract string, amount uint64, code string) *luaTxDef {
-	L := luac_util.NewLState()
+	L := luac.NewLState()
 	if L == nil {
 		return &luaTxDef{cErr: newVmStartError()}
 	}
-	defer luac_util.CloseLState(L)
-	b, err := luac_util.Compile(L, code)
+	defer luac.CloseLState(L)
+	b, err := luac.Compile(L, code)
 	if err != nil {
 		return &luaTxDef{cErr: err}
 	}
@@ -240,12 +257,12 @@ func NewLuaTxDef(sender, contract string, amount uint64, code string) *luaTxDef
 
 func getCompiledABI(code string) ([]byte, error) {
 
-	L := luac_util.NewLState()
+	L := luac.NewLState()
 	if L == nil {
 		return nil, newVmStartError()
 	}
-	defer luac_util.CloseLState(L)
-	b, err := luac_util.Compile(L, code)
+	defer luac.CloseLState(L)
+	b, err := luac.Compile(L, code)
 	if err != nil {
 		return nil, err
 	}
@@ -386,9 +403,69 @@ func (l *luaTxDef) run(bs *state.BlockState, bc *DummyChain, blockNo uint64, ts
 	)
 }
 
+type luaTxRedeploy struct {
+	luaTxCommon
+	cErr        error
+	expectedErr string
+}
+
+func NewLuaTxRedeploy(sender, contract string, code string) *luaTxRedeploy {
+	def := NewLuaTxDef(sender, contract, 0, code)
+	return &luaTxRedeploy{luaTxCommon: def.luaTxCommon, cErr: def.cErr}
+}
+
+func (l *luaTxRedeploy) hash() []byte {
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatUint(l.id, 10)))
+	b := h.Sum(nil)
+	return b
+}
+
+func (l *luaTxRedeploy) Fail(expectedErr string) *luaTxRedeploy {
+	l.expectedErr = expectedErr
+	return l
+}
+
+func (l *luaTxRedeploy) run(bs *state.BlockState, bc *DummyChain, blockNo uint64, ts int64, prevBlockHash []byte,
+	receiptTx db.Transaction) error {
+
+	if l.cErr != nil {
+		return l.cErr
+	}
+	err := contractFrame(&l.luaTxCommon, bs,
+		func(sender, contract *state.V, contractId types.AccountID, eContractState *state.ContractState) error {
+			stateSet := NewContext(bs, bc, sender, contract, eContractState, sender.ID(),
+				l.hash(), blockNo, ts, prevBlockHash, "", true,
+				false, contract.State().SqlRecoveryPoint, ChainService, l.luaTxCommon.amount)
+			_, evs, _, err := Redeploy(eContractState, l.code, l.contract, stateSet)
+			if err != nil {
+				return err
+			}
+			_ = bs.StageContractState(eContractState)
+			r := types.NewReceipt(l.contract, "SUCCESS", "")
+			r.Events = evs
+			r.TxHash = l.hash()
+			b, _ := r.MarshalBinary()
+			receiptTx.Set(l.hash(), b)
+			return nil
+		},
+	)
+	if l.expectedErr != "" {
+		if err == nil {
+			return fmt.Errorf("no error, expected: %s", l.expectedErr)
+		}
+		if !strings.Contains(err.Error(), l.expectedErr) {
+			return err
+		}
+		return nil
+	}
+	return err
+}
+
 type luaTxCall struct {
 	luaTxCommon
 	expectedErr string
+	gasLimit    uint64
 }
 
 func NewLuaTxCall(sender, contract string, amount uint64, code string) *luaTxCall {
@@ -427,6 +504,12 @@ func (l *luaTxCall) Fail(expectedErr string) *luaTxCall {
 	return l
 }
 
+// Gas makes the call metered by the gas schedule at the MinGasPrice.
+func (l *luaTxCall) Gas(gasLimit uint64) *luaTxCall {
+	l.gasLimit = gasLimit
+	return l
+}
+
 func (l *luaTxCall) run(bs *state.BlockState, bc *DummyChain, blockNo uint64, ts int64, prevBlockHash []byte,
 	receiptTx db.Transaction) error {
 	err := contractFrame(&l.luaTxCommon, bs,
@@ -434,9 +517,24 @@ func (l *luaTxCall) run(bs *state.BlockState, bc *DummyChain, blockNo uint64, ts
 			stateSet := NewContext(bs, bc, sender, contract, eContractState, sender.ID(),
 				l.hash(), blockNo, ts, prevBlockHash, "", true,
 				false, contract.State().SqlRecoveryPoint, ChainService, l.luaTxCommon.amount)
+			var gasUsed uint64
+			if l.gasLimit > 0 {
+				gasUsed = fee.TxGas(len(l.code))
+				if gasUsed > l.gasLimit {
+					return types.ErrTxGasLimitTooLow
+				}
+				stateSet.setGas(l.gasLimit-gasUsed, fee.MinGasPrice())
+			}
 			rv, evs, _, err := Call(eContractState, l.code, l.contract, stateSet)
+			if l.gasLimit > 0 {
+				gasUsed += stateSet.totalGas()
+				if gasUsed > l.gasLimit {
+					gasUsed = l.gasLimit
+				}
+			}
 			if err != nil {
 				r := types.NewReceipt(l.contract, err.Error(), "")
+				r.GasUsed = gasUsed
 				r.TxHash = l.hash()
 				b, _ := r.MarshalBinary()
 				receiptTx.Set(l.hash(), b)
@@ -445,6 +543,7 @@ func (l *luaTxCall) run(bs *state.BlockState, bc *DummyChain, blockNo uint64, ts
 			_ = bs.StageContractState(eContractState)
 			r := types.NewReceipt(l.contract, "SUCCESS", rv)
 			r.Events = evs
+			r.GasUsed = gasUsed
 			r.TxHash = l.hash()
 			blockHash := make([]byte, 32)
 			for _, ev := range evs {
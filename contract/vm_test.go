@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aergoio/aergo/fee"
 	"github.com/aergoio/aergo/types"
 )
 
@@ -579,6 +580,72 @@ abi.register(infiniteLoop, infiniteCall, catch)`
 
 }
 
+func TestGas(t *testing.T) {
+	bc, err := LoadDummyChain()
+	if err != nil {
+		t.Errorf("failed to create test database: %v", err)
+	}
+
+	definition := `
+function loop(n)
+	local t = 0
+	for i = 1, n do
+		t = t + i
+	end
+	return t
+end
+function getItems(n)
+	local v
+	for i = 1, n do
+		v = system.getItem("key")
+	end
+	return v
+end
+abi.register(loop, getItems)`
+
+	err = bc.ConnectBlock(
+		NewLuaTxAccount("ktlee", 100),
+		NewLuaTxDef("ktlee", "gas", 0, definition),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gasUsed := func(tx *luaTxCall) uint64 {
+		if err := bc.ConnectBlock(tx); err != nil {
+			t.Fatal(err)
+		}
+		return bc.getReceipt(tx.hash()).GetGasUsed()
+	}
+	const gasLimit = 1000000
+
+	// the intrinsic gas and the instructions
+	loop1 := gasUsed(NewLuaTxCall("ktlee", "gas", 0, `{"Name":"loop","Args":[1]}`).Gas(gasLimit))
+	loop10 := gasUsed(NewLuaTxCall("ktlee", "gas", 0, `{"Name":"loop","Args":[10]}`).Gas(gasLimit))
+	if loop1 <= fee.TxBaseGas || loop10 <= loop1 {
+		t.Errorf("unexpected gas used: %d, %d", loop1, loop10)
+	}
+
+	// the host functions
+	getItems10 := gasUsed(NewLuaTxCall("ktlee", "gas", 0, `{"Name":"getItems","Args":[10]}`).Gas(gasLimit))
+	if getItems10 < loop10+10*fee.GetDbGas {
+		t.Errorf("the host functions are not charged: %d, %d", loop10, getItems10)
+	}
+
+	// out of gas
+	for _, c := range []struct{ payload, errMsg string }{
+		{`{"Name":"loop","Args":[100000]}`, "exceeded the maximum instruction count"},
+		{`{"Name":"getItems","Args":[100000]}`, "not enough gas"},
+	} {
+		limit := fee.TxGas(len(c.payload)) + 5000
+		tx := NewLuaTxCall("ktlee", "gas", 0, c.payload).Gas(limit).Fail(c.errMsg)
+		// the gas left is less than the cost of the next instruction or call
+		if used := gasUsed(tx); used <= limit-fee.GetDbGas || used > limit {
+			t.Errorf("expected gas used: %d, but got: %d", limit, used)
+		}
+	}
+}
+
 func TestUpdateSize(t *testing.T) {
 	bc, err := LoadDummyChain()
 	if err != nil {
@@ -2498,6 +2565,138 @@ func TestMapKey(t *testing.T) {
 	}
 }
 
+func TestMapIteration(t *testing.T) {
+	definition := `
+	state.var{
+		counts = state.map({iterable = true}),
+		ids = state.map({iterable = true}),
+		plain = state.map()
+	}
+	function setCount(key, value)
+		counts[key] = value
+	end
+	function delCount(key)
+		counts:delete(key)
+	end
+	function list(cursor, limit)
+		local items = {}
+		for k, v in counts:pairs(cursor, limit) do
+			table.insert(items, k .. "=" .. v)
+		end
+		return items
+	end
+	function keys(cursor, limit)
+		return counts:keys(cursor, limit), counts:length()
+	end
+	function clear()
+		for k, v in counts:pairs() do
+			counts[k] = nil
+		end
+		return counts:length()
+	end
+	function setId(id)
+		ids[id] = true
+	end
+	function idKeys()
+		return ids:keys()
+	end
+	function setPlain()
+		plain["keys"] = 1
+		assert(plain["keys"] == 1)
+	end
+	function setNulKeys()
+		counts["x\0y"] = 1
+		counts["x\0z"] = 2
+	end
+	function nulKeys()
+		local ks = counts:keys()
+		return #ks, ks[1] == "x\0y", ks[2] == "x\0z", #counts:keys("x\0y")
+	end
+	abi.register(setCount, delCount, list, keys, clear, setId, idKeys, setPlain, setNulKeys, nulKeys)
+`
+	bc, _ := LoadDummyChain()
+	err := bc.ConnectBlock(
+		NewLuaTxAccount("ktlee", 100),
+		NewLuaTxDef("ktlee", "a", 0, definition),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bc.Query("a", `{"Name":"keys"}`, "", `[{},0]`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = bc.ConnectBlock(
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["b", 2]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["a", 1]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["d", 4]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["c", 3]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["a", 10]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["keys", 5]}`).Fail("can't use 'keys' as a key"),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setId", "Args":[3]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setId", "Args":[1.5]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setPlain"}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"list"}`, "", `["b=2","a=10","d=4","c=3"]`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"list", "Args":["a", 1]}`, "", `["d=4"]`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"keys", "Args":[null, 3]}`, "", `[["b","a","d"],4]`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"keys", "Args":["x"]}`, "the key of the cursor is not found", "")
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"idKeys"}`, "", `[3,1.5]`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = bc.ConnectBlock(
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"delCount", "Args":["a"]}`),
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setCount", "Args":["b", null]}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"keys"}`, "", `[["d","c"],2]`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.ConnectBlock(
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"clear"}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"list"}`, "", `{}`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// the keys which have NUL
+	err = bc.ConnectBlock(
+		NewLuaTxCall("ktlee", "a", 0, `{"Name":"setNulKeys"}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"nulKeys"}`, "", `[2,true,true,1]`)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestStateVarFieldUpdate(t *testing.T) {
 	src := `
 state.var{
@@ -2818,6 +3017,88 @@ abi.register(get, checkEther, checkAergo)
 	}
 }
 
+func TestCryptoExtended(t *testing.T) {
+	src := `
+function hash(data)
+	return crypto.keccak256(data), crypto.ripemd160(data), crypto.blake2b(data)
+end
+
+function ed25519verify(msg, sig, pubkey)
+	return crypto.ed25519verify(msg, sig, pubkey)
+end
+
+function ethAddress(msg, sig)
+	local pub = crypto.ecrecover(msg, sig)
+	return "0x" .. string.sub(crypto.keccak256("0x" .. string.sub(pub, 5)), -40)
+end
+
+function verifyProof(key, value, root, ...)
+	return crypto.verifyProof(key, value, root, ...)
+end
+abi.register(hash, ed25519verify, ethAddress, verifyProof)
+`
+	bc, _ := LoadDummyChain()
+	err := bc.ConnectBlock(
+		NewLuaTxAccount("ktlee", 100),
+		NewLuaTxDef("ktlee", "crypto", 0, src),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"aergo", "0x616572676f"} {
+		err = bc.Query("crypto", `{"Name": "hash", "Args" : ["`+arg+`"]}`, "",
+			`["0xe98bb03ab37161f8bbfe131f711dcccf3002a9cd9ec31bbd52edf181f7ab09a0","0xd8f1b77dbeec8e2713c93608359bb851837018b0","0xd43ba0df08df6d7e384b47fcc2d609f013119a8fbe720e064850bdac3774f77d"]`)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	pubkey := "0xf165e1e5f7c290e52f2edef3fbab60cbae74bfd3274f8e5ee1de3345c954a166"
+	sig := "0x58c1e3aceaf2d717f70d9a235d9e096185807f43b949862454ce630223e36a83e1fe77f27f12cec524aa27ccd6a34af4d69091ce86373d753e750c27723d4b0a"
+	err = bc.Query("crypto", `{"Name": "ed25519verify", "Args" : ["0x616572676f", "`+sig+`", "`+pubkey+`"]}`, "", `true`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("crypto", `{"Name": "ed25519verify", "Args" : ["0x6165726770", "`+sig+`", "`+pubkey+`"]}`, "", `false`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("crypto", `{"Name": "ed25519verify", "Args" : ["0x6165726770", "`+sig+`", "0x1234"]}`, "invalid public key length", "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = bc.Query("crypto", `{"Name": "ethAddress", "Args" : ["0xce0677bb30baa8cf067c88db9811f4333d131bf8bcf12fe7065d211dce971008",
+"0x90f27b8b488db00b00606796d2987f6a5f59ae62ea05effe84fef5b8b0e549984a691139ad57a3f0b906637673aa2f63d1f55cb1a69199d4009eea23ceaddc9301"]}`,
+		"", `"0xa19d069d48d2e9392ec2bb41ecab0a72119d633b"`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("crypto", `{"Name": "ethAddress", "Args" : ["0xce0677bb30baa8cf067c88db9811f4333d131bf8bcf12fe7065d211dce971008",
+"0x90f27b8b488db00b00606796d2987f6a5f59ae62ea05effe84fef5b8b0e549984a691139ad57a3f0b906637673aa2f63d1f55cb1a69199d4009eea23ceaddc9305"]}`,
+		"invalid recovery id", "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	key := "0x18ac3e7343f016890c510e93f935261169d9e3f565436429830faf0934f4f8e4"
+	value := "0x53423cb9661c9419ada22856c7053555a2ce4e11be200125f7a8487e480f4aa5"
+	root := "0x1a2d8dd1d5579885871642e7ac797ebc9851fcd008613be773cc52d0dd1db7a5"
+	proof := `"0xf3682b2bac323c2a13e9668ad381ab8e1a7603bf07482b2b38da31d214599a3e", "0x00", "0x05a656e8bcf1ed6936184715f78cd2ca8add6a39d4289707631be080cb914476"`
+	err = bc.Query("crypto", `{"Name": "verifyProof", "Args" : ["`+key+`", "`+value+`", "`+root+`", `+proof+`]}`, "", `true`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("crypto", `{"Name": "verifyProof", "Args" : ["`+key+`", "`+root+`", "`+root+`", `+proof+`]}`, "", `false`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("crypto", `{"Name": "verifyProof", "Args" : ["`+key+`", "`+value+`", "0x1a2d"]}`, "invalid hash length", "")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestPayable(t *testing.T) {
 	src := `
 state.var {
@@ -4152,4 +4433,235 @@ func TestSnapshot(t *testing.T) {
 		t.Error(err)
 	}
 }
+func TestContractRedeploy(t *testing.T) {
+	bc, err := LoadDummyChain()
+	if err != nil {
+		t.Errorf("failed to create test database: %v", err)
+	}
+	v1 := `
+	state.var{ count = state.value() }
+	function constructor()
+		db.exec("create table log(n integer)")
+	end
+	function inc()
+		count:set((count:get() or 0) + 1)
+		db.exec("insert into log values (1)")
+	end
+	function get()
+		return count:get()
+	end
+	abi.register(inc, get)`
+	v2 := `
+	state.var{ count = state.value() }
+	function inc()
+		count:set((count:get() or 0) + 10)
+		db.exec("insert into log values (10)")
+	end
+	function get()
+		return count:get()
+	end
+	function sum()
+		local rs = db.query("select sum(n) from log")
+		rs:next()
+		return rs:get()
+	end
+	function setOwner(owner)
+		system.setOwner(owner)
+	end
+	function getOwner()
+		return system.getOwner()
+	end
+	abi.register(inc, get, sum, setOwner, getOwner)`
+
+	err = bc.ConnectBlock(
+		NewLuaTxAccount("ktlee", 100),
+		NewLuaTxAccount("other", 100),
+		NewLuaTxDef("ktlee", "counter", 0, v1),
+		NewLuaTxCall("ktlee", "counter", 0, `{"Name": "inc"}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.ConnectBlock(
+		NewLuaTxRedeploy("other", "counter", v2).Fail("only the creator or the owner"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.ConnectBlock(
+		NewLuaTxRedeploy("ktlee", "counter", v2),
+		NewLuaTxCall("ktlee", "counter", 0, `{"Name": "inc"}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("counter", `{"Name":"get"}`, "", "11")
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("counter", `{"Name":"sum"}`, "", "11")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = bc.ConnectBlock(
+		NewLuaTxCall("ktlee", "counter", 0, `{"Name": "setOwner", "Args":["invalid"]}`).Fail("invalid owner address"),
+		NewLuaTxCall("ktlee", "counter", 0, fmt.Sprintf(`{"Name": "setOwner", "Args":["%s"]}`, StrToAddress("other"))),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("counter", `{"Name":"getOwner"}`, "", fmt.Sprintf(`"%s"`, StrToAddress("other")))
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.ConnectBlock(
+		NewLuaTxRedeploy("other", "counter", v1),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("counter", `{"Name":"sum"}`, "not found function")
+	if err != nil {
+		t.Error(err)
+	}
+
+	versions, err := bc.GetCodeVersions("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("expected 3 code versions, but got %d", len(versions))
+	}
+	if !bytes.Equal(versions[0].CodeHash, versions[2].CodeHash) || bytes.Equal(versions[0].CodeHash, versions[1].CodeHash) {
+		t.Error("invalid code hashes of the versions")
+	}
+	if !bytes.Equal(versions[1].Deployer, strHash("ktlee")) || !bytes.Equal(versions[2].Deployer, strHash("other")) {
+		t.Error("invalid deployers of the versions")
+	}
+}
+
 // end of test-cases
+
+func TestTypedABI(t *testing.T) {
+	definition := `
+	function transfer(to, amount, memo)
+		return to, bignum.tostring(amount), memo
+	end
+	function hash(data)
+		return data
+	end
+	function untyped(a)
+		return a
+	end
+	abi.register(transfer, hash, untyped)
+	abi.types(transfer, {"address", "bignum", "string"}, {"address", "bignum", "string"})
+	abi.types("hash", {"bytes"}, {"bytes"})
+	abi.event("Transfer", {"address", "address", "bignum"})
+`
+	bc, _ := LoadDummyChain()
+	err := bc.ConnectBlock(
+		NewLuaTxAccount("ktlee", 100),
+		NewLuaTxDef("ktlee", "a", 0, definition),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	abi, err := bc.GetABI("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer := abi.Function("transfer")
+	if transfer == nil || transfer.Arguments[1].Type != "bignum" || len(transfer.Returns) != 3 {
+		t.Errorf("wrong types of transfer: %v", transfer)
+	}
+	if len(abi.Events) != 1 || abi.Events[0].Name != "Transfer" || len(abi.Events[0].Arguments) != 3 {
+		t.Errorf("wrong events: %v", abi.Events)
+	}
+	if abi.Function("untyped").Arguments[0].Type != "" {
+		t.Errorf("untyped function has types")
+	}
+
+	err = bc.Query("a", `{"Name":"transfer", "Args":["aergo.system", 1000000000000000000001, "memo"]}`,
+		"", `["aergo.system","1000000000000000000001","memo"]`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"transfer", "Args":["aergo.system", "1000", "memo"]}`,
+		"", `["aergo.system","1000","memo"]`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"transfer", "Args":["invalid", "1000"]}`, "invalid address", "")
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"transfer", "Args":["aergo.system", 1.5]}`, "bignum expected", "")
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"hash", "Args":["ABCD"]}`, "", `"0xabcd"`)
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"hash", "Args":["xyz"]}`, "invalid hex string", "")
+	if err != nil {
+		t.Error(err)
+	}
+	err = bc.Query("a", `{"Name":"untyped", "Args":[1.5]}`, "", `1.5`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, c := range []struct{ code, errMsg string }{
+		{`function f(a) end abi.register(f) abi.types(f, {"int"})`, "invalid type"},
+		{`function f(a) end abi.register(f) abi.types(f, {"string", "string"})`, "the number of the parameter types"},
+		{`function f(a) end abi.register(f) abi.types("g", {"string"})`, "unregistered function"},
+	} {
+		err = bc.ConnectBlock(
+			NewLuaTxDef("ktlee", "b", 0, c.code),
+		)
+		if err == nil {
+			t.Errorf("expected: %s, but got: nil", c.errMsg)
+		} else if !strings.Contains(err.Error(), c.errMsg) {
+			t.Errorf("expected: %s, but got: %s", c.errMsg, err.Error())
+		}
+	}
+}
+
+func TestVerifySource(t *testing.T) {
+	definition := `
+	function hello(say)
+		return "Hello " .. say
+	end
+	abi.register(hello)
+`
+	bc, _ := LoadDummyChain()
+	err := bc.ConnectBlock(
+		NewLuaTxAccount("ktlee", 100),
+		NewLuaTxDef("ktlee", "a", 0, definition),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = bc.VerifySource("a", definition); err != nil {
+		t.Error(err)
+	}
+	for _, c := range []struct{ source, errMsg string }{
+		{strings.Replace(definition, "Hello ", "Hi ", 1), "bytecode of the source doesn't match"},
+		{"function hello(", "compile error"},
+		{definition + strings.Repeat(" ", types.TxMaxSize), "source is larger than"},
+	} {
+		err = bc.VerifySource("a", c.source)
+		if err == nil {
+			t.Errorf("expected: %s, but got: nil", c.errMsg)
+		} else if !strings.Contains(err.Error(), c.errMsg) {
+			t.Errorf("expected: %s, but got: %s", c.errMsg, err.Error())
+		}
+	}
+	if err = bc.VerifySource("ktlee", definition); err == nil {
+		t.Error("verified the source of an account")
+	}
+}
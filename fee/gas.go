@@ -0,0 +1,102 @@
+package fee
+
+import (
+	"math"
+	"math/big"
+)
+
+// Gas schedule for the transactions which specify a gas limit. A transaction
+// whose GasLimit is zero is still charged by the payload-size based fee.
+const (
+	TxBaseGas = 100000 // intrinsic gas of every metered tx
+
+	// A byte costs as much at the MinGasPrice as it does by the payload-size
+	// based fee.
+	PayloadGasPerByte = aerPerByte / (baseTxFee / TxBaseGas) // gas per payload byte beyond freeByteSize
+	StateDbGasPerByte = PayloadGasPerByte                    // gas per byte written to the state database
+
+	// MaxGasLimit bounds the gas limit of a transaction. The Lua VM counts
+	// instructions with a C int, so the limit must fit into it.
+	MaxGasLimit = math.MaxInt32
+)
+
+// Gas costs of the host functions called from contracts.
+const (
+	GetDbGas        = 200
+	SetDbGas        = 500
+	DelDbGas        = 200
+	GetBalanceGas   = 200
+	SendGas         = 1000
+	CallGas         = 1000
+	DelegateCallGas = 1000
+	DeployGas       = 5000
+	EventGas        = 500
+	GovernanceGas   = 1000
+	HashGas         = 100
+	SigVerifyGas    = 3000
+)
+
+// Gas costs of the crypto functions called from contracts. The hash functions
+// are also charged per 32-byte word of the hashed data, and the merkle proof
+// verification per node of the audit path.
+const (
+	HashWordGas      = 3
+	Ed25519VerifyGas = 2000
+	ECRecoverGas     = SigVerifyGas
+	MerkleNodeGas    = HashGas
+)
+
+// HashDataGas returns the gas used for hashing size bytes.
+func HashDataGas(size int) uint64 {
+	return HashGas + uint64((size+31)/32)*HashWordGas
+}
+
+// IsGasMetered reports whether a tx with the given gas limit is charged by
+// the gas schedule.
+func IsGasMetered(gasLimit uint64) bool {
+	return gasLimit > 0
+}
+
+// TxGas returns the intrinsic gas of a tx with the given payload size.
+func TxGas(payloadSize int) uint64 {
+	if IsZeroFee() {
+		return 0
+	}
+	size := PaymentDataSize(int64(payloadSize))
+	if size > payloadMaxSize {
+		size = payloadMaxSize
+	}
+	return TxBaseGas + uint64(size)*PayloadGasPerByte
+}
+
+// StateDbGas returns the gas used for writing updateSize bytes to the state
+// database.
+func StateDbGas(updateSize int64) uint64 {
+	if IsZeroFee() {
+		return 0
+	}
+	return uint64(PaymentDataSize(updateSize)) * StateDbGasPerByte
+}
+
+// GasFee returns the fee paid for gasUsed at gasPrice.
+func GasFee(gasUsed uint64, gasPrice *big.Int) *big.Int {
+	if IsZeroFee() {
+		return zero
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), gasPrice)
+}
+
+// MinGasPrice returns the lowest gas price of a metered tx, at which the
+// intrinsic gas costs as much as the base fee of a tx without a gas limit.
+func MinGasPrice() *big.Int {
+	if IsZeroFee() {
+		return zero
+	}
+	return new(big.Int).Div(baseTxAergo, new(big.Int).SetUint64(TxBaseGas))
+}
+
+// MaxGasTxFee returns the maximum fee which a tx with the given gas limit
+// may pay.
+func MaxGasTxFee(gasLimit uint64, gasPrice *big.Int) *big.Int {
+	return GasFee(gasLimit, gasPrice)
+}
@@ -5,8 +5,8 @@ import (
 )
 
 const (
-	baseTxFee            = "2000000000000000" // 0.002 AERGO
-	aerPerByte           = 5000000000000      // 5,000 GAER, feePerBytes * PayloadMaxBytes = 1 AERGO
+	baseTxFee            = 2000000000000000 // 0.002 AERGO
+	aerPerByte           = 5000000000000    // 5,000 GAER, feePerBytes * PayloadMaxBytes = 1 AERGO
 	payloadMaxSize       = 200 * 1024
 	StateDbMaxUpdateSize = payloadMaxSize
 	freeByteSize         = 200
@@ -21,7 +21,7 @@ var (
 )
 
 func init() {
-	baseTxAergo, _ = new(big.Int).SetString(baseTxFee, 10)
+	baseTxAergo = big.NewInt(baseTxFee)
 	zeroFee = false
 	AerPerByte = big.NewInt(aerPerByte)
 	stateDbMaxFee = new(big.Int).Mul(AerPerByte, big.NewInt(StateDbMaxUpdateSize-freeByteSize))
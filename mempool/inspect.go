@@ -0,0 +1,140 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package mempool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aergoio/aergo/types"
+)
+
+const (
+	// the cursor of the tx list is the account id and the nonce of the last tx
+	mempoolCursorLength = len(types.AccountID{}) + 8
+
+	MaxMempoolListLimit = 1000
+)
+
+var ErrInvalidMempoolCursor = errors.New("invalid mempool cursor")
+
+// inspect returns the txs of the list with the reasons why the orphans are
+// not executable, and the ranges of the missing nonces.
+func (tl *TxList) inspect() ([]*types.MempoolTx, []*types.NonceGap) {
+	tl.RLock()
+	defer tl.RUnlock()
+
+	txs := make([]*types.MempoolTx, 0, len(tl.list))
+	var gaps []*types.NonceGap
+	expected := tl.base.Nonce + 1
+	for i, tx := range tl.list {
+		nonce := tx.GetBody().GetNonce()
+		mtx := &types.MempoolTx{Tx: tx.GetTx(), Ready: i < tl.ready}
+		if nonce > expected {
+			gaps = append(gaps, &types.NonceGap{From: expected, To: nonce - 1})
+		}
+		if !mtx.Ready && len(gaps) > 0 {
+			mtx.Reason = fmt.Sprintf("nonce gap: waiting for the tx of nonce %d", gaps[0].From)
+		}
+		txs = append(txs, mtx)
+		expected = nonce + 1
+	}
+	return txs, gaps
+}
+
+// listTxs returns the txs in the order of the account id and the nonce. The
+// txs are returned after cursor, and the cursor of the next page is returned
+// if there are more than limit txs.
+func (mp *MemPool) listTxs(cursor []byte, limit int) ([]*types.MempoolTx, []byte, error) {
+	if len(cursor) != 0 && len(cursor) != mempoolCursorLength {
+		return nil, nil, ErrInvalidMempoolCursor
+	}
+	if limit <= 0 || limit > MaxMempoolListLimit {
+		limit = MaxMempoolListLimit
+	}
+
+	mp.RLock()
+	defer mp.RUnlock()
+
+	ids := make([]types.AccountID, 0, len(mp.pool))
+	for id := range mp.pool {
+		if len(cursor) == 0 || bytes.Compare(id[:], cursor[:len(id)]) >= 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+
+	txs := []*types.MempoolTx{}
+	var lastCursor []byte
+	for _, id := range ids {
+		accTxs, _ := mp.pool[id].inspect()
+		for _, tx := range accTxs {
+			nonce := tx.GetTx().GetBody().GetNonce()
+			if len(cursor) != 0 && bytes.Equal(id[:], cursor[:len(id)]) &&
+				nonce <= binary.BigEndian.Uint64(cursor[len(id):]) {
+				continue
+			}
+			if len(txs) == limit {
+				return txs, lastCursor, nil
+			}
+			txs = append(txs, tx)
+			lastCursor = make([]byte, mempoolCursorLength)
+			copy(lastCursor, id[:])
+			binary.BigEndian.PutUint64(lastCursor[len(id):], nonce)
+		}
+	}
+	return txs, nil, nil
+}
+
+// accountInfo returns the pending txs of acc and its nonce gaps.
+func (mp *MemPool) accountInfo(acc []byte) (*types.MempoolAccount, error) {
+	mp.RLock()
+	defer mp.RUnlock()
+
+	if len(acc) <= types.NameLength {
+		if acc = mp.getAddress(acc); acc == nil {
+			return nil, types.ErrNameNotFound
+		}
+	}
+
+	info := &types.MempoolAccount{Account: acc, Txs: []*types.MempoolTx{}}
+	list := mp.getMemPoolList(acc)
+	if list == nil {
+		ns, err := mp.getAccountState(acc)
+		if err != nil {
+			return nil, err
+		}
+		info.Nonce = ns.GetNonce()
+		info.Balance = ns.GetBalance()
+		return info, nil
+	}
+
+	info.Txs, info.Gaps = list.inspect()
+	info.Nonce = list.base.GetNonce()
+	info.Balance = list.base.GetBalance()
+	info.Ready = uint32(list.Len())
+	info.Orphan = uint32(len(info.Txs)) - info.Ready
+	return info, nil
+}
+
+func (mp *MemPool) stats() *types.MempoolStats {
+	mp.RLock()
+	defer mp.RUnlock()
+
+	return &types.MempoolStats{
+		Total:    uint64(len(mp.cache)),
+		Orphan:   uint64(mp.orphan),
+		Accounts: uint64(len(mp.pool)),
+		Bytes:    uint64(mp.bytes),
+		Evicted:  uint64(mp.evicted),
+		Rejected: uint64(mp.rejected),
+	}
+}
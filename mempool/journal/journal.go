@@ -0,0 +1,220 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+// Package journal implements the append-only journal of the mempool, which
+// keeps the pending txs across a crash of the server.
+//
+// The journal is a csv file. Each record is an operation and its encoded
+// argument:
+//
+//	+,<encoded tx>    the tx is accepted into the mempool
+//	-,<encoded hash>  the tx is removed from the mempool
+//
+// A record of a single column is an accepted tx, which is the format of the
+// old mempool dump file.
+package journal
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"os"
+
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	opAdd    = "+"
+	opRemove = "-"
+)
+
+// Writer appends the records to a journal file. The records are buffered
+// until Sync, so that a change of the mempool is written by a single sync.
+type Writer struct {
+	file  *os.File
+	w     *csv.Writer
+	count int
+	dirty bool
+}
+
+// Open opens the journal file at path to append records to it.
+func Open(path string) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: file, w: csv.NewWriter(file)}, nil
+}
+
+// Add records that tx is accepted.
+func (jw *Writer) Add(tx *types.Tx) error {
+	data, err := proto.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	return jw.write(opAdd, data)
+}
+
+// Remove records that the tx of hash is removed.
+func (jw *Writer) Remove(hash []byte) error {
+	return jw.write(opRemove, hash)
+}
+
+func (jw *Writer) write(op string, data []byte) error {
+	if err := jw.w.Write([]string{op, enc.ToString(data)}); err != nil {
+		return err
+	}
+	jw.count++
+	jw.dirty = true
+	return nil
+}
+
+// Sync flushes the buffered records and syncs them to the file, so that they
+// survive a crash of the process or the host.
+func (jw *Writer) Sync() error {
+	if !jw.dirty {
+		return nil
+	}
+	jw.w.Flush()
+	if err := jw.w.Error(); err != nil {
+		return err
+	}
+	if err := jw.file.Sync(); err != nil {
+		return err
+	}
+	jw.dirty = false
+	return nil
+}
+
+// Count returns the number of the records written by jw.
+func (jw *Writer) Count() int {
+	return jw.count
+}
+
+// Close syncs the buffered records and closes the journal file.
+func (jw *Writer) Close() error {
+	if err := jw.Sync(); err != nil {
+		jw.file.Close() // nolint: errcheck
+		return err
+	}
+	return jw.file.Close()
+}
+
+// Compact replaces the journal file at path with the one which has only the
+// records of txs. The file is removed if there is no tx.
+func Compact(path string, txs []*types.Tx) error {
+	if len(txs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(file)
+	for _, tx := range txs {
+		data, err := proto.Marshal(tx)
+		if err != nil {
+			continue
+		}
+		if err := w.Write([]string{opAdd, enc.ToString(data)}); err != nil {
+			file.Close() // nolint: errcheck
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close() // nolint: errcheck
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close() // nolint: errcheck
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Load replays the journal file at path, and returns the txs which are not
+// removed in the order of acceptance. It returns no tx if the file does not
+// exist.
+func Load(path string) ([]*types.Tx, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close() // nolint: errcheck
+
+	return Read(file)
+}
+
+// Read replays the journal from r. The records before an error are replayed,
+// since the last record may be partially written by a crash.
+func Read(r io.Reader) ([]*types.Tx, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+
+	var (
+		txs   []*types.Tx
+		index = make(map[types.TxID]int)
+		err   error
+	)
+	for {
+		var rc []string
+		if rc, err = reader.Read(); err != nil {
+			break
+		}
+
+		op, arg := opAdd, rc[0]
+		if len(rc) > 1 {
+			op, arg = rc[0], rc[1]
+		}
+		data, decErr := enc.ToBytes(arg)
+		if decErr != nil {
+			continue
+		}
+
+		switch op {
+		case opAdd:
+			tx := &types.Tx{}
+			if proto.Unmarshal(data, tx) != nil {
+				continue
+			}
+			id := types.ToTxID(tx.GetHash())
+			if _, exist := index[id]; !exist {
+				index[id] = len(txs)
+				txs = append(txs, tx)
+			}
+		case opRemove:
+			id := types.ToTxID(data)
+			if i, exist := index[id]; exist {
+				txs[i] = nil
+				delete(index, id)
+			}
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+
+	live := make([]*types.Tx, 0, len(index))
+	for _, tx := range txs {
+		if tx != nil {
+			live = append(live, tx)
+		}
+	}
+	return live, err
+}
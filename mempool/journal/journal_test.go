@@ -0,0 +1,83 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTx(nonce uint64) *types.Tx {
+	tx := &types.Tx{Body: &types.TxBody{Nonce: nonce, Account: []byte("account")}}
+	tx.Hash = tx.CalculateTxHash()
+	return tx
+}
+
+func TestJournal(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mempool.dump")
+
+	jw, err := Open(path)
+	assert.NoError(t, err)
+	for i := 1; i <= 5; i++ {
+		assert.NoError(t, jw.Add(newTestTx(uint64(i))))
+	}
+	assert.NoError(t, jw.Remove(newTestTx(2).GetHash()))
+	assert.NoError(t, jw.Remove(newTestTx(4).GetHash()))
+	assert.Equal(t, 7, jw.Count())
+
+	// the records are written by Sync
+	txs, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(txs))
+	assert.NoError(t, jw.Sync())
+	txs, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(txs))
+	assert.NoError(t, jw.Close())
+
+	// a record partially written by a crash
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	file.WriteString("+,\"2kL")
+	file.Close()
+
+	txs, err = Load(path)
+	assert.Error(t, err)
+	assert.Equal(t, 3, len(txs))
+	for i, nonce := range []uint64{1, 3, 5} {
+		assert.Equal(t, nonce, txs[i].GetBody().GetNonce())
+	}
+
+	assert.NoError(t, Compact(path, txs))
+	txs, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(txs))
+
+	assert.NoError(t, Compact(path, nil))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestReadDumpFormat(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mempool.dump")
+
+	data, _ := proto.Marshal(newTestTx(1))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(enc.ToString(data)+"\n"), 0644))
+
+	txs, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(txs))
+}
@@ -0,0 +1,137 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package mempool
+
+import (
+	"bytes"
+
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+)
+
+// addCache adds tx to the cache and counts its size. It is recorded in the
+// journal. must be called with lock.
+func (mp *MemPool) addCache(tx types.Transaction) {
+	mp.cache[types.ToTxID(tx.GetHash())] = tx
+	mp.bytes += proto.Size(tx.GetTx())
+	if tx.GetBody().GetValidUntil() != 0 {
+		mp.expiring++
+	}
+	if mp.journal != nil {
+		if err := mp.journal.Add(tx.GetTx()); err != nil {
+			mp.Error().Err(err).Msg("failed to write tx to journal")
+		}
+	}
+}
+
+// deleteCache removes tx from the cache. It is recorded in the journal. must
+// be called with lock.
+func (mp *MemPool) deleteCache(tx types.Transaction) {
+	id := types.ToTxID(tx.GetHash())
+	if _, found := mp.cache[id]; found {
+		delete(mp.cache, id)
+		mp.bytes -= proto.Size(tx.GetTx())
+		if tx.GetBody().GetValidUntil() != 0 {
+			mp.expiring--
+		}
+		if mp.journal != nil {
+			if err := mp.journal.Remove(tx.GetHash()); err != nil {
+				mp.Error().Err(err).Msg("failed to write tx removal to journal")
+			}
+		}
+	}
+}
+
+// syncJournal writes the records of the last change of the pool to the
+// journal file. must be called with lock.
+func (mp *MemPool) syncJournal() {
+	if mp.journal != nil {
+		if err := mp.journal.Sync(); err != nil {
+			mp.Error().Err(err).Msg("failed to sync journal")
+		}
+	}
+}
+
+func (mp *MemPool) overflow() bool {
+	conf := mp.cfg.Mempool
+	return (conf.MaxTxCount > 0 && len(mp.cache) > conf.MaxTxCount) ||
+		(conf.MaxTxBytes > 0 && mp.bytes > conf.MaxTxBytes)
+}
+
+// enforceLimits evicts txs until the mempool is within its limits after tx is
+// added to list. It returns an error if tx itself is evicted, which is
+// reported to the submitter. must be called with lock.
+func (mp *MemPool) enforceLimits(list *TxList, tx types.Transaction) error {
+	evict := func(list *TxList, limitErr error) error {
+		evicted := mp.evictLast(list)
+		if bytes.Equal(evicted.GetHash(), tx.GetHash()) {
+			mp.rejected++
+			return limitErr
+		}
+		mp.evicted++
+		mp.Debug().Str("tx_hash", enc.ToString(evicted.GetHash())).Msg("tx evicted by the limit of mempool")
+		return nil
+	}
+
+	if limit := mp.cfg.Mempool.MaxAccountTxs; limit > 0 && list.len() > limit {
+		if err := evict(list, types.ErrTxAccountLimitExceeded); err != nil {
+			return err
+		}
+	}
+	for mp.overflow() {
+		victim := mp.evictionCandidate()
+		if victim == nil {
+			break
+		}
+		if err := evict(victim, types.ErrMempoolFull); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evictLast removes the tx of the highest nonce in list, so that the nonce
+// order of the remaining txs has no gap. must be called with lock.
+func (mp *MemPool) evictLast(list *TxList) types.Transaction {
+	tx, orphan := list.RemoveLast()
+	if orphan {
+		mp.orphan--
+	}
+	mp.deleteCache(tx)
+	mp.releaseMemPoolList(list)
+	return tx
+}
+
+// evictionCandidate returns the list whose last tx is evicted first. The
+// orphan txs, which are the farthest from being executed, go before the ready
+// ones, and then the txs of the lower gas price go first.
+func (mp *MemPool) evictionCandidate() *TxList {
+	var (
+		victim       *TxList
+		victimOrphan bool
+		victimTx     types.Transaction
+	)
+	for _, list := range mp.pool {
+		last := list.last()
+		if last == nil {
+			continue
+		}
+		orphan := list.len() > list.ready
+		if victim != nil {
+			if orphan != victimOrphan {
+				if !orphan {
+					continue
+				}
+			} else if c := effectivePrice(last).Cmp(effectivePrice(victimTx)); c > 0 ||
+				(c == 0 && bytes.Compare(list.account, victim.account) > 0) {
+				continue
+			}
+		}
+		victim, victimOrphan, victimTx = list, orphan, last
+	}
+	return victim
+}
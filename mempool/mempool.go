@@ -6,12 +6,8 @@
 package mempool
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/csv"
-	"io"
 	"math/big"
-	"os"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,6 +23,7 @@ import (
 	"github.com/aergoio/aergo/fee"
 	"github.com/aergoio/aergo/internal/common"
 	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/mempool/journal"
 	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/pkg/component"
 	"github.com/aergoio/aergo/state"
@@ -41,9 +38,10 @@ const (
 )
 
 var (
-	evictInterval  = time.Minute
-	evictPeriod    = time.Hour * types.DefaultEvictPeriod
-	metricInterval = time.Second
+	evictInterval   = time.Minute
+	evictPeriod     = time.Hour * types.DefaultEvictPeriod
+	metricInterval  = time.Second
+	compactInterval = time.Minute * 10
 )
 
 // MemPool is main structure of mempool service
@@ -59,9 +57,14 @@ type MemPool struct {
 	stateDB     *state.StateDB
 	verifier    *actor.PID
 	orphan      int
+	bytes       int
+	evicted     int
+	rejected    int
+	expiring    int
 	cache       map[types.TxID]types.Transaction
 	pool        map[types.AccountID]*TxList
 	dumpPath    string
+	journal     *journal.Writer
 	status      int32
 	coinbasefee *big.Int
 	chainIdHash []byte
@@ -138,7 +141,7 @@ func (mp *MemPool) BeforeStop() {
 	if mp.verifier != nil {
 		mp.verifier.GracefulStop()
 	}
-	mp.dumpTxsToFile()
+	mp.compactJournal(false)
 	mp.quit <- true
 	mp.wg.Wait()
 }
@@ -152,6 +155,9 @@ func (mp *MemPool) monitor() {
 	showmetric := time.NewTicker(metricInterval)
 	defer showmetric.Stop()
 
+	compact := time.NewTicker(compactInterval)
+	defer compact.Stop()
+
 	for {
 		select {
 		// Log current counts on mempool
@@ -165,6 +171,11 @@ func (mp *MemPool) monitor() {
 			if mp.cfg.Mempool.EnableFadeout {
 				mp.evictTransactions()
 			}
+			// Compact the journal if it has more records than twice the txs
+		case <-compact.C:
+			if mp.needCompaction() {
+				mp.compactJournal(true)
+			}
 
 			// Graceful quit
 		case <-mp.quit:
@@ -177,6 +188,7 @@ func (mp *MemPool) monitor() {
 func (mp *MemPool) evictTransactions() {
 	mp.Lock()
 	defer mp.Unlock()
+	defer mp.syncJournal()
 
 	total := 0
 	for acc, list := range mp.pool {
@@ -188,7 +200,7 @@ func (mp *MemPool) evictTransactions() {
 		orphan := len(txs) - list.Len()
 
 		for _, tx := range txs {
-			delete(mp.cache, types.ToTxID(tx.GetHash())) // need lock
+			mp.deleteCache(tx) // need lock
 		}
 		mp.orphan -= orphan
 		delete(mp.pool, acc)
@@ -198,6 +210,12 @@ func (mp *MemPool) evictTransactions() {
 	}
 }
 
+func (mp *MemPool) needCompaction() bool {
+	mp.RLock()
+	defer mp.RUnlock()
+	return mp.journal != nil && mp.journal.Count() > 2*len(mp.cache)
+}
+
 // Size returns current maintaining number of transactions
 // and number of orphan transaction
 func (mp *MemPool) Size() (int, int) {
@@ -236,6 +254,21 @@ func (mp *MemPool) Receive(context actor.Context) {
 
 		txs := mp.existEx(bucketHash)
 		context.Respond(&message.MemPoolExistExRsp{Txs: txs})
+	case *message.MemPoolList:
+		txs, cursor, err := mp.listTxs(msg.Cursor, int(msg.Limit))
+		context.Respond(&message.MemPoolListRsp{
+			Txs:        txs,
+			NextCursor: cursor,
+			Err:        err,
+		})
+	case *message.MemPoolAccount:
+		info, err := mp.accountInfo(msg.Account)
+		context.Respond(&message.MemPoolAccountRsp{
+			Account: info,
+			Err:     err,
+		})
+	case *message.MemPoolStats:
+		context.Respond(&message.MemPoolStatsRsp{Stats: mp.stats()})
 	case *actor.Started:
 		mp.loadTxs() // FIXME :work-around for actor settled
 
@@ -246,9 +279,12 @@ func (mp *MemPool) Receive(context actor.Context) {
 
 func (mp *MemPool) Statistics() *map[string]interface{} {
 	return &map[string]interface{}{
-		"total":  len(mp.cache),
-		"orphan": mp.orphan,
-		"dead":   mp.deadtx,
+		"total":    len(mp.cache),
+		"orphan":   mp.orphan,
+		"dead":     mp.deadtx,
+		"bytes":    mp.bytes,
+		"evicted":  mp.evicted,
+		"rejected": mp.rejected,
 	}
 }
 
@@ -259,15 +295,14 @@ func (mp *MemPool) get(maxBlockBodySize uint32) ([]types.Transaction, error) {
 	count := 0
 	size := 0
 	txs := make([]types.Transaction, 0)
-Gather:
-	for _, list := range mp.pool {
-		for _, tx := range list.Get() {
-			if size += proto.Size(tx.GetTx()); uint32(size) > maxBlockBodySize {
-				break Gather
-			}
-			txs = append(txs, tx)
-			count++
+	// the txs of higher gas price go first, keeping the nonce order of each account
+	for q := newTxPriceQueue(mp.pool); q.Len() > 0; q.next() {
+		tx := q.peek()
+		if size += proto.Size(tx.GetTx()); uint32(size) > maxBlockBodySize {
+			break
 		}
+		txs = append(txs, tx)
+		count++
 	}
 	elapsed := time.Since(start)
 	mp.Debug().Str("elapsed", elapsed.String()).Int("len", len(mp.cache)).Int("orphan", mp.orphan).Int("count", count).Msg("total tx returned")
@@ -286,6 +321,7 @@ func (mp *MemPool) put(tx types.Transaction) error {
 
 	mp.Lock()
 	defer mp.Unlock()
+	defer mp.syncJournal()
 	if _, found := mp.cache[id]; found {
 		return types.ErrTxAlreadyInMempool
 	}
@@ -306,13 +342,24 @@ func (mp *MemPool) put(tx types.Transaction) error {
 	}
 	defer mp.releaseMemPoolList(list)
 	diff, err := list.Put(tx)
-	if err != nil {
+	if err == types.ErrSameNonceAlreadyInMempool {
+		replaced, err := list.Replace(tx, mp.cfg.Mempool.PriceBump)
+		if err != nil {
+			return err
+		}
+		mp.deleteCache(replaced)
+		mp.Debug().Str("tx_hash", enc.ToString(tx.GetHash())).
+			Str("replaced", enc.ToString(replaced.GetHash())).Msg("tx replaced by fee")
+	} else if err != nil {
 		mp.Error().Err(err).Msg("fail to put at a mempool list")
 		return err
 	}
 
 	mp.orphan -= diff
-	mp.cache[id] = tx
+	mp.addCache(tx)
+	if err := mp.enforceLimits(list, tx); err != nil {
+		return err
+	}
 	mp.Debug().Str("tx_hash", enc.ToString(tx.GetHash())).Msgf("tx add-ed size(%d, %d)", len(mp.cache), mp.orphan)
 
 	if !mp.testConfig {
@@ -367,6 +414,7 @@ func (mp *MemPool) removeOnBlockArrival(block *types.Block) error {
 	start := time.Now()
 	mp.Lock()
 	defer mp.Unlock()
+	defer mp.syncJournal()
 
 	check := 0
 	all := false
@@ -405,11 +453,12 @@ func (mp *MemPool) removeOnBlockArrival(block *types.Block) error {
 		diff, delTxs := list.FilterByState(ns)
 		mp.orphan -= diff
 		for _, tx := range delTxs {
-			delete(mp.cache, types.ToTxID(tx.GetHash())) // need lock
+			mp.deleteCache(tx) // need lock
 		}
 		mp.releaseMemPoolList(list)
 		check++
 	}
+	mp.removeExpired(mp.bestBlockNo + 1)
 
 	//FOR TEST
 	for _, tx := range block.GetBody().GetTxs() {
@@ -430,13 +479,45 @@ func (mp *MemPool) removeOnBlockArrival(block *types.Block) error {
 	return nil
 }
 
+// removeExpired drops the txs which can't be included in the block of blockNo
+// or later due to their validity window. must be called with lock.
+func (mp *MemPool) removeExpired(blockNo types.BlockNo) {
+	if mp.expiring == 0 {
+		return
+	}
+	for _, list := range mp.pool {
+		diff, delTxs := list.FilterExpired(blockNo)
+		mp.orphan -= diff
+		for _, tx := range delTxs {
+			mp.Debug().Str("tx_hash", enc.ToString(tx.GetHash())).Msg("tx expired")
+			mp.deleteCache(tx)
+		}
+		mp.releaseMemPoolList(list)
+	}
+}
+
 // signiture verification
 func (mp *MemPool) verifyTx(tx types.Transaction) error {
 	err := tx.Validate(mp.chainIdHash)
 	if err != nil {
 		return err
 	}
-	if !tx.GetTx().NeedNameVerify() {
+	if tx.GetBody().GetType() == types.TxType_MULTISIG {
+		keys := tx.GetBody().GetMultisig()
+		if keys == nil {
+			mp.RLock()
+			st, err := mp.getAccountState(tx.GetBody().GetAccount())
+			mp.RUnlock()
+			if err != nil {
+				return err
+			}
+			keys = st.GetMultisig()
+		}
+		err = key.VerifyMultisigTx(tx.GetTx(), keys)
+		if err != nil {
+			return err
+		}
+	} else if !tx.GetTx().NeedNameVerify() {
 		err = key.VerifyTx(tx.GetTx())
 		if err != nil {
 			return err
@@ -478,6 +559,9 @@ func (mp *MemPool) getAddress(account []byte) []byte {
 // check if recipient is valid name
 // check tx account is lower than known value
 func (mp *MemPool) validateTx(tx types.Transaction, account types.Address) error {
+	if err := tx.GetBody().ValidateWindow(mp.bestBlockNo + 1); err != nil {
+		return err
+	}
 
 	ns, err := mp.getAccountState(account)
 	if err != nil {
@@ -493,7 +577,7 @@ func (mp *MemPool) validateTx(tx types.Transaction, account types.Address) error
 	//this will be refactored soon
 
 	switch tx.GetBody().GetType() {
-	case types.TxType_NORMAL:
+	case types.TxType_NORMAL, types.TxType_FEE_DELEGATION, types.TxType_MULTISIG, types.TxType_REDEPLOY:
 		if tx.GetTx().HasNameRecipient() {
 			recipient := tx.GetBody().GetRecipient()
 			recipientAddr := mp.getAddress(recipient)
@@ -501,6 +585,23 @@ func (mp *MemPool) validateTx(tx types.Transaction, account types.Address) error
 				return types.ErrTxInvalidRecipient
 			}
 		}
+		if tx.GetBody().GetType() == types.TxType_FEE_DELEGATION {
+			if bytes.Equal(account, tx.GetBody().GetFeePayer()) {
+				return types.ErrTxInvalidFeePayer
+			}
+			payerState, err := mp.getAccountState(tx.GetBody().GetFeePayer())
+			if err != nil {
+				return err
+			}
+			if err := tx.ValidateWithFeePayerState(payerState); err != nil {
+				return err
+			}
+			// the payer must be able to pay the fees of its pending txs as well
+			pending := mp.pendingPayerFee(tx)
+			if new(big.Int).Add(pending, tx.GetMaxFee()).Cmp(payerState.GetBalanceBigInt()) > 0 {
+				return types.ErrInsufficientFeePayerBalance
+			}
+		}
 	case types.TxType_GOVERNANCE:
 		aergoState, err := mp.getAccountState(tx.GetBody().GetRecipient())
 		if err != nil {
@@ -534,10 +635,37 @@ func (mp *MemPool) validateTx(tx types.Transaction, account types.Address) error
 				return err
 			}
 		}
+	case types.TxType_BATCH:
+		for _, op := range tx.GetBody().GetOps() {
+			recipient := op.GetRecipient()
+			if op.GetType() == types.TxType_NORMAL && len(recipient) <= types.NameLength &&
+				mp.getAddress(recipient) == nil {
+				return types.ErrTxInvalidRecipient
+			}
+		}
 	}
 	return err
 }
 
+// pendingPayerFee returns the sum of the max fees of the txs in the pool,
+// which the fee payer of tx pays. The tx of the same sender and nonce isn't
+// counted, since tx replaces it.
+func (mp *MemPool) pendingPayerFee(tx types.Transaction) *big.Int {
+	body := tx.GetBody()
+	sum := new(big.Int)
+	for _, v := range mp.cache {
+		vBody := v.GetBody()
+		if vBody.GetType() != types.TxType_FEE_DELEGATION || !bytes.Equal(vBody.GetFeePayer(), body.GetFeePayer()) {
+			continue
+		}
+		if vBody.GetNonce() == body.GetNonce() && bytes.Equal(vBody.GetAccount(), body.GetAccount()) {
+			continue
+		}
+		sum.Add(sum, v.GetMaxFee())
+	}
+	return sum
+}
+
 func (mp *MemPool) exist(hash []byte) *types.Tx {
 	v := make([]types.TxHash, 1)
 	v[0] = hash
@@ -622,6 +750,7 @@ func (mp *MemPool) notifyNewTx(tx types.Transaction) {
 	mp.RequestTo(message.P2PSvc, &message.NotifyNewTransactions{
 		Txs: []*types.Tx{tx.GetTx()},
 	})
+	mp.TellTo(message.RPCSvc, tx.GetTx())
 }
 
 func (mp *MemPool) loadTxs() {
@@ -629,94 +758,68 @@ func (mp *MemPool) loadTxs() {
 	if !atomic.CompareAndSwapInt32(&mp.status, initial, loading) {
 		return
 	}
-	defer atomic.StoreInt32(&mp.status, running)
-	file, err := os.Open(mp.dumpPath)
+
+	txs, err := journal.Load(mp.dumpPath)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			mp.Error().Err(err).Msg("Unable to open dump file")
-		}
-		return
+		mp.Error().Err(err).Msg("err on reading journal during loading")
 	}
-
-	defer file.Close() // nolint: errcheck
-	reader := csv.NewReader(bufio.NewReader(file))
-
-	var count int
-	for {
-		buf := types.Tx{}
-		rc, err := reader.Read()
-		if err != nil {
-			if err != io.EOF {
-				mp.Error().Err(err).Msg("err on read file during loading")
-			}
-			break
-		}
-		count++
-		dataBuf, err := enc.ToBytes(rc[0])
-		if err != nil {
-			mp.Error().Err(err).Msg("err on decoding tx during loading")
-			continue
-		}
-		err = proto.Unmarshal(dataBuf, &buf)
-		if err != nil {
-			mp.Error().Err(err).Msg("errr on unmarshalling tx during loading")
-			continue
-		}
-		mp.put(types.NewTransaction(&buf)) // nolint: errcheck
+	for _, tx := range txs {
+		mp.put(types.NewTransaction(tx)) // nolint: errcheck
 	}
 
-	mp.Info().Int("try", count).
-		Int("drop", count-len(mp.cache)-mp.orphan).
+	mp.Info().Int("try", len(txs)).
+		Int("drop", len(txs)-len(mp.cache)).
 		Int("suceed", len(mp.cache)).
 		Int("orphan", mp.orphan).
 		Msg("loading mempool done")
+
+	atomic.StoreInt32(&mp.status, running)
+	mp.compactJournal(true)
 }
 
 func (mp *MemPool) isRunning() bool {
 	if atomic.LoadInt32(&mp.status) != running {
-		mp.Info().Msg("skip to dump txs because mempool is not running yet")
+		mp.Info().Msg("skip to write journal because mempool is not running yet")
 		return false
 	}
 	return true
 }
-func (mp *MemPool) dumpTxsToFile() {
 
+// compactJournal rewrites the journal with the txs in the pool, dropping the
+// records of the removed txs. If reopen is true, the journal is opened again
+// to record the following changes of the pool.
+func (mp *MemPool) compactJournal(reopen bool) {
 	if !mp.isRunning() {
 		return
 	}
-	if len, _ := mp.Size(); len == 0 {
-		os.Remove(mp.dumpPath) // nolint: errcheck
-		return
-	}
-
-	file, err := os.Create(mp.dumpPath)
-	if err != nil {
-		mp.Error().Err(err).Msg("Unable to create file")
-		return
-	}
-	defer file.Close() // nolint: errcheck
-
-	writer := csv.NewWriter(bufio.NewWriter(file))
-	defer writer.Flush() //nolint: errcheck
 
 	mp.Lock()
 	defer mp.Unlock()
-	count := 0
+
+	if mp.journal != nil {
+		mp.journal.Close() // nolint: errcheck
+		mp.journal = nil
+	}
+
+	txs := make([]*types.Tx, 0, len(mp.cache))
 	for _, list := range mp.pool {
 		for _, v := range list.GetAll() {
-			data, err := proto.Marshal(v.GetTx())
-			if err != nil {
-				continue
-			}
+			txs = append(txs, v.GetTx())
+		}
+	}
+	if err := journal.Compact(mp.dumpPath, txs); err != nil {
+		// keep appending to the existing journal, which is still valid
+		mp.Error().Err(err).Str("path", mp.dumpPath).Msg("failed to compact journal")
+	} else {
+		mp.Info().Int("count", len(txs)).Str("path", mp.dumpPath).Msg("compact journal")
+	}
 
-			strData := enc.ToString(data)
-			err = writer.Write([]string{strData})
-			if err != nil {
-				mp.Error().Err(err).Msg("writing encoded tx fail")
-				break
-			}
-			count++
+	if reopen {
+		jw, err := journal.Open(mp.dumpPath)
+		if err != nil {
+			mp.Error().Err(err).Str("path", mp.dumpPath).Msg("failed to open journal")
+			return
 		}
+		mp.journal = jw
 	}
-	mp.Info().Int("count", count).Str("path", mp.dumpPath).Msg("dump txs")
 }
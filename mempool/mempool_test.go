@@ -14,6 +14,7 @@ import (
 
 	"github.com/aergoio/aergo/account/key"
 	"github.com/aergoio/aergo/config"
+	"github.com/aergoio/aergo/fee"
 	"github.com/aergoio/aergo/types"
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/stretchr/testify/assert"
@@ -247,6 +248,8 @@ func TestOrphanTransaction(t *testing.T) {
 func TestBasics2(t *testing.T) {
 	initTest(t)
 	defer deinitTest()
+	// all the txs are kept beyond the default limit
+	pool.cfg.Mempool.MaxTxCount = 0
 	txs := make([]*types.Tx, 0)
 
 	accCount := 1000
@@ -499,7 +502,7 @@ func TestDumpAndLoad(t *testing.T) {
 		}
 	}
 
-	pool.dumpTxsToFile()
+	pool.compactJournal(false)
 	if _, err := os.Stat(pool.dumpPath); !os.IsNotExist(err) {
 		t.Errorf("err should be NotExist ,but %s", err.Error())
 	}
@@ -507,7 +510,7 @@ func TestDumpAndLoad(t *testing.T) {
 	if !atomic.CompareAndSwapInt32(&pool.status, initial, running) {
 		t.Errorf("pool status should be initial, but %d", pool.status)
 	}
-	pool.dumpTxsToFile()
+	pool.compactJournal(false)
 	if _, err := os.Stat(pool.dumpPath); !os.IsNotExist(err) {
 		t.Errorf("err should be NotExist ,but %s", err.Error())
 	}
@@ -520,7 +523,7 @@ func TestDumpAndLoad(t *testing.T) {
 		}
 	}
 
-	pool.dumpTxsToFile()
+	pool.compactJournal(false)
 	if _, err := os.Stat(pool.dumpPath); err != nil {
 		t.Errorf("dump file should be created but, %s", err.Error())
 	}
@@ -602,3 +605,148 @@ func TestDeleteInvokePriceFilterOut(t *testing.T) {
 	simulateBlockGen(txs[1:2]...)
 	checkRemainder(0, 0)
 }
+
+func genTxWithPrice(acc int, nonce uint64, price int64) types.Transaction {
+	tx := genTx(acc, 0, nonce, 1).GetTx()
+	tx.Body.GasLimit = fee.TxBaseGas
+	tx.Body.GasPrice = big.NewInt(price).Bytes()
+	tx.Hash = tx.CalculateTxHash()
+	return types.NewTransaction(tx)
+}
+
+func TestReplaceByFee(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	old := genTxWithPrice(0, 1, 100)
+	assert.NoError(t, pool.put(old))
+	assert.Equal(t, types.ErrTxPriceTooLowToReplace, pool.put(genTxWithPrice(0, 1, 109)))
+
+	replacing := genTxWithPrice(0, 1, 110)
+	assert.NoError(t, pool.put(replacing))
+	assert.Nil(t, pool.exist(old.GetHash()))
+	assert.NotNil(t, pool.exist(replacing.GetHash()))
+
+	total, orphan := pool.Size()
+	assert.EqualValuesf(t, []int{total, orphan}, []int{1, 0}, "wrong mempool stat")
+}
+
+func TestGetByPrice(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	pool.put(genTxWithPrice(0, 1, 10))
+	pool.put(genTxWithPrice(0, 2, 50))
+	pool.put(genTxWithPrice(1, 1, 30))
+	pool.put(genTxWithPrice(2, 1, 20))
+	// the gas price of the tx without a gas limit isn't paid
+	legacy := genTx(3, 0, 1, 1).GetTx()
+	legacy.Body.GasPrice = big.NewInt(100).Bytes()
+	legacy.Hash = legacy.CalculateTxHash()
+	pool.put(types.NewTransaction(legacy))
+
+	txs, err := pool.get(maxBlockBodySize)
+	assert.NoError(t, err)
+	var prices []int64
+	for _, tx := range txs {
+		prices = append(prices, tx.GetBody().GetGasPriceBigInt().Int64())
+	}
+	// the txs of an account are kept in the order of nonce
+	assert.Equal(t, []int64{30, 20, 10, 50, 100}, prices)
+}
+
+func TestMempoolLimits(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	pool.cfg.Mempool.MaxTxCount = 3
+	pool.cfg.Mempool.MaxAccountTxs = 2
+
+	assert.NoError(t, pool.put(genTxWithPrice(0, 1, 10)))
+	assert.NoError(t, pool.put(genTxWithPrice(0, 2, 10)))
+	assert.Equal(t, types.ErrTxAccountLimitExceeded, pool.put(genTxWithPrice(0, 3, 10)))
+
+	// the orphan goes first
+	orphan := genTxWithPrice(1, 5, 50)
+	assert.NoError(t, pool.put(orphan))
+	assert.NoError(t, pool.put(genTxWithPrice(2, 1, 20)))
+	assert.Nil(t, pool.exist(orphan.GetHash()))
+
+	// the lowest price goes next
+	assert.NoError(t, pool.put(genTxWithPrice(3, 1, 30)))
+	total, orphans := pool.Size()
+	assert.EqualValuesf(t, []int{total, orphans}, []int{3, 0}, "wrong mempool stat")
+	txs, _ := pool.get(maxBlockBodySize)
+	var prices []int64
+	for _, tx := range txs {
+		prices = append(prices, tx.GetBody().GetGasPriceBigInt().Int64())
+	}
+	assert.Equal(t, []int64{30, 20, 10}, prices)
+
+	// a tx of the lowest price is rejected when the mempool is full
+	assert.Equal(t, types.ErrMempoolFull, pool.put(genTxWithPrice(4, 1, 5)))
+	assert.Equal(t, 2, pool.evicted)
+	assert.Equal(t, 2, pool.rejected)
+}
+
+func TestInspect(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	for _, nonce := range []uint64{1, 2, 5, 7} {
+		pool.put(genTx(0, 0, nonce, 1))
+	}
+	pool.put(genTx(1, 0, 1, 1))
+
+	info, err := pool.accountInfo(accs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), info.Ready)
+	assert.Equal(t, uint32(2), info.Orphan)
+	assert.Equal(t, []*types.NonceGap{{From: 3, To: 4}, {From: 6, To: 6}}, info.Gaps)
+	assert.Equal(t, 4, len(info.Txs))
+	assert.True(t, info.Txs[1].Ready)
+	assert.False(t, info.Txs[3].Ready)
+	assert.Equal(t, "nonce gap: waiting for the tx of nonce 3", info.Txs[3].Reason)
+
+	var listed []*types.MempoolTx
+	var cursor []byte
+	for {
+		txs, next, err := pool.listTxs(cursor, 2)
+		assert.NoError(t, err)
+		listed = append(listed, txs...)
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, 5, len(listed))
+
+	stats := pool.stats()
+	assert.Equal(t, uint64(5), stats.Total)
+	assert.Equal(t, uint64(2), stats.Orphan)
+	assert.Equal(t, uint64(2), stats.Accounts)
+}
+
+func genTxWithWindow(acc int, nonce uint64, notBefore, validUntil uint64) types.Transaction {
+	tx := genTx(acc, 0, nonce, 1).GetTx()
+	tx.Body.NotBefore = notBefore
+	tx.Body.ValidUntil = validUntil
+	tx.Hash = tx.CalculateTxHash()
+	return types.NewTransaction(tx)
+}
+
+func TestValidityWindow(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	assert.Equal(t, types.ErrTxNotYetValid, pool.put(genTxWithWindow(0, 1, 5, 0)))
+
+	assert.NoError(t, pool.put(genTxWithWindow(0, 1, 0, 3)))
+	assert.NoError(t, pool.put(genTxWithWindow(0, 2, 0, 0)))
+	assert.NoError(t, pool.put(genTxWithWindow(1, 1, 1, 10)))
+
+	pool.bestBlockNo = 3
+	simulateBlockGen()
+	total, orphan := pool.Size()
+	assert.EqualValuesf(t, []int{total, orphan}, []int{2, 1}, "wrong mempool stat")
+	assert.Equal(t, types.ErrTxExpired, pool.put(genTxWithWindow(0, 1, 0, 3)))
+}
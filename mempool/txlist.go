@@ -121,6 +121,54 @@ func (tl *TxList) Put(tx types.Transaction) (int, error) {
 	return oldCnt - newCnt, nil
 }
 
+// Replace replaces the transaction which has the same nonce as tx, if tx pays
+// at least priceBump percent more gas price. The replaced one is returned.
+func (tl *TxList) Replace(tx types.Transaction, priceBump uint32) (types.Transaction, error) {
+	tl.Lock()
+	defer tl.Unlock()
+
+	index, found := tl.search(tx)
+	if !found {
+		return nil, types.ErrTxNotFound
+	}
+	old := tl.list[index]
+	if !enoughPriceBump(old, tx, priceBump) {
+		return nil, types.ErrTxPriceTooLowToReplace
+	}
+	tl.list[index] = tx
+
+	tl.lastTime = time.Now()
+	return old, nil
+}
+
+// RemoveLast removes the transaction of the highest nonce, and reports
+// whether it was an orphan
+func (tl *TxList) RemoveLast() (types.Transaction, bool) {
+	tl.Lock()
+	defer tl.Unlock()
+
+	n := len(tl.list)
+	if n == 0 {
+		return nil, false
+	}
+	tx := tl.list[n-1]
+	tl.list = tl.list[:n-1]
+
+	orphan := true
+	if tl.ready == n {
+		tl.ready--
+		orphan = false
+	}
+	return tx, orphan
+}
+
+func (tl *TxList) last() types.Transaction {
+	if len(tl.list) == 0 {
+		return nil
+	}
+	return tl.list[len(tl.list)-1]
+}
+
 // SetMinNonce sets new minimum nonce for TxList
 // evict on some transactions is possible due to minimum nonce
 func (tl *TxList) FilterByState(st *types.State) (int, []types.Transaction) {
@@ -169,6 +217,40 @@ func (tl *TxList) FilterByState(st *types.State) (int, []types.Transaction) {
 	return oldCnt - newCnt, removed
 }
 
+// FilterExpired removes the transactions which can't be included in the block
+// of blockNo or later. The transactions after a removed one become orphans
+// until the nonce gap is filled.
+func (tl *TxList) FilterExpired(blockNo types.BlockNo) (int, []types.Transaction) {
+	tl.Lock()
+	defer tl.Unlock()
+
+	oldCnt := len(tl.list) - tl.ready
+	var left, removed []types.Transaction
+	for _, x := range tl.list {
+		if x.GetBody().IsExpired(blockNo) {
+			removed = append(removed, x)
+		} else {
+			left = append(left, x)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	tl.list = left
+	tl.ready = 0
+	for i := 0; i < len(tl.list); i++ {
+		if !tl.continuous(i) {
+			break
+		}
+		tl.ready++
+	}
+	newCnt := len(tl.list) - tl.ready
+
+	tl.lastTime = time.Now()
+	return oldCnt - newCnt, removed
+}
+
 // FilterByPrice will evict transactions that needs more amount than balance
 /*
 func (tl *TxList) FilterByPrice(balance uint64) error {
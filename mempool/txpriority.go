@@ -0,0 +1,111 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package mempool
+
+import (
+	"bytes"
+	"container/heap"
+	"math/big"
+
+	"github.com/aergoio/aergo/fee"
+	"github.com/aergoio/aergo/types"
+)
+
+var hundred = big.NewInt(100)
+
+// txCursor points the first tx of an account which is not yet taken.
+type txCursor struct {
+	txs   []types.Transaction
+	price *big.Int
+}
+
+func newTxCursor(txs []types.Transaction) *txCursor {
+	return &txCursor{txs: txs, price: effectivePrice(txs[0])}
+}
+
+// effectivePrice returns the gas price which the tx actually pays. The tx
+// without a gas limit pays the payload based fee regardless of its gas price,
+// so its price is the fee per its intrinsic gas.
+func effectivePrice(tx types.Transaction) *big.Int {
+	body := tx.GetBody()
+	if fee.IsGasMetered(body.GetGasLimit()) {
+		return body.GetGasPriceBigInt()
+	}
+	gas := fee.TxGas(len(body.GetPayload()))
+	if gas == 0 {
+		// zero fee
+		return new(big.Int)
+	}
+	return new(big.Int).Div(fee.PayloadTxFee(len(body.GetPayload())), new(big.Int).SetUint64(gas))
+}
+
+// txPriceQueue orders the ready txs of the accounts by their gas price. Only
+// the first tx of each account is in the queue, so that the txs of an account
+// are taken in the order of nonce.
+type txPriceQueue []*txCursor
+
+func newTxPriceQueue(lists map[types.AccountID]*TxList) *txPriceQueue {
+	q := make(txPriceQueue, 0, len(lists))
+	for _, list := range lists {
+		if txs := list.Get(); len(txs) > 0 {
+			q = append(q, newTxCursor(txs))
+		}
+	}
+	heap.Init(&q)
+	return &q
+}
+
+func (q txPriceQueue) Len() int { return len(q) }
+
+func (q txPriceQueue) Less(i, j int) bool {
+	if c := q[i].price.Cmp(q[j].price); c != 0 {
+		return c > 0
+	}
+	// the order of the same price is fixed by the account
+	return bytes.Compare(q[i].txs[0].GetBody().GetAccount(), q[j].txs[0].GetBody().GetAccount()) < 0
+}
+
+func (q txPriceQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *txPriceQueue) Push(x interface{}) {
+	*q = append(*q, x.(*txCursor))
+}
+
+func (q *txPriceQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	c := old[n-1]
+	*q = old[:n-1]
+	return c
+}
+
+// peek returns the tx with the highest gas price.
+func (q *txPriceQueue) peek() types.Transaction {
+	return (*q)[0].txs[0]
+}
+
+// next moves to the next tx of the account whose tx is taken by peek.
+func (q *txPriceQueue) next() {
+	c := (*q)[0]
+	if c.txs = c.txs[1:]; len(c.txs) == 0 {
+		heap.Pop(q)
+		return
+	}
+	c.price = effectivePrice(c.txs[0])
+	heap.Fix(q, 0)
+}
+
+// enoughPriceBump reports whether tx pays at least bump percent more
+// effective gas price than old.
+func enoughPriceBump(old, tx types.Transaction, bump uint32) bool {
+	oldPrice := effectivePrice(old)
+	newPrice := effectivePrice(tx)
+	if newPrice.Cmp(oldPrice) <= 0 {
+		return false
+	}
+	required := new(big.Int).Mul(oldPrice, big.NewInt(int64(bump)+100))
+	return new(big.Int).Mul(newPrice, hundred).Cmp(required) >= 0
+}
@@ -14,6 +14,28 @@ type CreateAccountRsp struct {
 	Account *types.Account
 }
 
+// CreateAccountMnemonic is the request to make a new HD wallet and its first
+// account
+type CreateAccountMnemonic struct {
+	Passphrase string
+}
+
+// RecoverAccounts is the request to recover the accounts of a HD wallet from
+// the mnemonic
+type RecoverAccounts struct {
+	Mnemonic   string
+	Account    uint32
+	Index      uint32
+	Count      uint32
+	Passphrase string
+}
+
+type HDAccountsRsp struct {
+	Mnemonic string
+	Accounts []*types.Account
+	Err      error
+}
+
 type LockAccount struct {
 	Account    *types.Account
 	Passphrase string
@@ -77,6 +99,7 @@ type ImportAccountRsp struct {
 type ExportAccount struct {
 	Account *types.Account
 	Pass    string
+	Legacy  bool
 }
 
 type ExportAccountRsp struct {
@@ -45,8 +45,13 @@ type AddBlockRsp struct {
 	BlockHash []byte
 	Err       error
 }
+
+// GetState is a request for the state of an account. Block is an optional
+// block hash or 8 byte little endian block number to look the state up at;
+// the best block is used if it is empty.
 type GetState struct {
 	Account []byte
+	Block   []byte
 }
 type GetStateRsp struct {
 	Account []byte
@@ -57,6 +62,7 @@ type GetStateAndProof struct {
 	Account    []byte
 	Root       []byte
 	Compressed bool
+	Block      []byte
 }
 type GetStateAndProofRsp struct {
 	StateProof *types.AccountProof
@@ -87,19 +93,53 @@ type GetABIRsp struct {
 	Err error
 }
 
+type GetCodeVersions struct {
+	Contract []byte
+}
+type GetCodeVersionsRsp struct {
+	Versions *types.CodeVersionList
+	Err      error
+}
+
+type VerifyContract struct {
+	Contract        []byte
+	Source          string
+	CompilerVersion string
+}
+type GetContractSource struct {
+	Contract []byte
+}
+type GetContractSourceRsp struct {
+	Source *types.ContractSource
+	Err    error
+}
+
 type GetQuery struct {
 	Contract  []byte
 	Queryinfo []byte
+	Block     []byte
 }
 type GetQueryRsp struct {
 	Result []byte
 	Err    error
 }
+
+// SimulateTx is a request to execute a tx against the best state without
+// committing it.
+type SimulateTx struct {
+	Tx *types.Tx
+}
+type SimulateTxRsp struct {
+	Receipt *types.Receipt
+	Err     error
+}
+
 type GetStateQuery struct {
 	ContractAddress []byte
 	StorageKeys     []string
 	Root            []byte
 	Compressed      bool
+	Block           []byte
 }
 type GetStateQueryRsp struct {
 	Result *types.StateQueryProof
@@ -182,6 +222,74 @@ type ListEvents struct {
 
 // response to p2p for GetAncestor message
 type ListEventsRsp struct {
-	Events []*types.Event
-	Err    error
+	Events     []*types.Event
+	NextCursor []byte
+	Err        error
+}
+
+type ListAccountTxs struct {
+	Address []byte
+	Cursor  []byte
+	Limit   uint32
+	Desc    bool
+}
+
+type ListAccountTxsRsp struct {
+	Txs        []*types.TxInBlock
+	NextCursor []byte
+	Err        error
+}
+
+// receive from p2p for the snapshot sync of the other nodes
+type GetStateRange struct {
+	Root  []byte
+	Start []byte
+	Size  int
+}
+
+type GetStateRangeRsp struct {
+	Keys    [][]byte
+	Values  [][]byte
+	HasNext bool
+	Err     error
+}
+
+type GetContractCode struct {
+	Hashes [][]byte
+}
+
+type GetContractCodeRsp struct {
+	Codes [][]byte
+	Err   error
+}
+
+// ImportStateRange adds the leaves of a trie downloaded by the snapshot sync.
+// Done is set for the last leaves of the trie.
+type ImportStateRange struct {
+	Root   []byte
+	Keys   [][]byte
+	Values [][]byte
+	Done   bool
+}
+
+type ImportStateRangeRsp struct {
+	Err error
+}
+
+type ImportContractCode struct {
+	Codes [][]byte
+}
+
+type ImportContractCodeRsp struct {
+	Err error
+}
+
+// InstallSnapshot makes the downloaded state of Block the best state and
+// Block the best block.
+type InstallSnapshot struct {
+	Block *types.Block
+}
+
+type InstallSnapshotRsp struct {
+	Err error
 }
@@ -63,3 +63,37 @@ type MemPoolDel struct {
 type MemPoolDelRsp struct {
 	Err error
 }
+
+// MemPoolList is interface of MemPool service for listing pending
+// transactions page by page
+type MemPoolList struct {
+	Cursor []byte
+	Limit  uint32
+}
+
+// MemPoolListRsp defines struct of result for MemPoolList
+type MemPoolListRsp struct {
+	Txs        []*types.MempoolTx
+	NextCursor []byte
+	Err        error
+}
+
+// MemPoolAccount is interface of MemPool service for inspecting pending
+// transactions of an account
+type MemPoolAccount struct {
+	Account []byte
+}
+
+// MemPoolAccountRsp defines struct of result for MemPoolAccount
+type MemPoolAccountRsp struct {
+	Account *types.MempoolAccount
+	Err     error
+}
+
+// MemPoolStats is interface of MemPool service for retrieving statistics
+type MemPoolStats struct{}
+
+// MemPoolStatsRsp defines struct of result for MemPoolStats
+type MemPoolStatsRsp struct {
+	Stats *types.MempoolStats
+}
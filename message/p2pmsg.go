@@ -181,6 +181,43 @@ type GetHashByNoRsp struct {
 	Err       error
 }
 
+// GetStateChunk requests a range of the leaves of a trie to a peer for the
+// snapshot sync. The p2p actor sends GetStateChunkRsp to the syncer.
+type GetStateChunk struct {
+	Seq    uint64
+	ToWhom peer.ID
+	Root   []byte
+	Start  []byte
+	Size   uint32
+	TTL    time.Duration
+}
+
+type GetStateChunkRsp struct {
+	Seq     uint64
+	ToWhom  peer.ID
+	Root    []byte
+	Keys    [][]byte
+	Values  [][]byte
+	HasNext bool
+	Err     error
+}
+
+// GetCodeChunk requests the contract codes to a peer for the snapshot sync.
+// The p2p actor sends GetCodeChunkRsp to the syncer.
+type GetCodeChunk struct {
+	Seq    uint64
+	ToWhom peer.ID
+	Hashes [][]byte
+	TTL    time.Duration
+}
+
+type GetCodeChunkRsp struct {
+	Seq    uint64
+	ToWhom peer.ID
+	Codes  [][]byte
+	Err    error
+}
+
 type GetSelf struct {
 }
 
@@ -121,6 +121,32 @@ func (p2ps *P2P) GetBlockHashByNo(context actor.Context, msg *message.GetHashByN
 	receiver.StartGet()
 }
 
+// GetStateChunk send request message to peer and make response message for a range of state trie leaves
+func (p2ps *P2P) GetStateChunk(context actor.Context, msg *message.GetStateChunk) {
+	peerID := msg.ToWhom
+	remotePeer, exists := p2ps.pm.GetPeer(peerID)
+	if !exists {
+		p2ps.Warn().Str(p2putil.LogPeerID, p2putil.ShortForm(peerID)).Str(p2putil.LogProtoID, subproto.GetStateRangeRequest.String()).Msg("Invalid peerID")
+		context.Respond(&message.GetStateChunkRsp{Seq: msg.Seq, ToWhom: peerID, Root: msg.Root, Err: message.PeerNotFoundError})
+		return
+	}
+	receiver := NewStateRangeReceiver(p2ps, remotePeer, msg.Seq, msg.Root, msg.Start, msg.Size, msg.TTL)
+	receiver.StartGet()
+}
+
+// GetCodeChunk send request message to peer and make response message for contract codes
+func (p2ps *P2P) GetCodeChunk(context actor.Context, msg *message.GetCodeChunk) {
+	peerID := msg.ToWhom
+	remotePeer, exists := p2ps.pm.GetPeer(peerID)
+	if !exists {
+		p2ps.Warn().Str(p2putil.LogPeerID, p2putil.ShortForm(peerID)).Str(p2putil.LogProtoID, subproto.GetContractCodeRequest.String()).Msg("Invalid peerID")
+		context.Respond(&message.GetCodeChunkRsp{Seq: msg.Seq, ToWhom: peerID, Err: message.PeerNotFoundError})
+		return
+	}
+	receiver := NewContractCodeReceiver(p2ps, remotePeer, msg.Seq, msg.Hashes, msg.TTL)
+	receiver.StartGet()
+}
+
 // NotifyNewBlock send notice message of new block to a peer
 func (p2ps *P2P) NotifyNewBlock(newBlock message.NotifyNewBlock) bool {
 	req := &types.NewBlockNotice{
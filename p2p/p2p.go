@@ -172,6 +172,10 @@ func (p2ps *P2P) Receive(context actor.Context) {
 		p2ps.GetBlockHashes(context, msg)
 	case *message.GetHashByNo:
 		p2ps.GetBlockHashByNo(context, msg)
+	case *message.GetStateChunk:
+		p2ps.GetStateChunk(context, msg)
+	case *message.GetCodeChunk:
+		p2ps.GetCodeChunk(context, msg)
 	case *message.NotifyNewBlock:
 		if msg.Produced {
 			p2ps.NotifyBlockProduced(*msg)
@@ -293,6 +297,12 @@ func (p2ps *P2P) InsertHandlers(peer p2pcommon.RemotePeer) {
 	peer.AddMessageHandler(subproto.GetHashByNoRequest, subproto.NewGetHashByNoReqHandler(p2ps.pm, peer, logger, p2ps))
 	peer.AddMessageHandler(subproto.GetHashByNoResponse, subproto.NewGetHashByNoRespHandler(p2ps.pm, peer, logger, p2ps))
 
+	// StateHandlers for the snapshot sync
+	peer.AddMessageHandler(subproto.GetStateRangeRequest, subproto.NewStateRangeReqHandler(p2ps.pm, peer, logger, p2ps))
+	peer.AddMessageHandler(subproto.GetStateRangeResponse, subproto.NewStateRangeRespHandler(p2ps.pm, peer, logger, p2ps))
+	peer.AddMessageHandler(subproto.GetContractCodeRequest, subproto.NewContractCodeReqHandler(p2ps.pm, peer, logger, p2ps))
+	peer.AddMessageHandler(subproto.GetContractCodeResponse, subproto.NewContractCodeRespHandler(p2ps.pm, peer, logger, p2ps))
+
 	// TxHandlers
 	peer.AddMessageHandler(subproto.GetTXsRequest, subproto.NewTxReqHandler(p2ps.pm, peer, logger, p2ps))
 	peer.AddMessageHandler(subproto.GetTXsResponse, subproto.NewTxRespHandler(p2ps.pm, peer, logger, p2ps))
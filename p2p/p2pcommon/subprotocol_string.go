@@ -16,12 +16,14 @@ const (
 	_SubProtocol_name_1 = "GetBlocksRequestGetBlocksResponseGetBlockHeadersRequestGetBlockHeadersResponseGetMissingRequestGetMissingResponseNewBlockNoticeGetAncestorRequestGetAncestorResponseGetHashesRequestGetHashesResponseGetHashByNoRequestGetHashByNoResponse"
 	_SubProtocol_name_2 = "GetTXsRequestGetTXsResponseNewTxNotice"
 	_SubProtocol_name_3 = "BlockProducedNotice"
+	_SubProtocol_name_4 = "GetStateRangeRequestGetStateRangeResponseGetContractCodeRequestGetContractCodeResponse"
 )
 
 var (
 	_SubProtocol_index_0 = [...]uint8{0, 13, 24, 36, 42, 58, 75}
 	_SubProtocol_index_1 = [...]uint8{0, 16, 33, 55, 78, 95, 113, 127, 145, 164, 180, 197, 215, 234}
 	_SubProtocol_index_2 = [...]uint8{0, 13, 27, 38}
+	_SubProtocol_index_4 = [...]uint8{0, 20, 41, 63, 86}
 )
 
 func (i SubProtocol) String() string {
@@ -37,6 +39,9 @@ func (i SubProtocol) String() string {
 		return _SubProtocol_name_2[_SubProtocol_index_2[i]:_SubProtocol_index_2[i+1]]
 	case i == 48:
 		return _SubProtocol_name_3
+	case 64 <= i && i <= 67:
+		i -= 64
+		return _SubProtocol_name_4[_SubProtocol_index_4[i]:_SubProtocol_index_4[i+1]]
 	default:
 		return "SubProtocol(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
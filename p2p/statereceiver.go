@@ -0,0 +1,118 @@
+/*
+ * @file
+ * @copyright defined in aergo/LICENSE.txt
+ */
+
+package p2p
+
+import (
+	"time"
+
+	"github.com/aergoio/aergo/message"
+	"github.com/aergoio/aergo/p2p/p2pcommon"
+	"github.com/aergoio/aergo/p2p/subproto"
+	"github.com/aergoio/aergo/types"
+)
+
+// StateRangeReceiver sends p2p GetStateRangeRequest to target peer and sends
+// GetStateChunkRsp actor message to the syncer when the response is received.
+// It does not send response if timeout expired.
+type StateRangeReceiver struct {
+	syncerSeq uint64
+	requestID p2pcommon.MsgID
+
+	peer  p2pcommon.RemotePeer
+	actor p2pcommon.ActorService
+
+	root     []byte
+	start    []byte
+	size     uint32
+	timeout  time.Time
+	finished bool
+}
+
+func NewStateRangeReceiver(actor p2pcommon.ActorService, peer p2pcommon.RemotePeer, seq uint64, root, start []byte, size uint32, ttl time.Duration) *StateRangeReceiver {
+	timeout := time.Now().Add(ttl)
+	return &StateRangeReceiver{syncerSeq: seq, actor: actor, peer: peer, root: root, start: start, size: size, timeout: timeout}
+}
+
+func (sr *StateRangeReceiver) StartGet() {
+	// create message data
+	req := &types.GetStateRangeRequest{Root: sr.root, Start: sr.start, Size: sr.size}
+	mo := sr.peer.MF().NewMsgBlockRequestOrder(sr.ReceiveResp, subproto.GetStateRangeRequest, req)
+	sr.requestID = mo.GetMsgID()
+	sr.peer.SendMessage(mo)
+}
+
+// ReceiveResp must be called just in read go routine
+func (sr *StateRangeReceiver) ReceiveResp(msg p2pcommon.Message, msgBody p2pcommon.MessageBody) (ret bool) {
+	ret = true
+	defer func() {
+		sr.finished = true
+		sr.peer.ConsumeRequest(sr.requestID)
+	}()
+	// timeout
+	if sr.finished || sr.timeout.Before(time.Now()) {
+		// silently ignore already finished job
+		return
+	}
+	// remote peer response failure
+	body := msgBody.(*types.GetStateRangeResponse)
+	if body.Status != types.ResultStatus_OK || len(body.Keys) != len(body.Values) {
+		sr.actor.TellRequest(message.SyncerSvc, &message.GetStateChunkRsp{Seq: sr.syncerSeq, ToWhom: sr.peer.ID(), Root: sr.root, Err: message.RemotePeerFailError})
+		return
+	}
+	sr.actor.TellRequest(message.SyncerSvc, &message.GetStateChunkRsp{Seq: sr.syncerSeq, ToWhom: sr.peer.ID(), Root: sr.root,
+		Keys: body.Keys, Values: body.Values, HasNext: body.HasNext})
+	return
+}
+
+// ContractCodeReceiver sends p2p GetContractCodeRequest to target peer and
+// sends GetCodeChunkRsp actor message to the syncer when the response is
+// received. It does not send response if timeout expired.
+type ContractCodeReceiver struct {
+	syncerSeq uint64
+	requestID p2pcommon.MsgID
+
+	peer  p2pcommon.RemotePeer
+	actor p2pcommon.ActorService
+
+	hashes   [][]byte
+	timeout  time.Time
+	finished bool
+}
+
+func NewContractCodeReceiver(actor p2pcommon.ActorService, peer p2pcommon.RemotePeer, seq uint64, hashes [][]byte, ttl time.Duration) *ContractCodeReceiver {
+	timeout := time.Now().Add(ttl)
+	return &ContractCodeReceiver{syncerSeq: seq, actor: actor, peer: peer, hashes: hashes, timeout: timeout}
+}
+
+func (cr *ContractCodeReceiver) StartGet() {
+	// create message data
+	req := &types.GetContractCodeRequest{Hashes: cr.hashes}
+	mo := cr.peer.MF().NewMsgBlockRequestOrder(cr.ReceiveResp, subproto.GetContractCodeRequest, req)
+	cr.requestID = mo.GetMsgID()
+	cr.peer.SendMessage(mo)
+}
+
+// ReceiveResp must be called just in read go routine
+func (cr *ContractCodeReceiver) ReceiveResp(msg p2pcommon.Message, msgBody p2pcommon.MessageBody) (ret bool) {
+	ret = true
+	defer func() {
+		cr.finished = true
+		cr.peer.ConsumeRequest(cr.requestID)
+	}()
+	// timeout
+	if cr.finished || cr.timeout.Before(time.Now()) {
+		// silently ignore already finished job
+		return
+	}
+	// remote peer response failure
+	body := msgBody.(*types.GetContractCodeResponse)
+	if body.Status != types.ResultStatus_OK {
+		cr.actor.TellRequest(message.SyncerSvc, &message.GetCodeChunkRsp{Seq: cr.syncerSeq, ToWhom: cr.peer.ID(), Err: message.RemotePeerFailError})
+		return
+	}
+	cr.actor.TellRequest(message.SyncerSvc, &message.GetCodeChunkRsp{Seq: cr.syncerSeq, ToWhom: cr.peer.ID(), Codes: body.Codes})
+	return
+}
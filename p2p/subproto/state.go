@@ -0,0 +1,192 @@
+/*
+ * @file
+ * @copyright defined in aergo/LICENSE.txt
+ */
+
+package subproto
+
+import (
+	"fmt"
+
+	"github.com/aergoio/aergo-lib/log"
+	"github.com/aergoio/aergo/message"
+	"github.com/aergoio/aergo/p2p/p2pcommon"
+	"github.com/aergoio/aergo/p2p/p2putil"
+	"github.com/aergoio/aergo/types"
+)
+
+const (
+	// MaxStateRangeSize is the maximum number of leaves in a GetStateRangeResponse
+	MaxStateRangeSize = 1000
+	// MaxContractCodeCount is the maximum number of codes in a GetContractCodeResponse
+	MaxContractCodeCount   = 100
+	EmptyStateResponseSize = 12
+)
+
+type stateRangeRequestHandler struct {
+	BaseMsgHandler
+}
+
+var _ p2pcommon.MessageHandler = (*stateRangeRequestHandler)(nil)
+
+type stateRangeResponseHandler struct {
+	BaseMsgHandler
+}
+
+var _ p2pcommon.MessageHandler = (*stateRangeResponseHandler)(nil)
+
+type contractCodeRequestHandler struct {
+	BaseMsgHandler
+}
+
+var _ p2pcommon.MessageHandler = (*contractCodeRequestHandler)(nil)
+
+type contractCodeResponseHandler struct {
+	BaseMsgHandler
+}
+
+var _ p2pcommon.MessageHandler = (*contractCodeResponseHandler)(nil)
+
+// NewStateRangeReqHandler creates handler for GetStateRangeRequest
+func NewStateRangeReqHandler(pm p2pcommon.PeerManager, peer p2pcommon.RemotePeer, logger *log.Logger, actor p2pcommon.ActorService) *stateRangeRequestHandler {
+	sh := &stateRangeRequestHandler{BaseMsgHandler: BaseMsgHandler{protocol: GetStateRangeRequest, pm: pm, peer: peer, actor: actor, logger: logger}}
+	return sh
+}
+
+func (sh *stateRangeRequestHandler) ParsePayload(rawbytes []byte) (p2pcommon.MessageBody, error) {
+	return p2putil.UnmarshalAndReturn(rawbytes, &types.GetStateRangeRequest{})
+}
+
+func (sh *stateRangeRequestHandler) Handle(msg p2pcommon.Message, msgBody p2pcommon.MessageBody) {
+	remotePeer := sh.peer
+	data := msgBody.(*types.GetStateRangeRequest)
+	p2putil.DebugLogReceiveMsg(sh.logger, sh.protocol, msg.ID().String(), remotePeer, data.Size)
+
+	if data.Size == 0 || data.Size > MaxStateRangeSize {
+		resp := &types.GetStateRangeResponse{Status: types.ResultStatus_INVALID_ARGUMENT}
+		remotePeer.SendMessage(remotePeer.MF().NewMsgResponseOrder(msg.ID(), GetStateRangeResponse, resp))
+		return
+	}
+
+	result, err := sh.actor.CallRequestDefaultTimeout(message.ChainSvc,
+		&message.GetStateRange{Root: data.Root, Start: data.Start, Size: int(data.Size)})
+	rsp, ok := result.(message.GetStateRangeRsp)
+	if err != nil || !ok || rsp.Err != nil {
+		sh.logger.Debug().Err(err).Str(p2putil.LogOrgReqID, msg.ID().String()).Msg("failed to get state range")
+		resp := &types.GetStateRangeResponse{Status: types.ResultStatus_NOT_FOUND}
+		remotePeer.SendMessage(remotePeer.MF().NewMsgResponseOrder(msg.ID(), GetStateRangeResponse, resp))
+		return
+	}
+
+	// cut the range if it exceeds the payload limit. the requester continues
+	// from the last key of the response.
+	hasNext := rsp.HasNext
+	payloadSize := EmptyStateResponseSize
+	count := 0
+	for ; count < len(rsp.Keys); count++ {
+		fieldSize := len(rsp.Keys[count]) + p2putil.CalculateFieldDescSize(len(rsp.Keys[count]))
+		fieldSize += len(rsp.Values[count]) + p2putil.CalculateFieldDescSize(len(rsp.Values[count]))
+		if count > 0 && payloadSize+fieldSize > p2pcommon.MaxPayloadLength {
+			hasNext = true
+			break
+		}
+		payloadSize += fieldSize
+	}
+
+	resp := &types.GetStateRangeResponse{
+		Status:  types.ResultStatus_OK,
+		Keys:    rsp.Keys[:count],
+		Values:  rsp.Values[:count],
+		HasNext: hasNext,
+	}
+	remotePeer.SendMessage(remotePeer.MF().NewMsgResponseOrder(msg.ID(), GetStateRangeResponse, resp))
+}
+
+// NewStateRangeRespHandler creates handler for GetStateRangeResponse
+func NewStateRangeRespHandler(pm p2pcommon.PeerManager, peer p2pcommon.RemotePeer, logger *log.Logger, actor p2pcommon.ActorService) *stateRangeResponseHandler {
+	sh := &stateRangeResponseHandler{BaseMsgHandler: BaseMsgHandler{protocol: GetStateRangeResponse, pm: pm, peer: peer, actor: actor, logger: logger}}
+	return sh
+}
+
+func (sh *stateRangeResponseHandler) ParsePayload(rawbytes []byte) (p2pcommon.MessageBody, error) {
+	return p2putil.UnmarshalAndReturn(rawbytes, &types.GetStateRangeResponse{})
+}
+
+func (sh *stateRangeResponseHandler) Handle(msg p2pcommon.Message, msgBody p2pcommon.MessageBody) {
+	data := msgBody.(*types.GetStateRangeResponse)
+	p2putil.DebugLogReceiveResponseMsg(sh.logger, sh.protocol, msg.ID().String(), msg.OriginalID().String(), sh.peer, fmt.Sprintf("count=%d,hasNext=%t", len(data.Keys), data.HasNext))
+
+	// locate request data and remove it if found
+	sh.peer.GetReceiver(msg.OriginalID())(msg, data)
+}
+
+// NewContractCodeReqHandler creates handler for GetContractCodeRequest
+func NewContractCodeReqHandler(pm p2pcommon.PeerManager, peer p2pcommon.RemotePeer, logger *log.Logger, actor p2pcommon.ActorService) *contractCodeRequestHandler {
+	ch := &contractCodeRequestHandler{BaseMsgHandler: BaseMsgHandler{protocol: GetContractCodeRequest, pm: pm, peer: peer, actor: actor, logger: logger}}
+	return ch
+}
+
+func (ch *contractCodeRequestHandler) ParsePayload(rawbytes []byte) (p2pcommon.MessageBody, error) {
+	return p2putil.UnmarshalAndReturn(rawbytes, &types.GetContractCodeRequest{})
+}
+
+func (ch *contractCodeRequestHandler) Handle(msg p2pcommon.Message, msgBody p2pcommon.MessageBody) {
+	remotePeer := ch.peer
+	data := msgBody.(*types.GetContractCodeRequest)
+	p2putil.DebugLogReceiveMsg(ch.logger, ch.protocol, msg.ID().String(), remotePeer, len(data.Hashes))
+
+	if len(data.Hashes) == 0 || len(data.Hashes) > MaxContractCodeCount {
+		resp := &types.GetContractCodeResponse{Status: types.ResultStatus_INVALID_ARGUMENT}
+		remotePeer.SendMessage(remotePeer.MF().NewMsgResponseOrder(msg.ID(), GetContractCodeResponse, resp))
+		return
+	}
+
+	result, err := ch.actor.CallRequestDefaultTimeout(message.ChainSvc, &message.GetContractCode{Hashes: data.Hashes})
+	rsp, ok := result.(message.GetContractCodeRsp)
+	if err != nil || !ok || rsp.Err != nil {
+		ch.logger.Debug().Err(err).Str(p2putil.LogOrgReqID, msg.ID().String()).Msg("failed to get contract codes")
+		resp := &types.GetContractCodeResponse{Status: types.ResultStatus_INTERNAL}
+		remotePeer.SendMessage(remotePeer.MF().NewMsgResponseOrder(msg.ID(), GetContractCodeResponse, resp))
+		return
+	}
+
+	// the codes are returned in the requested order, so the requester asks
+	// the rest again if the response is cut by the payload limit.
+	payloadSize := EmptyStateResponseSize
+	count := 0
+	for ; count < len(rsp.Codes); count++ {
+		if len(rsp.Codes[count]) == 0 {
+			break
+		}
+		fieldSize := len(rsp.Codes[count]) + p2putil.CalculateFieldDescSize(len(rsp.Codes[count]))
+		if count > 0 && payloadSize+fieldSize > p2pcommon.MaxPayloadLength {
+			break
+		}
+		payloadSize += fieldSize
+	}
+	status := types.ResultStatus_OK
+	if count == 0 {
+		status = types.ResultStatus_NOT_FOUND
+	}
+
+	resp := &types.GetContractCodeResponse{Status: status, Codes: rsp.Codes[:count]}
+	remotePeer.SendMessage(remotePeer.MF().NewMsgResponseOrder(msg.ID(), GetContractCodeResponse, resp))
+}
+
+// NewContractCodeRespHandler creates handler for GetContractCodeResponse
+func NewContractCodeRespHandler(pm p2pcommon.PeerManager, peer p2pcommon.RemotePeer, logger *log.Logger, actor p2pcommon.ActorService) *contractCodeResponseHandler {
+	ch := &contractCodeResponseHandler{BaseMsgHandler: BaseMsgHandler{protocol: GetContractCodeResponse, pm: pm, peer: peer, actor: actor, logger: logger}}
+	return ch
+}
+
+func (ch *contractCodeResponseHandler) ParsePayload(rawbytes []byte) (p2pcommon.MessageBody, error) {
+	return p2putil.UnmarshalAndReturn(rawbytes, &types.GetContractCodeResponse{})
+}
+
+func (ch *contractCodeResponseHandler) Handle(msg p2pcommon.Message, msgBody p2pcommon.MessageBody) {
+	data := msgBody.(*types.GetContractCodeResponse)
+	p2putil.DebugLogReceiveResponseMsg(ch.logger, ch.protocol, msg.ID().String(), msg.OriginalID().String(), ch.peer, fmt.Sprintf("count=%d", len(data.Codes)))
+
+	// locate request data and remove it if found
+	ch.peer.GetReceiver(msg.OriginalID())(msg, data)
+}
@@ -39,6 +39,14 @@ const (
 	BlockProducedNotice p2pcommon.SubProtocol = 0x030 + iota
 )
 
+// subprotocols for the snapshot sync
+const (
+	GetStateRangeRequest p2pcommon.SubProtocol = 0x040 + iota
+	GetStateRangeResponse
+	GetContractCodeRequest
+	GetContractCodeResponse
+)
+
 const (
 	_ p2pcommon.SubProtocol = 0x3100 + iota
 	GetClusterRequest
@@ -0,0 +1,98 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+#include <stdlib.h>
+#include <lualib.h>
+#include <lauxlib.h>
+#include "abi_module.h"
+#include "_cgo_export.h"
+
+/* returns the global name of the function or the name itself */
+static const char *abi_name(lua_State *L, int idx)
+{
+    if (lua_isfunction(L, idx)) {
+        lua_pushnil(L);
+        while (lua_next(L, LUA_GLOBALSINDEX) != 0) {    /* key value */
+            if (lua_type(L, -2) == LUA_TSTRING && lua_rawequal(L, -1, idx)) {
+                lua_pop(L, 1);                          /* key */
+                return lua_tostring(L, -1);
+            }
+            lua_pop(L, 1);
+        }
+        luaL_argerror(L, idx, "the function must be global");
+    }
+    return luaL_checkstring(L, idx);
+}
+
+/* pushes the comma separated types of the array */
+static void abi_push_types(lua_State *L, int idx)
+{
+    luaL_Buffer b;
+    int i, n;
+
+    if (lua_isnoneornil(L, idx)) {
+        lua_pushstring(L, "");
+        return;
+    }
+    luaL_checktype(L, idx, LUA_TTABLE);
+    n = lua_objlen(L, idx);
+    luaL_buffinit(L, &b);
+    for (i = 1; i <= n; i++) {
+        lua_rawgeti(L, idx, i);
+        if (lua_type(L, -1) != LUA_TSTRING) {
+            luaL_error(L, "the type must be a string: #%d of argument %d", i, idx);
+        }
+        if (i > 1) {
+            luaL_addchar(&b, ',');
+        }
+        luaL_addvalue(&b);
+    }
+    luaL_pushresult(&b);
+}
+
+static int abi_types(lua_State *L)
+{
+    const char *name = abi_name(L, 1);
+    char *errMsg;
+
+    abi_push_types(L, 2);
+    abi_push_types(L, 3);
+    errMsg = addABITypes((char *)name, (char *)lua_tostring(L, -2), (char *)lua_tostring(L, -1));
+    if (errMsg != NULL) {
+        lua_pushstring(L, errMsg);
+        free(errMsg);
+        lua_error(L);
+    }
+    return 0;
+}
+
+static int abi_event(lua_State *L)
+{
+    const char *name = luaL_checkstring(L, 1);
+    char *errMsg;
+
+    abi_push_types(L, 2);
+    errMsg = addABIEvent((char *)name, (char *)lua_tostring(L, -1));
+    if (errMsg != NULL) {
+        lua_pushstring(L, errMsg);
+        free(errMsg);
+        lua_error(L);
+    }
+    return 0;
+}
+
+int luac_open_abi(lua_State *L)
+{
+    static const luaL_Reg abi_lib[] = {
+        {"types", abi_types},
+        {"event", abi_event},
+        {NULL, NULL}
+    };
+
+    luaL_register(L, "abi", abi_lib);
+    lua_pop(L, 1);
+
+    return 1;
+}
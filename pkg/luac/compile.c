@@ -5,6 +5,7 @@
 #include <lauxlib.h>
 #include <luajit.h>
 #include "state_module.h"
+#include "abi_module.h"
 #include "_cgo_export.h"
 
 lua_State *luac_vm_newstate()
@@ -15,6 +16,7 @@ lua_State *luac_vm_newstate()
 	}
 	luaL_openlibs(L);
 	luac_open_state(L);
+	luac_open_abi(L);
 	return L;
 }
 
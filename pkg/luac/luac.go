@@ -0,0 +1,247 @@
+// Package luac compiles the lua contracts into the bytecode and the ABI. It
+// is shared by aergoluac and the node, so that a contract compiled by the
+// node is the same as the one compiled by aergoluac.
+package luac
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../../libtool/include/luajit-2.1
+#cgo LDFLAGS: ${SRCDIR}/../../libtool/lib/libluajit-5.1.a -lm
+
+#include <stdlib.h>
+#include "compile.h"
+*/
+import "C"
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aergoio/aergo/types"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+var (
+	b           bytes.Buffer
+	annotations []abiAnnotation
+	// compileLock guards the buffer while the node compiles contracts for
+	// deployments and source verifications at the same time.
+	compileLock sync.Mutex
+)
+
+// abiAnnotation is the types of a function or an event declared by abi.types
+// or abi.event, which are merged into the generated ABI.
+type abiAnnotation struct {
+	name    string
+	event   bool
+	params  []string
+	returns []string
+}
+
+func NewLState() *C.lua_State {
+	L := C.luac_vm_newstate()
+	if L == nil {
+		runtime.GC()
+		L = C.luac_vm_newstate()
+	}
+	return L
+}
+
+func CloseLState(L *C.lua_State) {
+	if L != nil {
+		C.luac_vm_close(L)
+	}
+}
+
+func Compile(L *C.lua_State, code string) ([]byte, error) {
+	compileLock.Lock()
+	defer compileLock.Unlock()
+	b.Reset()
+	annotations = nil
+	cstr := C.CString(code)
+	defer C.free(unsafe.Pointer(cstr))
+	if errMsg := C.vm_loadstring(L, cstr); errMsg != nil {
+		return nil, errors.New(C.GoString(errMsg))
+	}
+	if errMsg := C.vm_stringdump(L); errMsg != nil {
+		return nil, errors.New(C.GoString(errMsg))
+	}
+	if err := annotateDump(); err != nil {
+		return nil, err
+	}
+	return append([]byte{}, b.Bytes()...), nil
+}
+
+func CompileFromFile(srcFileName, outFileName, abiFileName string) error {
+	cSrcFileName := C.CString(srcFileName)
+	cOutFileName := C.CString(outFileName)
+	cAbiFileName := C.CString(abiFileName)
+	L := C.luac_vm_newstate()
+	defer C.free(unsafe.Pointer(cSrcFileName))
+	defer C.free(unsafe.Pointer(cOutFileName))
+	defer C.free(unsafe.Pointer(cAbiFileName))
+	defer C.luac_vm_close(L)
+
+	annotations = nil
+	if errMsg := C.vm_compile(L, cSrcFileName, cOutFileName, cAbiFileName); errMsg != nil {
+		return errors.New(C.GoString(errMsg))
+	}
+	if len(annotations) == 0 || len(abiFileName) == 0 {
+		return nil
+	}
+	abi, err := ioutil.ReadFile(abiFileName)
+	if err != nil {
+		return err
+	}
+	if abi, err = annotateABI(abi); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(abiFileName, abi, 0644)
+}
+
+// DumpFromFile returns the payload of the source file, which consists of the
+// bytecode and the ABI.
+func DumpFromFile(srcFileName string) ([]byte, error) {
+	cSrcFileName := C.CString(srcFileName)
+	L := C.luac_vm_newstate()
+	defer C.free(unsafe.Pointer(cSrcFileName))
+	defer C.luac_vm_close(L)
+
+	compileLock.Lock()
+	defer compileLock.Unlock()
+	b.Reset()
+	annotations = nil
+	if errMsg := C.vm_loadfile(L, cSrcFileName); errMsg != nil {
+		return nil, errors.New(C.GoString(errMsg))
+	}
+	if errMsg := C.vm_stringdump(L); errMsg != nil {
+		return nil, errors.New(C.GoString(errMsg))
+	}
+	if err := annotateDump(); err != nil {
+		return nil, err
+	}
+	return append([]byte{}, b.Bytes()...), nil
+}
+
+// DumpFromStdin returns the payload of the source read from the standard
+// input.
+func DumpFromStdin() ([]byte, error) {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var buf []byte
+	if (fi.Mode() & os.ModeCharDevice) == 0 {
+		buf, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var bBuf bytes.Buffer
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			bBuf.WriteString(scanner.Text() + "\n")
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, err
+		}
+		buf = bBuf.Bytes()
+	}
+	L := NewLState()
+	defer CloseLState(L)
+	return Compile(L, string(buf))
+}
+
+//export addLen
+func addLen(length C.int) {
+	var l [4]byte
+	binary.LittleEndian.PutUint32(l[:], uint32(length))
+	b.Write(l[:])
+}
+
+//export addByteN
+func addByteN(p *C.char, length C.int) {
+	s := C.GoStringN(p, length)
+	b.WriteString(s)
+}
+
+// annotateDump merges the annotations into the ABI following the bytecode
+// in the buffer.
+func annotateDump() error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	dump := b.Bytes()
+	codeLen := 4 + int(binary.LittleEndian.Uint32(dump[:4]))
+	abi, err := annotateABI(dump[codeLen:])
+	if err != nil {
+		return err
+	}
+	code := append([]byte{}, dump[:codeLen]...)
+	b.Reset()
+	b.Write(code)
+	b.Write(abi)
+	return nil
+}
+
+func annotateABI(abiJSON []byte) ([]byte, error) {
+	var abi types.ABI
+	if err := json.Unmarshal(abiJSON, &abi); err != nil {
+		return nil, err
+	}
+	for _, a := range annotations {
+		var err error
+		if a.event {
+			err = abi.AddEvent(a.name, a.params)
+		} else {
+			err = abi.SetFunctionTypes(a.name, a.params, a.returns)
+		}
+		if err != nil {
+			return nil, errors.New("abi: " + err.Error())
+		}
+	}
+	return json.Marshal(&abi)
+}
+
+func splitTypes(p *C.char) []string {
+	s := C.GoString(p)
+	if len(s) == 0 {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+//export addABITypes
+func addABITypes(name, params, returns *C.char) *C.char {
+	annotations = append(annotations, abiAnnotation{
+		name:    C.GoString(name),
+		params:  splitTypes(params),
+		returns: splitTypes(returns),
+	})
+	return checkAnnotation(annotations[len(annotations)-1])
+}
+
+//export addABIEvent
+func addABIEvent(name, args *C.char) *C.char {
+	annotations = append(annotations, abiAnnotation{
+		name:   C.GoString(name),
+		event:  true,
+		params: splitTypes(args),
+	})
+	return checkAnnotation(annotations[len(annotations)-1])
+}
+
+func checkAnnotation(a abiAnnotation) *C.char {
+	for _, t := range append(append([]string{}, a.params...), a.returns...) {
+		if !types.IsValidABIType(t) {
+			return C.CString(fmt.Sprintf("abi: invalid type of %s: %s", a.name, t))
+		}
+	}
+	return nil
+}
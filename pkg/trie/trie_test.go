@@ -342,6 +342,140 @@ func TestTrieStageUpdates(t *testing.T) {
 	os.RemoveAll(".aergo")
 }
 
+func TestTrieWalk(t *testing.T) {
+	dbPath := path.Join(".aergo", "db")
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		_ = os.MkdirAll(dbPath, 0711)
+	}
+	st := db.NewDB(db.BadgerImpl, dbPath)
+
+	smt := NewTrie(nil, common.Hasher, st)
+	keys := getFreshData(100, 32)
+	values := getFreshData(100, 32)
+	smt.Update(keys, values)
+	smt.Commit()
+	root1 := smt.Root
+
+	newValues := getFreshData(10, 32)
+	smt.Update(keys[:10], newValues)
+	smt.Commit()
+	root2 := smt.Root
+
+	visited := make(map[Hash]bool)
+	visitNode := func(node []byte) bool {
+		var h Hash
+		copy(h[:], node)
+		if visited[h] {
+			return false
+		}
+		if len(st.Get(node)) == 0 {
+			t.Fatal("visited node is not in db")
+		}
+		visited[h] = true
+		return true
+	}
+	leaves := make(map[Hash][]byte)
+	visitLeaf := func(key, value []byte) error {
+		var h Hash
+		copy(h[:], key)
+		leaves[h] = value
+		return nil
+	}
+	if err := smt.Walk(root1, visitNode, visitLeaf); err != nil {
+		t.Fatal(err)
+	}
+	if len(leaves) != len(keys) {
+		t.Fatalf("walk found %d leaves, want %d", len(leaves), len(keys))
+	}
+	for i, key := range keys {
+		var h Hash
+		copy(h[:], key)
+		if !bytes.Equal(leaves[h], values[i]) {
+			t.Fatal("walk returned a wrong value")
+		}
+	}
+	nodes1 := len(visited)
+
+	// the nodes shared with root1 are not visited again
+	leaves = make(map[Hash][]byte)
+	if err := smt.Walk(root2, visitNode, visitLeaf); err != nil {
+		t.Fatal(err)
+	}
+	if len(leaves) == 0 || len(leaves) >= len(keys) {
+		t.Fatalf("walk of root2 found %d leaves", len(leaves))
+	}
+	if len(visited) <= nodes1 {
+		t.Fatal("walk of root2 found no new node")
+	}
+	st.Close()
+	os.RemoveAll(".aergo")
+}
+
+func TestTrieRange(t *testing.T) {
+	dbPath := path.Join(".aergo", "db")
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		_ = os.MkdirAll(dbPath, 0711)
+	}
+	st := db.NewDB(db.BadgerImpl, dbPath)
+
+	smt := NewTrie(nil, common.Hasher, st)
+	keys := getFreshData(100, 32)
+	values := getFreshData(100, 32)
+	smt.Update(keys, values)
+	smt.Commit()
+
+	sorted := make([][]byte, len(keys))
+	copy(sorted, keys)
+	sort.Sort(DataArray(sorted))
+
+	// all the leaves are returned in the key order
+	var got [][]byte
+	var start []byte
+	for {
+		k, v, more, err := smt.Range(smt.Root, start, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range k {
+			value, _ := smt.Get(k[i])
+			if !bytes.Equal(value, v[i]) {
+				t.Fatal("range returned a wrong value")
+			}
+		}
+		got = append(got, k...)
+		if !more {
+			break
+		}
+		if len(k) != 30 {
+			t.Fatalf("range returned %d leaves before the last range", len(k))
+		}
+		start = k[len(k)-1]
+		start = append([]byte(nil), start...)
+		for i := len(start) - 1; i >= 0; i-- {
+			start[i]++
+			if start[i] != 0 {
+				break
+			}
+		}
+	}
+	if len(got) != len(sorted) {
+		t.Fatalf("range found %d leaves, want %d", len(got), len(sorted))
+	}
+	for i := range sorted {
+		if !bytes.Equal(got[i], sorted[i]) {
+			t.Fatal("range is not in the key order")
+		}
+	}
+
+	// a start key which is not in the trie
+	k, _, _, err := smt.Range(smt.Root, sorted[50][:31], 1)
+	if err != nil || len(k) != 1 || !bytes.Equal(k[0], sorted[50]) {
+		t.Fatal("range should start from the next key")
+	}
+	st.Close()
+	os.RemoveAll(".aergo")
+}
+
 func TestTrieRevert(t *testing.T) {
 	dbPath := path.Join(".aergo", "db")
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
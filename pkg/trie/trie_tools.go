@@ -110,6 +110,98 @@ func (s *Trie) get(root, key []byte, batch [][]byte, iBatch, height int) ([]byte
 	return s.get(lnode, key, batch, 2*iBatch+1, height-1)
 }
 
+// Walk visits the trie given a root. visitNode is called with the db key of
+// every node stored in the db and the children of the node are skipped when
+// it returns false, so that a subtree shared by several roots can be visited
+// once. visitLeaf is called with the key and the value of every leaf.
+func (s *Trie) Walk(root []byte, visitNode func(node []byte) bool, visitLeaf func(key, value []byte) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.walk(root, nil, 0, s.TrieHeight, visitNode, visitLeaf)
+}
+
+// walk visits the subtree of root
+func (s *Trie) walk(root []byte, batch [][]byte, iBatch, height int, visitNode func([]byte) bool, visitLeaf func(key, value []byte) error) error {
+	if len(root) == 0 {
+		return nil
+	}
+	if height%4 == 0 && !visitNode(root[:HashLength]) {
+		return nil
+	}
+	batch, iBatch, lnode, rnode, isShortcut, err := s.loadChildren(root, height, iBatch, batch)
+	if err != nil {
+		return err
+	}
+	if isShortcut {
+		return visitLeaf(lnode[:HashLength], rnode[:HashLength])
+	}
+	if height == 0 {
+		return nil
+	}
+	if err := s.walk(lnode, batch, 2*iBatch+1, height-1, visitNode, visitLeaf); err != nil {
+		return err
+	}
+	return s.walk(rnode, batch, 2*iBatch+2, height-1, visitNode, visitLeaf)
+}
+
+// errRangeFull stops the range walk when the limit is reached.
+var errRangeFull = fmt.Errorf("range is full")
+
+// Range returns at most limit leaves of the trie given a root in the key
+// order, starting from the first key greater than or equal to start. more is
+// true if the trie has leaves after the returned ones. A start shorter than a
+// key is padded with zeros.
+func (s *Trie) Range(root, start []byte, limit int) (keys, values [][]byte, more bool, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(start) != 0 && len(start) < HashLength {
+		start = append(start[:len(start):len(start)], make([]byte, HashLength-len(start))...)
+	}
+	visitLeaf := func(key, value []byte) error {
+		if len(keys) == limit {
+			more = true
+			return errRangeFull
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+		return nil
+	}
+	err = s.rangeWalk(root, start, nil, 0, s.TrieHeight, len(start) != 0, visitLeaf)
+	if err == errRangeFull {
+		err = nil
+	}
+	return keys, values, more, err
+}
+
+// rangeWalk visits the leaves of root in the key order. bound is true while
+// the path to root is a prefix of start, so that the subtrees whose keys are
+// less than start are skipped.
+func (s *Trie) rangeWalk(root, start []byte, batch [][]byte, iBatch, height int, bound bool, visitLeaf func(key, value []byte) error) error {
+	if len(root) == 0 {
+		return nil
+	}
+	batch, iBatch, lnode, rnode, isShortcut, err := s.loadChildren(root, height, iBatch, batch)
+	if err != nil {
+		return err
+	}
+	if isShortcut {
+		if bound && bytes.Compare(lnode[:HashLength], start) < 0 {
+			return nil
+		}
+		return visitLeaf(lnode[:HashLength], rnode[:HashLength])
+	}
+	if height == 0 {
+		return nil
+	}
+	if bound && bitIsSet(start, s.TrieHeight-height) {
+		return s.rangeWalk(rnode, start, batch, 2*iBatch+2, height-1, true, visitLeaf)
+	}
+	if err := s.rangeWalk(lnode, start, batch, 2*iBatch+1, height-1, bound, visitLeaf); err != nil {
+		return err
+	}
+	return s.rangeWalk(rnode, start, batch, 2*iBatch+2, height-1, false, visitLeaf)
+}
+
 // TrieRootExists returns true if the root exists in Database.
 func (s *Trie) TrieRootExists(root []byte) bool {
 	s.db.lock.RLock()
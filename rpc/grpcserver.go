@@ -19,6 +19,7 @@ import (
 
 	"github.com/aergoio/aergo-actor/actor"
 	"github.com/aergoio/aergo-lib/log"
+	"github.com/aergoio/aergo/account/key"
 	"github.com/aergoio/aergo/chain"
 	"github.com/aergoio/aergo/consensus"
 	"github.com/aergoio/aergo/consensus/impl/raftv2"
@@ -48,10 +49,16 @@ type EventStream struct {
 	stream types.AergoRPCService_ListEventStreamServer
 }
 
+type PendingTxStream struct {
+	filter *types.TxFilter
+	stream types.AergoRPCService_ListPendingTxStreamServer
+}
+
 // AergoRPCService implements GRPC server which is defined in rpc.proto
 type AergoRPCService struct {
 	hub               *component.ComponentHub
 	actorHelper       p2pcommon.ActorService
+	version           string
 	consensusAccessor consensus.ConsensusAccessor //TODO refactor with actorHelper
 	msgHelper         message.Helper
 
@@ -63,12 +70,18 @@ type AergoRPCService struct {
 
 	eventStreamLock sync.RWMutex
 	eventStream     map[*EventStream]*EventStream
+
+	pendingTxStreamLock sync.RWMutex
+	pendingTxStream     map[*PendingTxStream]*PendingTxStream
 }
 
 // FIXME remove redundant constants
 const halfMinute = time.Second * 30
 const defaultActorTimeout = time.Second * 3
 
+// recoverKeyTimeout is the additional timeout to store a recovered key
+const recoverKeyTimeout = time.Second * 2
+
 var _ types.AergoRPCServiceServer = (*AergoRPCService)(nil)
 
 func (rpc *AergoRPCService) SetConsensusAccessor(ca consensus.ConsensusAccessor) {
@@ -609,10 +622,24 @@ func (rpc *AergoRPCService) GetState(ctx context.Context, in *types.SingleBytes)
 	return rsp.State, rsp.Err
 }
 
+// GetStateAt handle rpc request getstate at the block given by in.Block
+func (rpc *AergoRPCService) GetStateAt(ctx context.Context, in *types.AccountAndRoot) (*types.State, error) {
+	result, err := rpc.hub.RequestFuture(message.ChainSvc,
+		&message.GetState{Account: in.Account, Block: in.Block}, defaultActorTimeout, "rpc.(*AergoRPCService).GetStateAt").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(message.GetStateRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.State, rsp.Err
+}
+
 // GetStateAndProof handle rpc request getstateproof
 func (rpc *AergoRPCService) GetStateAndProof(ctx context.Context, in *types.AccountAndRoot) (*types.AccountProof, error) {
 	result, err := rpc.hub.RequestFuture(message.ChainSvc,
-		&message.GetStateAndProof{Account: in.Account, Root: in.Root, Compressed: in.Compressed}, defaultActorTimeout, "rpc.(*AergoRPCService).GetStateAndProof").Result()
+		&message.GetStateAndProof{Account: in.Account, Root: in.Root, Compressed: in.Compressed, Block: in.Block}, defaultActorTimeout, "rpc.(*AergoRPCService).GetStateAndProof").Result()
 	if err != nil {
 		return nil, err
 	}
@@ -734,9 +761,11 @@ func (rpc *AergoRPCService) ImportAccount(ctx context.Context, in *types.ImportF
 	return rsp.Account, rsp.Err
 }
 
+// ExportAccount handle rpc request to export the account in the old format,
+// which is kept for the existing clients. ExportAccountKeystore is preferred.
 func (rpc *AergoRPCService) ExportAccount(ctx context.Context, in *types.Personal) (*types.SingleBytes, error) {
 	result, err := rpc.hub.RequestFutureResult(message.AccountsSvc,
-		&message.ExportAccount{Account: in.Account, Pass: in.Passphrase},
+		&message.ExportAccount{Account: in.Account, Pass: in.Passphrase, Legacy: true},
 		defaultActorTimeout, "rpc.(*AergoRPCService).ExportAccount")
 	if err != nil {
 		if err == component.ErrHubUnregistered {
@@ -752,6 +781,75 @@ func (rpc *AergoRPCService) ExportAccount(ctx context.Context, in *types.Persona
 	return &types.SingleBytes{Value: rsp.Wif}, rsp.Err
 }
 
+// ExportAccountKeystore handle rpc request to export the account in the keystore format
+func (rpc *AergoRPCService) ExportAccountKeystore(ctx context.Context, in *types.Personal) (*types.SingleBytes, error) {
+	result, err := rpc.hub.RequestFutureResult(message.AccountsSvc,
+		&message.ExportAccount{Account: in.Account, Pass: in.Passphrase},
+		defaultActorTimeout, "rpc.(*AergoRPCService).ExportAccountKeystore")
+	if err != nil {
+		if err == component.ErrHubUnregistered {
+			return nil, status.Errorf(codes.Unavailable, "Unavailable personal feature")
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	rsp, ok := result.(*message.ExportAccountRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return &types.SingleBytes{Value: rsp.Wif}, rsp.Err
+}
+
+// CreateAccountMnemonic handle rpc request create a HD wallet
+func (rpc *AergoRPCService) CreateAccountMnemonic(ctx context.Context, in *types.Personal) (*types.HDAccounts, error) {
+	result, err := rpc.hub.RequestFutureResult(message.AccountsSvc,
+		&message.CreateAccountMnemonic{Passphrase: in.Passphrase},
+		defaultActorTimeout, "rpc.(*AergoRPCService).CreateAccountMnemonic")
+	if err != nil {
+		if err == component.ErrHubUnregistered {
+			return nil, status.Errorf(codes.Unavailable, "Unavailable personal feature")
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	rsp, ok := result.(*message.HDAccountsRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	if rsp.Err != nil {
+		return nil, status.Errorf(codes.Internal, rsp.Err.Error())
+	}
+	return &types.HDAccounts{Mnemonic: rsp.Mnemonic, Accounts: rsp.Accounts}, nil
+}
+
+// RecoverAccounts handle rpc request recover the accounts of a HD wallet
+func (rpc *AergoRPCService) RecoverAccounts(ctx context.Context, in *types.HDAccountRequest) (*types.HDAccounts, error) {
+	if in.Count > key.MaxDeriveKeys {
+		return nil, status.Errorf(codes.InvalidArgument, key.ErrTooManyKeys.Error())
+	}
+	// each key is encrypted by scrypt, which takes a while
+	timeout := defaultActorTimeout + time.Duration(in.Count)*recoverKeyTimeout
+	result, err := rpc.hub.RequestFutureResult(message.AccountsSvc,
+		&message.RecoverAccounts{Mnemonic: in.Mnemonic, Account: in.Account, Index: in.Index, Count: in.Count,
+			Passphrase: in.Passphrase},
+		timeout, "rpc.(*AergoRPCService).RecoverAccounts")
+	if err != nil {
+		if err == component.ErrHubUnregistered {
+			return nil, status.Errorf(codes.Unavailable, "Unavailable personal feature")
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	rsp, ok := result.(*message.HDAccountsRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	if rsp.Err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, rsp.Err.Error())
+	}
+	return &types.HDAccounts{Accounts: rsp.Accounts}, nil
+}
+
 // SignTX handle rpc request signtx
 func (rpc *AergoRPCService) SignTX(ctx context.Context, in *types.Tx) (*types.Tx, error) {
 	result, err := rpc.hub.RequestFutureResult(message.AccountsSvc,
@@ -932,9 +1030,62 @@ func (rpc *AergoRPCService) GetABI(ctx context.Context, in *types.SingleBytes) (
 	return rsp.ABI, rsp.Err
 }
 
+// GetCodeVersions handle rpc request get the code versions of the contract
+func (rpc *AergoRPCService) GetCodeVersions(ctx context.Context, in *types.SingleBytes) (*types.CodeVersionList, error) {
+	result, err := rpc.hub.RequestFuture(message.ChainSvc,
+		&message.GetCodeVersions{Contract: in.Value}, defaultActorTimeout, "rpc.(*AergoRPCService).GetCodeVersions").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(message.GetCodeVersionsRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.Versions, rsp.Err
+}
+
+// VerifyContract handle rpc request verify the source of the contract
+func (rpc *AergoRPCService) VerifyContract(ctx context.Context, in *types.VerifyContractRequest) (*types.ContractSource, error) {
+	if len(in.Source) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "source is required")
+	}
+	// the source is compiled by the compiler built with the node
+	compilerVersion := in.CompilerVersion
+	if len(compilerVersion) == 0 {
+		compilerVersion = rpc.version
+	} else if compilerVersion != rpc.version {
+		return nil, status.Errorf(codes.FailedPrecondition, "compiler version %s is different from the node version %s", compilerVersion, rpc.version)
+	}
+	result, err := rpc.hub.RequestFuture(message.ChainSvc,
+		&message.VerifyContract{Contract: in.ContractAddress, Source: in.Source, CompilerVersion: compilerVersion},
+		defaultActorTimeout, "rpc.(*AergoRPCService).VerifyContract").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(message.GetContractSourceRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.Source, rsp.Err
+}
+
+// GetContractSource handle rpc request get the verified source of the contract
+func (rpc *AergoRPCService) GetContractSource(ctx context.Context, in *types.SingleBytes) (*types.ContractSource, error) {
+	result, err := rpc.hub.RequestFuture(message.ChainSvc,
+		&message.GetContractSource{Contract: in.Value}, defaultActorTimeout, "rpc.(*AergoRPCService).GetContractSource").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(message.GetContractSourceRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.Source, rsp.Err
+}
+
 func (rpc *AergoRPCService) QueryContract(ctx context.Context, in *types.Query) (*types.SingleBytes, error) {
 	result, err := rpc.hub.RequestFuture(message.ChainSvc,
-		&message.GetQuery{Contract: in.ContractAddress, Queryinfo: in.Queryinfo}, defaultActorTimeout, "rpc.(*AergoRPCService).QueryContract").Result()
+		&message.GetQuery{Contract: in.ContractAddress, Queryinfo: in.Queryinfo, Block: in.Block}, defaultActorTimeout, "rpc.(*AergoRPCService).QueryContract").Result()
 	if err != nil {
 		return nil, err
 	}
@@ -948,7 +1099,7 @@ func (rpc *AergoRPCService) QueryContract(ctx context.Context, in *types.Query)
 // QueryContractState queries the state of a contract state variable without executing a contract function.
 func (rpc *AergoRPCService) QueryContractState(ctx context.Context, in *types.StateQuery) (*types.StateQueryProof, error) {
 	result, err := rpc.hub.RequestFuture(message.ChainSvc,
-		&message.GetStateQuery{ContractAddress: in.ContractAddress, StorageKeys: in.StorageKeys, Root: in.Root, Compressed: in.Compressed}, defaultActorTimeout, "rpc.(*AergoRPCService).GetStateQuery").Result()
+		&message.GetStateQuery{ContractAddress: in.ContractAddress, StorageKeys: in.StorageKeys, Root: in.Root, Compressed: in.Compressed, Block: in.Block}, defaultActorTimeout, "rpc.(*AergoRPCService).GetStateQuery").Result()
 	if err != nil {
 		return nil, err
 	}
@@ -959,6 +1110,27 @@ func (rpc *AergoRPCService) QueryContractState(ctx context.Context, in *types.St
 	return rsp.Result, rsp.Err
 }
 
+// SimulateTX executes a transaction against the best state without committing
+// it and returns the receipt which the transaction would produce.
+func (rpc *AergoRPCService) SimulateTX(ctx context.Context, in *types.Tx) (*types.SimulateResult, error) {
+	if in.GetBody() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "tx body is empty")
+	}
+	result, err := rpc.hub.RequestFuture(message.ChainSvc,
+		&message.SimulateTx{Tx: in}, defaultActorTimeout, "rpc.(*AergoRPCService).SimulateTX").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(message.SimulateTxRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	if rsp.Err != nil {
+		return &types.SimulateResult{Error: rsp.Err.Error()}, nil
+	}
+	return &types.SimulateResult{Receipt: rsp.Receipt}, nil
+}
+
 func toTimestamp(time time.Time) *timestamp.Timestamp {
 	return &timestamp.Timestamp{
 		Seconds: time.Unix(),
@@ -1017,6 +1189,36 @@ func (rpc *AergoRPCService) BroadcastToEventStream(events []*types.Event) error
 	return nil
 }
 
+func (rpc *AergoRPCService) ListPendingTxStream(in *types.TxFilter, stream types.AergoRPCService_ListPendingTxStreamServer) error {
+	txStream := &PendingTxStream{in, stream}
+	rpc.pendingTxStreamLock.Lock()
+	rpc.pendingTxStream[txStream] = txStream
+	rpc.pendingTxStreamLock.Unlock()
+
+	<-txStream.stream.Context().Done()
+
+	rpc.pendingTxStreamLock.Lock()
+	delete(rpc.pendingTxStream, txStream)
+	rpc.pendingTxStreamLock.Unlock()
+	return nil
+}
+
+// BroadcastToPendingTxStream sends a tx accepted into the mempool to the
+// streams whose filter matches it.
+func (rpc *AergoRPCService) BroadcastToPendingTxStream(tx *types.Tx) error {
+	rpc.pendingTxStreamLock.RLock()
+	defer rpc.pendingTxStreamLock.RUnlock()
+
+	for _, ts := range rpc.pendingTxStream {
+		if ts != nil && ts.filter.Match(tx) {
+			if err := ts.stream.Send(tx); err != nil {
+				logger.Warn().Err(err).Msg("failed to broadcast pending tx stream")
+			}
+		}
+	}
+	return nil
+}
+
 func (rpc *AergoRPCService) ListEvents(ctx context.Context, in *types.FilterInfo) (*types.EventList, error) {
 	result, err := rpc.hub.RequestFuture(message.ChainSvc,
 		&message.ListEvents{Filter: in}, defaultActorTimeout, "rpc.(*AergoRPCService).ListEvents").Result()
@@ -1027,7 +1229,63 @@ func (rpc *AergoRPCService) ListEvents(ctx context.Context, in *types.FilterInfo
 	if !ok {
 		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
 	}
-	return &types.EventList{Events: rsp.Events}, rsp.Err
+	return &types.EventList{Events: rsp.Events, NextCursor: rsp.NextCursor}, rsp.Err
+}
+
+func (rpc *AergoRPCService) ListAccountTxs(ctx context.Context, in *types.AccountTxsRequest) (*types.AccountTxList, error) {
+	result, err := rpc.hub.RequestFuture(message.ChainSvc,
+		&message.ListAccountTxs{Address: in.Address, Cursor: in.Cursor, Limit: in.Limit, Desc: in.Desc},
+		defaultActorTimeout, "rpc.(*AergoRPCService).ListAccountTxs").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*message.ListAccountTxsRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return &types.AccountTxList{Txs: rsp.Txs, NextCursor: rsp.NextCursor}, rsp.Err
+}
+
+func (rpc *AergoRPCService) ListMempoolTxs(ctx context.Context, in *types.MempoolTxsRequest) (*types.MempoolTxList, error) {
+	result, err := rpc.hub.RequestFuture(message.MemPoolSvc,
+		&message.MemPoolList{Cursor: in.Cursor, Limit: in.Limit}, defaultActorTimeout, "rpc.(*AergoRPCService).ListMempoolTxs").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*message.MemPoolListRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return &types.MempoolTxList{Txs: rsp.Txs, NextCursor: rsp.NextCursor}, rsp.Err
+}
+
+func (rpc *AergoRPCService) GetMempoolAccount(ctx context.Context, in *types.SingleBytes) (*types.MempoolAccount, error) {
+	if len(in.Value) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "account is empty")
+	}
+	result, err := rpc.hub.RequestFuture(message.MemPoolSvc,
+		&message.MemPoolAccount{Account: in.Value}, defaultActorTimeout, "rpc.(*AergoRPCService).GetMempoolAccount").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*message.MemPoolAccountRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.Account, rsp.Err
+}
+
+func (rpc *AergoRPCService) GetMempoolStats(ctx context.Context, in *types.Empty) (*types.MempoolStats, error) {
+	result, err := rpc.hub.RequestFuture(message.MemPoolSvc,
+		&message.MemPoolStats{}, defaultActorTimeout, "rpc.(*AergoRPCService).GetMempoolStats").Result()
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*message.MemPoolStatsRsp)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.Stats, nil
 }
 
 func (rpc *AergoRPCService) GetServerInfo(ctx context.Context, in *types.KeyParams) (*types.ServerInfo, error) {
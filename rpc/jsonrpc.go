@@ -0,0 +1,866 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aergoio/aergo/internal/enc"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/status"
+)
+
+// JSONRPCServer is an HTTP JSON-RPC 2.0 gateway in front of AergoRPCService.
+// Every method is served by the corresponding gRPC handler, so the requests
+// go through the same actor messages as the gRPC API. Hashes and raw bytes are
+// encoded in base58 and addresses in the aergo address format.
+//
+//	aergo_blockNumber                            best block number
+//	aergo_getBlockByNumber [no, fullTx]          block by number, "earliest" or "latest"
+//	aergo_getBlockByHash [hash, fullTx]          block by hash
+//	aergo_getTransactionByHash [hash]            tx in a block or the mempool
+//	aergo_getTransactionReceipt [hash]           receipt of a tx
+//	aergo_sendRawTransaction [rawTx]             commit a signed tx encoded in protobuf
+//	aergo_simulateTransaction [rawTx]            execute a tx without committing it
+//	aergo_call [address, {Name, Args}, no]       query a contract function at the block, or the best one if no is omitted
+//	aergo_getLogs [filter]                       events matched with the filter
+//	aergo_subscribe ["newHeads"|"logs", filter]  websocket only
+//	aergo_unsubscribe [id]                       websocket only
+//
+// The methods are prefixed by aergo_ instead of eth_, since neither the
+// encodings nor the objects are compatible with the Ethereum JSON-RPC API, so
+// an Ethereum client can't be used with the gateway as it is.
+//
+// The websocket connections from the other origins are accepted only if CORS
+// is allowed. The notifications are queued for each connection, and a client
+// which doesn't read them in time is disconnected.
+type JSONRPCServer struct {
+	rpc        *AergoRPCService
+	allowCORS  bool
+	httpServer *http.Server
+	upgrader   websocket.Upgrader
+
+	subID   uint64
+	subLock sync.RWMutex
+	subs    map[string]*jsonRPCSub
+}
+
+const (
+	jsonRPCVersion = "2.0"
+
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCServerError    = -32000
+
+	jsonRPCMaxBodySize = 1024 * 1024 * 4
+
+	// jsonRPCSendQueueSize is the number of the messages queued for a
+	// websocket connection.
+	jsonRPCSendQueueSize = 256
+)
+
+type jsonRPCRequest struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCNotification struct {
+	Version string              `json:"jsonrpc"`
+	Method  string              `json:"method"`
+	Params  jsonRPCSubscription `json:"params"`
+}
+
+type jsonRPCSubscription struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *jsonRPCError) Error() string {
+	return e.Message
+}
+
+func invalidParams(format string, args ...interface{}) *jsonRPCError {
+	return &jsonRPCError{Code: jsonRPCInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+type jsonRPCMethod func(s *JSONRPCServer, ctx context.Context, params json.RawMessage) (interface{}, error)
+
+var jsonRPCMethods = map[string]jsonRPCMethod{
+	"aergo_blockNumber":           (*JSONRPCServer).blockNumber,
+	"aergo_getBlockByNumber":      (*JSONRPCServer).getBlockByNumber,
+	"aergo_getBlockByHash":        (*JSONRPCServer).getBlockByHash,
+	"aergo_getTransactionByHash":  (*JSONRPCServer).getTransactionByHash,
+	"aergo_getTransactionReceipt": (*JSONRPCServer).getTransactionReceipt,
+	"aergo_sendRawTransaction":    (*JSONRPCServer).sendRawTransaction,
+	"aergo_simulateTransaction":   (*JSONRPCServer).simulateTransaction,
+	"aergo_call":                  (*JSONRPCServer).callContract,
+	"aergo_getLogs":               (*JSONRPCServer).getLogs,
+}
+
+// NewJSONRPCServer returns a JSON-RPC gateway which serves the requests by
+// rpc.
+func NewJSONRPCServer(rpc *AergoRPCService, allowCORS bool) *JSONRPCServer {
+	s := &JSONRPCServer{
+		rpc:       rpc,
+		allowCORS: allowCORS,
+		subs:      make(map[string]*jsonRPCSub),
+	}
+	if allowCORS {
+		s.upgrader.CheckOrigin = func(r *http.Request) bool {
+			return true
+		}
+	}
+	// otherwise, the upgrader accepts the same origin only
+	s.httpServer = &http.Server{
+		Handler:        s,
+		ReadTimeout:    4 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+	return s
+}
+
+// Serve accepts the JSON-RPC requests on l until the server is closed.
+func (s *JSONRPCServer) Serve(l net.Listener) error {
+	if err := s.httpServer.Serve(l); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Close closes the listener and all the websocket connections.
+func (s *JSONRPCServer) Close() error {
+	return s.httpServer.Close()
+}
+
+func (s *JSONRPCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.allowCORS {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	}
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebsocket(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, jsonRPCMaxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	rsp := s.handleBody(r.Context(), body)
+	w.Header().Set("Content-Type", "application/json")
+	if rsp == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(rsp); err != nil {
+		logger.Warn().Err(err).Msg("failed to write json-rpc response")
+	}
+}
+
+// handleBody handles a single request or a batch of requests. It returns nil
+// when there is nothing to respond, i.e. every request is a notification.
+func (s *JSONRPCServer) handleBody(ctx context.Context, body []byte) interface{} {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return errorResponse(nil, &jsonRPCError{Code: jsonRPCParseError, Message: err.Error()})
+		}
+		if len(reqs) == 0 {
+			return errorResponse(nil, &jsonRPCError{Code: jsonRPCInvalidRequest, Message: "empty batch"})
+		}
+		rsps := make([]*jsonRPCResponse, 0, len(reqs))
+		for _, req := range reqs {
+			if rsp := s.handleRequest(ctx, req); rsp != nil {
+				rsps = append(rsps, rsp)
+			}
+		}
+		if len(rsps) == 0 {
+			return nil
+		}
+		return rsps
+	}
+	if rsp := s.handleRequest(ctx, body); rsp != nil {
+		return rsp
+	}
+	return nil
+}
+
+func (s *JSONRPCServer) handleRequest(ctx context.Context, body []byte) *jsonRPCResponse {
+	var req jsonRPCRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return errorResponse(nil, &jsonRPCError{Code: jsonRPCParseError, Message: err.Error()})
+	}
+	if req.Version != jsonRPCVersion || len(req.Method) == 0 {
+		return errorResponse(req.ID, &jsonRPCError{Code: jsonRPCInvalidRequest, Message: "invalid request"})
+	}
+	result, err := s.dispatch(ctx, req.Method, req.Params)
+	if len(req.ID) == 0 {
+		// notification
+		return nil
+	}
+	if err != nil {
+		return errorResponse(req.ID, err)
+	}
+	return &jsonRPCResponse{Version: jsonRPCVersion, ID: req.ID, Result: result}
+}
+
+func (s *JSONRPCServer) dispatch(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+	fn, exist := jsonRPCMethods[method]
+	if !exist {
+		return nil, &jsonRPCError{Code: jsonRPCMethodNotFound, Message: "method not found: " + method}
+	}
+	return fn(s, ctx, params)
+}
+
+func errorResponse(id json.RawMessage, err error) *jsonRPCResponse {
+	rpcErr, ok := err.(*jsonRPCError)
+	if !ok {
+		msg := err.Error()
+		if st, ok := status.FromError(err); ok {
+			msg = st.Message()
+		}
+		rpcErr = &jsonRPCError{Code: jsonRPCServerError, Message: msg}
+	}
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &jsonRPCResponse{Version: jsonRPCVersion, ID: id, Error: rpcErr}
+}
+
+// parseParams decodes the positional parameters into args. The first
+// required args must be given.
+func parseParams(raw json.RawMessage, required int, args ...interface{}) error {
+	var params []json.RawMessage
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return invalidParams("params must be an array")
+		}
+	}
+	if len(params) < required || len(params) > len(args) {
+		return invalidParams("expected %d to %d params, got %d", required, len(args), len(params))
+	}
+	for i, p := range params {
+		if err := json.Unmarshal(p, args[i]); err != nil {
+			return invalidParams("invalid param %d: %s", i, err.Error())
+		}
+	}
+	return nil
+}
+
+func parseHashParam(raw json.RawMessage) ([]byte, error) {
+	var encoded string
+	if err := parseParams(raw, 1, &encoded); err != nil {
+		return nil, err
+	}
+	hash, err := enc.ToBytes(encoded)
+	if err != nil || len(hash) != types.HashIDLength {
+		return nil, invalidParams("invalid hash: %s", encoded)
+	}
+	return hash, nil
+}
+
+func parseRawTxParam(raw json.RawMessage) (*types.Tx, error) {
+	var encoded string
+	if err := parseParams(raw, 1, &encoded); err != nil {
+		return nil, err
+	}
+	txBytes, err := enc.ToBytes(encoded)
+	if err != nil {
+		return nil, invalidParams("invalid raw tx: %s", err.Error())
+	}
+	tx := &types.Tx{}
+	if err := proto.Unmarshal(txBytes, tx); err != nil || tx.GetBody() == nil {
+		return nil, invalidParams("invalid raw tx")
+	}
+	return tx, nil
+}
+
+// blockNumberParam is a block number, which is given as a decimal number, a
+// hexadecimal string or "latest".
+type blockNumberParam struct {
+	latest bool
+	no     types.BlockNo
+}
+
+func (b *blockNumberParam) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return json.Unmarshal(data, &b.no)
+	}
+	switch {
+	case str == "latest":
+		b.latest = true
+	case str == "earliest":
+		b.no = 0
+	case strings.HasPrefix(str, "0x"):
+		no, err := strconv.ParseUint(str[2:], 16, 64)
+		if err != nil {
+			return err
+		}
+		b.no = no
+	default:
+		no, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return err
+		}
+		b.no = no
+	}
+	return nil
+}
+
+type jsonTx struct {
+	Hash        string `json:"hash"`
+	Nonce       uint64 `json:"nonce"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Amount      string `json:"amount"`
+	Payload     string `json:"payload"`
+	GasLimit    uint64 `json:"gasLimit"`
+	GasPrice    string `json:"gasPrice"`
+	Type        string `json:"type"`
+	ChainIdHash string `json:"chainIdHash"`
+	Sign        string `json:"sign"`
+	BlockHash   string `json:"blockHash,omitempty"`
+	TxIndex     *int32 `json:"txIndex,omitempty"`
+}
+
+type jsonBlock struct {
+	Hash             string        `json:"hash"`
+	Number           types.BlockNo `json:"number"`
+	ParentHash       string        `json:"parentHash"`
+	Timestamp        int64         `json:"timestamp"`
+	StateRoot        string        `json:"stateRoot"`
+	TxRoot           string        `json:"txRoot"`
+	ReceiptsRoot     string        `json:"receiptsRoot"`
+	Confirms         uint64        `json:"confirms"`
+	Coinbase         string        `json:"coinbase"`
+	ChainID          string        `json:"chainId"`
+	TransactionCount int           `json:"transactionCount"`
+	Transactions     interface{}   `json:"transactions"`
+}
+
+func toJSONTx(tx *types.Tx, idx *types.TxIdx) *jsonTx {
+	body := tx.GetBody()
+	jtx := &jsonTx{
+		Hash:        enc.ToString(tx.GetHash()),
+		Nonce:       body.GetNonce(),
+		From:        types.EncodeAddress(body.GetAccount()),
+		To:          types.EncodeAddress(body.GetRecipient()),
+		Amount:      new(big.Int).SetBytes(body.GetAmount()).String(),
+		Payload:     enc.ToString(body.GetPayload()),
+		GasLimit:    body.GetGasLimit(),
+		GasPrice:    new(big.Int).SetBytes(body.GetGasPrice()).String(),
+		Type:        body.GetType().String(),
+		ChainIdHash: enc.ToString(body.GetChainIdHash()),
+		Sign:        enc.ToString(body.GetSign()),
+	}
+	if idx != nil {
+		jtx.BlockHash = enc.ToString(idx.GetBlockHash())
+		txIdx := idx.GetIdx()
+		jtx.TxIndex = &txIdx
+	}
+	return jtx
+}
+
+func toJSONBlock(block *types.Block, fullTx bool) *jsonBlock {
+	header := block.GetHeader()
+	txs := block.GetBody().GetTxs()
+	jb := &jsonBlock{
+		Hash:             enc.ToString(block.GetHash()),
+		Number:           header.GetBlockNo(),
+		ParentHash:       enc.ToString(header.GetPrevBlockHash()),
+		Timestamp:        header.GetTimestamp(),
+		StateRoot:        enc.ToString(header.GetBlocksRootHash()),
+		TxRoot:           enc.ToString(header.GetTxsRootHash()),
+		ReceiptsRoot:     enc.ToString(header.GetReceiptsRootHash()),
+		Confirms:         header.GetConfirms(),
+		Coinbase:         types.EncodeAddress(header.GetCoinbaseAccount()),
+		ChainID:          enc.ToString(header.GetChainID()),
+		TransactionCount: len(txs),
+	}
+	if fullTx {
+		jtxs := make([]*jsonTx, len(txs))
+		for i, tx := range txs {
+			jtxs[i] = toJSONTx(tx, &types.TxIdx{BlockHash: block.GetHash(), Idx: int32(i)})
+		}
+		jb.Transactions = jtxs
+	} else {
+		hashes := make([]string, len(txs))
+		for i, tx := range txs {
+			hashes[i] = enc.ToString(tx.GetHash())
+		}
+		jb.Transactions = hashes
+	}
+	return jb
+}
+
+func (s *JSONRPCServer) blockNumber(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	if err := parseParams(params, 0); err != nil {
+		return nil, err
+	}
+	bestStatus, err := s.rpc.Blockchain(ctx, &types.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return bestStatus.GetBestHeight(), nil
+}
+
+func (s *JSONRPCServer) getBlockByNumber(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var number blockNumberParam
+	var fullTx bool
+	if err := parseParams(params, 1, &number, &fullTx); err != nil {
+		return nil, err
+	}
+	if number.latest {
+		bestStatus, err := s.rpc.Blockchain(ctx, &types.Empty{})
+		if err != nil {
+			return nil, err
+		}
+		number.no = bestStatus.GetBestHeight()
+	}
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, number.no)
+	block, err := s.rpc.GetBlock(ctx, &types.SingleBytes{Value: b})
+	if err != nil {
+		return nil, err
+	}
+	return toJSONBlock(block, fullTx), nil
+}
+
+func (s *JSONRPCServer) getBlockByHash(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var encoded string
+	var fullTx bool
+	if err := parseParams(params, 1, &encoded, &fullTx); err != nil {
+		return nil, err
+	}
+	hash, err := enc.ToBytes(encoded)
+	if err != nil || len(hash) != types.HashIDLength {
+		return nil, invalidParams("invalid hash: %s", encoded)
+	}
+	block, err := s.rpc.GetBlock(ctx, &types.SingleBytes{Value: hash})
+	if err != nil {
+		return nil, err
+	}
+	return toJSONBlock(block, fullTx), nil
+}
+
+func (s *JSONRPCServer) getTransactionByHash(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	hash, err := parseHashParam(params)
+	if err != nil {
+		return nil, err
+	}
+	txInBlock, err := s.rpc.GetBlockTX(ctx, &types.SingleBytes{Value: hash})
+	if err == nil && txInBlock.GetTx() != nil {
+		return toJSONTx(txInBlock.GetTx(), txInBlock.GetTxIdx()), nil
+	}
+	// not in the chain yet, try the mempool
+	tx, err := s.rpc.GetTX(ctx, &types.SingleBytes{Value: hash})
+	if err != nil {
+		return nil, err
+	}
+	return toJSONTx(tx, nil), nil
+}
+
+func (s *JSONRPCServer) getTransactionReceipt(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	hash, err := parseHashParam(params)
+	if err != nil {
+		return nil, err
+	}
+	return s.rpc.GetReceipt(ctx, &types.SingleBytes{Value: hash})
+}
+
+func (s *JSONRPCServer) sendRawTransaction(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	tx, err := parseRawTxParam(params)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.rpc.CommitTX(ctx, &types.TxList{Txs: []*types.Tx{tx}})
+	if err != nil {
+		return nil, err
+	}
+	if len(results.GetResults()) == 0 {
+		return nil, errors.New("no commit result")
+	}
+	result := results.GetResults()[0]
+	if result.GetError() != types.CommitStatus_TX_OK {
+		msg := result.GetError().String()
+		if len(result.GetDetail()) > 0 {
+			msg += ": " + result.GetDetail()
+		}
+		return nil, errors.New(msg)
+	}
+	return enc.ToString(result.GetHash()), nil
+}
+
+func (s *JSONRPCServer) simulateTransaction(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	tx, err := parseRawTxParam(params)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.rpc.SimulateTX(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.GetError()) > 0 {
+		return nil, errors.New(result.GetError())
+	}
+	return result.GetReceipt(), nil
+}
+
+type jsonCallInfo struct {
+	Name string        `json:"Name"`
+	Args []interface{} `json:"Args"`
+}
+
+func (s *JSONRPCServer) callContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var address string
+	var callInfo jsonCallInfo
+	var number *blockNumberParam
+	if err := parseParams(params, 2, &address, &callInfo, &number); err != nil {
+		return nil, err
+	}
+	contract, err := types.DecodeAddress(address)
+	if err != nil {
+		return nil, invalidParams("invalid address: %s", err.Error())
+	}
+	queryInfo, err := json.Marshal(callInfo)
+	if err != nil {
+		return nil, invalidParams("invalid call info: %s", err.Error())
+	}
+	query := &types.Query{ContractAddress: contract, Queryinfo: queryInfo}
+	if number != nil && !number.latest {
+		query.Block = make([]byte, 8)
+		binary.LittleEndian.PutUint64(query.Block, number.no)
+	}
+	ret, err := s.rpc.QueryContract(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if json.Valid(ret.GetValue()) {
+		return json.RawMessage(ret.GetValue()), nil
+	}
+	return string(ret.GetValue()), nil
+}
+
+type jsonFilter struct {
+	Address        string          `json:"address"`
+	EventName      string          `json:"eventName"`
+	Args           json.RawMessage `json:"args"`
+	BlockFrom      uint64          `json:"blockfrom"`
+	BlockTo        uint64          `json:"blockto"`
+	Desc           bool            `json:"desc"`
+	RecentBlockCnt int32           `json:"recentBlockCnt"`
+}
+
+func (f *jsonFilter) toFilterInfo() (*types.FilterInfo, error) {
+	filter := &types.FilterInfo{
+		EventName:      f.EventName,
+		Blockfrom:      f.BlockFrom,
+		Blockto:        f.BlockTo,
+		Desc:           f.Desc,
+		RecentBlockCnt: f.RecentBlockCnt,
+	}
+	if len(f.Address) > 0 {
+		address, err := types.DecodeAddress(f.Address)
+		if err != nil {
+			return nil, invalidParams("invalid address: %s", err.Error())
+		}
+		filter.ContractAddress = address
+	}
+	if len(f.Args) > 0 && string(f.Args) != "null" {
+		filter.ArgFilter = f.Args
+	}
+	return filter, nil
+}
+
+func (s *JSONRPCServer) getLogs(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var jf jsonFilter
+	if err := parseParams(params, 1, &jf); err != nil {
+		return nil, err
+	}
+	filter, err := jf.toFilterInfo()
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.rpc.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if events.GetEvents() == nil {
+		return []*types.Event{}, nil
+	}
+	return events.GetEvents(), nil
+}
+
+const (
+	subNewHeads = "newHeads"
+	subLogs     = "logs"
+)
+
+// jsonRPCConn is a websocket connection. The messages are written by the
+// writer goroutine of the connection, so that the rpc actor sending the
+// notifications is never blocked by a slow client.
+type jsonRPCConn struct {
+	conn      *websocket.Conn
+	sendCh    chan interface{}
+	quitCh    chan struct{}
+	closeOnce sync.Once
+}
+
+func newJSONRPCConn(conn *websocket.Conn) *jsonRPCConn {
+	c := &jsonRPCConn{
+		conn:   conn,
+		sendCh: make(chan interface{}, jsonRPCSendQueueSize),
+		quitCh: make(chan struct{}),
+	}
+	go c.writeLoop()
+	return c
+}
+
+// send queues v to be written. The connection is closed if the queue is full.
+// It returns false if v is dropped.
+func (c *jsonRPCConn) send(v interface{}) bool {
+	select {
+	case <-c.quitCh:
+		return false
+	default:
+	}
+	select {
+	case c.sendCh <- v:
+		return true
+	default:
+		logger.Warn().Str("remote", c.conn.RemoteAddr().String()).Msg("json-rpc websocket client is too slow, disconnecting")
+		c.close()
+		return false
+	}
+}
+
+func (c *jsonRPCConn) writeLoop() {
+	for {
+		select {
+		case v := <-c.sendCh:
+			c.conn.SetWriteDeadline(time.Now().Add(defaultActorTimeout))
+			if err := c.conn.WriteJSON(v); err != nil {
+				logger.Debug().Err(err).Msg("failed to write json-rpc websocket")
+				c.close()
+				return
+			}
+		case <-c.quitCh:
+			return
+		}
+	}
+}
+
+// close stops the writer and closes the connection, which also makes the
+// reader fail and remove the subscriptions.
+func (c *jsonRPCConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.quitCh)
+		c.conn.Close()
+	})
+}
+
+type jsonRPCSub struct {
+	id        string
+	kind      string
+	filter    *types.FilterInfo
+	argFilter []types.ArgFilter
+	conn      *jsonRPCConn
+}
+
+func (s *JSONRPCServer) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	wsConn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Debug().Err(err).Msg("failed to upgrade json-rpc websocket")
+		return
+	}
+	conn := newJSONRPCConn(wsConn)
+	defer func() {
+		s.removeSubs(conn)
+		conn.close()
+	}()
+	wsConn.SetReadLimit(jsonRPCMaxBodySize)
+
+	for {
+		_, body, err := wsConn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var rsp interface{}
+		var req jsonRPCRequest
+		if json.Unmarshal(body, &req) == nil && (req.Method == "aergo_subscribe" || req.Method == "aergo_unsubscribe") {
+			rsp = s.handleSubscription(conn, &req)
+		} else {
+			rsp = s.handleBody(r.Context(), body)
+		}
+		if rsp == nil {
+			continue
+		}
+		if !conn.send(rsp) {
+			return
+		}
+	}
+}
+
+func (s *JSONRPCServer) handleSubscription(conn *jsonRPCConn, req *jsonRPCRequest) *jsonRPCResponse {
+	var result interface{}
+	var err error
+	if req.Method == "aergo_subscribe" {
+		result, err = s.subscribe(conn, req.Params)
+	} else {
+		result, err = s.unsubscribe(conn, req.Params)
+	}
+	if err != nil {
+		return errorResponse(req.ID, err)
+	}
+	return &jsonRPCResponse{Version: jsonRPCVersion, ID: req.ID, Result: result}
+}
+
+func (s *JSONRPCServer) subscribe(conn *jsonRPCConn, params json.RawMessage) (interface{}, error) {
+	var kind string
+	var jf jsonFilter
+	if err := parseParams(params, 1, &kind, &jf); err != nil {
+		return nil, err
+	}
+	sub := &jsonRPCSub{kind: kind, conn: conn}
+	switch kind {
+	case subNewHeads:
+	case subLogs:
+		filter, err := jf.toFilterInfo()
+		if err != nil {
+			return nil, err
+		}
+		if err := filter.ValidateCheck(0); err != nil {
+			return nil, invalidParams("%s", err.Error())
+		}
+		argFilter, err := filter.GetExArgFilter()
+		if err != nil {
+			return nil, invalidParams("%s", err.Error())
+		}
+		sub.filter = filter
+		sub.argFilter = argFilter
+	default:
+		return nil, invalidParams("unknown subscription: %s", kind)
+	}
+	sub.id = "0x" + strconv.FormatUint(atomic.AddUint64(&s.subID, 1), 16)
+
+	s.subLock.Lock()
+	s.subs[sub.id] = sub
+	s.subLock.Unlock()
+	return sub.id, nil
+}
+
+func (s *JSONRPCServer) unsubscribe(conn *jsonRPCConn, params json.RawMessage) (interface{}, error) {
+	var id string
+	if err := parseParams(params, 1, &id); err != nil {
+		return nil, err
+	}
+	s.subLock.Lock()
+	defer s.subLock.Unlock()
+	sub, exist := s.subs[id]
+	if !exist || sub.conn != conn {
+		return false, nil
+	}
+	delete(s.subs, id)
+	return true, nil
+}
+
+func (s *JSONRPCServer) removeSubs(conn *jsonRPCConn) {
+	s.subLock.Lock()
+	defer s.subLock.Unlock()
+	for id, sub := range s.subs {
+		if sub.conn == conn {
+			delete(s.subs, id)
+		}
+	}
+}
+
+// notify queues the notification without blocking, so it can be called under
+// subLock.
+func (s *JSONRPCServer) notify(sub *jsonRPCSub, result interface{}) {
+	sent := sub.conn.send(&jsonRPCNotification{
+		Version: jsonRPCVersion,
+		Method:  "aergo_subscription",
+		Params:  jsonRPCSubscription{Subscription: sub.id, Result: result},
+	})
+	if !sent {
+		logger.Debug().Str("id", sub.id).Msg("dropped json-rpc notification of a closed connection")
+	}
+}
+
+// BroadcastNewHead notifies block to the newHeads subscriptions.
+func (s *JSONRPCServer) BroadcastNewHead(block *types.Block) {
+	s.subLock.RLock()
+	defer s.subLock.RUnlock()
+	var head *jsonBlock
+	for _, sub := range s.subs {
+		if sub.kind != subNewHeads {
+			continue
+		}
+		if head == nil {
+			head = toJSONBlock(block, false)
+		}
+		s.notify(sub, head)
+	}
+}
+
+// BroadcastLogs notifies the events matched with the filter of each logs
+// subscription.
+func (s *JSONRPCServer) BroadcastLogs(events []*types.Event) {
+	s.subLock.RLock()
+	defer s.subLock.RUnlock()
+	for _, sub := range s.subs {
+		if sub.kind != subLogs {
+			continue
+		}
+		for _, event := range events {
+			if event.Filter(sub.filter, sub.argFilter) {
+				s.notify(sub, event)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRPCServer_handleBody(t *testing.T) {
+	s := NewJSONRPCServer(&AergoRPCService{}, false)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"parseError", `{"jsonrpc":"2.0",`, jsonRPCParseError},
+		{"noVersion", `{"id":1,"method":"aergo_blockNumber"}`, jsonRPCInvalidRequest},
+		{"noMethod", `{"jsonrpc":"2.0","id":1,"method":"eth_mining"}`, jsonRPCMethodNotFound},
+		{"badParams", `{"jsonrpc":"2.0","id":1,"method":"aergo_getTransactionByHash","params":[]}`, jsonRPCInvalidParams},
+		{"badHash", `{"jsonrpc":"2.0","id":1,"method":"aergo_getTransactionReceipt","params":["abc"]}`, jsonRPCInvalidParams},
+		{"emptyBatch", `[]`, jsonRPCInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, ok := s.handleBody(ctx, []byte(tt.body)).(*jsonRPCResponse)
+			assert.True(t, ok)
+			assert.NotNil(t, rsp.Error)
+			assert.Equal(t, tt.wantCode, rsp.Error.Code)
+		})
+	}
+
+	// notifications are not answered even if they fail
+	assert.Nil(t, s.handleBody(ctx, []byte(`{"jsonrpc":"2.0","method":"eth_mining"}`)))
+
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"eth_mining"},{"jsonrpc":"2.0","method":"eth_mining"},{"jsonrpc":"2.0","id":"a","method":"eth_mining"}]`
+	rsps, ok := s.handleBody(ctx, []byte(batch)).([]*jsonRPCResponse)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(rsps))
+	assert.Equal(t, json.RawMessage(`1`), rsps[0].ID)
+	assert.Equal(t, json.RawMessage(`"a"`), rsps[1].ID)
+}
+
+func TestBlockNumberParam(t *testing.T) {
+	tests := []struct {
+		in      string
+		latest  bool
+		no      uint64
+		wantErr bool
+	}{
+		{`10`, false, 10, false},
+		{`"10"`, false, 10, false},
+		{`"0x1f"`, false, 31, false},
+		{`"latest"`, true, 0, false},
+		{`"earliest"`, false, 0, false},
+		{`"pending"`, false, 0, true},
+		{`-1`, false, 0, true},
+	}
+	for _, tt := range tests {
+		var b blockNumberParam
+		err := json.Unmarshal([]byte(tt.in), &b)
+		if tt.wantErr {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.latest, b.latest, tt.in)
+		assert.Equal(t, tt.no, b.no, tt.in)
+	}
+
+	// an omitted block number is the best block, and "earliest" is the genesis
+	var number *blockNumberParam
+	assert.NoError(t, parseParams(json.RawMessage(`["x"]`), 1, new(string), &number))
+	assert.Nil(t, number)
+	assert.NoError(t, parseParams(json.RawMessage(`["x", "earliest"]`), 1, new(string), &number))
+	if assert.NotNil(t, number) {
+		assert.False(t, number.latest)
+		assert.Equal(t, uint64(0), number.no)
+	}
+}
+
+func TestJSONRPCServer_checkOrigin(t *testing.T) {
+	// the upgrader checks the same origin by default
+	assert.Nil(t, NewJSONRPCServer(&AergoRPCService{}, false).upgrader.CheckOrigin)
+	assert.NotNil(t, NewJSONRPCServer(&AergoRPCService{}, true).upgrader.CheckOrigin)
+}
+
+func TestParseParams(t *testing.T) {
+	var a string
+	var b bool
+	assert.NoError(t, parseParams(json.RawMessage(`["x"]`), 1, &a, &b))
+	assert.Equal(t, "x", a)
+	assert.NoError(t, parseParams(json.RawMessage(`["y", true]`), 1, &a, &b))
+	assert.Equal(t, "y", a)
+	assert.True(t, b)
+	assert.Error(t, parseParams(nil, 1, &a, &b))
+	assert.Error(t, parseParams(json.RawMessage(`["x", true, 1]`), 1, &a, &b))
+	assert.Error(t, parseParams(json.RawMessage(`{"a":1}`), 0, &a))
+	assert.Error(t, parseParams(json.RawMessage(`[1]`), 1, &a))
+}
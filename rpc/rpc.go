@@ -40,6 +40,7 @@ type RPC struct {
 	grpcWebServer *grpcweb.WrappedGrpcServer
 	actualServer  *AergoRPCService
 	httpServer    *http.Server
+	jsonRPCServer *JSONRPCServer
 
 	ca      types.ChainAccessor
 	version string
@@ -51,9 +52,11 @@ type RPC struct {
 func NewRPC(cfg *config.Config, chainAccessor types.ChainAccessor, version string) *RPC {
 	actualServer := &AergoRPCService{
 		msgHelper:           message.GetHelper(),
+		version:             version,
 		blockStream:         map[uint32]types.AergoRPCService_ListBlockStreamServer{},
 		blockMetadataStream: map[uint32]types.AergoRPCService_ListBlockMetadataStreamServer{},
 		eventStream:         make(map[*EventStream]*EventStream),
+		pendingTxStream:     make(map[*PendingTxStream]*PendingTxStream),
 	}
 
 	tracer := opentracing.GlobalTracer()
@@ -93,6 +96,9 @@ func NewRPC(cfg *config.Config, chainAccessor types.ChainAccessor, version strin
 		WriteTimeout:   4 * time.Second,
 		MaxHeaderBytes: 1 << 20,
 	}
+	if cfg.RPC.JSONRPCEnable {
+		rpcsvc.jsonRPCServer = NewJSONRPCServer(actualServer, cfg.RPC.NSAllowCORS)
+	}
 
 	return rpcsvc
 }
@@ -119,6 +125,9 @@ func (ns *RPC) AfterStart() {
 func (ns *RPC) BeforeStop() {
 	ns.httpServer.Close()
 	ns.grpcServer.Stop()
+	if ns.jsonRPCServer != nil {
+		ns.jsonRPCServer.Close()
+	}
 }
 
 func (ns *RPC) Statistics() *map[string]interface{} {
@@ -138,9 +147,17 @@ func (ns *RPC) Receive(context actor.Context) {
 			Txcount: int32(len(msg.GetBody().GetTxs())),
 		}
 		server.BroadcastToListBlockMetadataStream(meta)
+		if ns.jsonRPCServer != nil {
+			ns.jsonRPCServer.BroadcastNewHead(msg)
+		}
 	case []*types.Event:
 		server := ns.actualServer
 		server.BroadcastToEventStream(msg)
+		if ns.jsonRPCServer != nil {
+			ns.jsonRPCServer.BroadcastLogs(msg)
+		}
+	case *types.Tx:
+		ns.actualServer.BroadcastToPendingTxStream(msg)
 	case *message.GetServerInfo:
 		context.Respond(ns.CollectServerInfo(msg.Categories))
 	case *actor.Started, *actor.Stopping, *actor.Stopped, *component.CompStatReq: // donothing
@@ -181,6 +198,19 @@ func (ns *RPC) serveHTTP(l net.Listener, server *http.Server) {
 	}
 }
 
+// Serve JSON-RPC gateway over its own port
+func (ns *RPC) serveJSONRPC(ipAddr net.IP) {
+	addr := fmt.Sprintf("%s:%d", ipAddr, ns.conf.RPC.JSONRPCPort)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		panic(err)
+	}
+	ns.Info().Msg(fmt.Sprintf("Starting JSON-RPC gateway listening on %s", addr))
+	if err := ns.jsonRPCServer.Serve(l); err != nil {
+		panic(err)
+	}
+}
+
 // Serve TCP multiplexer
 func (ns *RPC) serve() {
 	ipAddr := net.ParseIP(ns.conf.RPC.NetServiceAddr)
@@ -209,6 +239,9 @@ func (ns *RPC) serve() {
 	// Server both servers
 	go ns.serveGRPC(grpcL, ns.grpcServer)
 	go ns.serveHTTP(httpL, ns.httpServer)
+	if ns.jsonRPCServer != nil {
+		go ns.serveJSONRPC(ipAddr)
+	}
 
 	// Serve TCP multiplexer
 	if err := tcpm.Serve(); !strings.Contains(err.Error(), "use of closed network connection") {
@@ -289,9 +322,9 @@ func convertError(err error) types.CommitStatus {
 		return types.CommitStatus_TX_INVALID_HASH
 	case types.ErrTxFormatInvalid:
 		return types.CommitStatus_TX_INVALID_FORMAT
-	case types.ErrInsufficientBalance:
+	case types.ErrInsufficientBalance, types.ErrInsufficientFeePayerBalance:
 		return types.CommitStatus_TX_INSUFFICIENT_BALANCE
-	case types.ErrSameNonceAlreadyInMempool:
+	case types.ErrSameNonceAlreadyInMempool, types.ErrTxPriceTooLowToReplace:
 		return types.CommitStatus_TX_HAS_SAME_NONCE
 	default:
 		//logger.Info().Str("hash", err.Error()).Msg("RPC encountered unconvertable error")
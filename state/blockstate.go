@@ -16,7 +16,7 @@ type BlockState struct {
 	StateDB
 	BpReward []byte //final bp reward, increment when tx executes
 	receipts types.Receipts
-	CodeMap  map[types.AccountID][]byte
+	CodeMap  map[types.HashID][]byte // contract codes by the code hash
 }
 
 // NewBlockInfo create new blockInfo contains blockNo, blockHash and blockHash of previous block
@@ -39,7 +39,7 @@ func (bi *BlockInfo) GetStateRoot() []byte {
 func NewBlockState(states *StateDB) *BlockState {
 	return &BlockState{
 		StateDB: *states,
-		CodeMap: make(map[types.AccountID][]byte),
+		CodeMap: make(map[types.HashID][]byte),
 	}
 }
 
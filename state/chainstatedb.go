@@ -17,6 +17,7 @@ type ChainStateDB struct {
 	states   *StateDB
 	store    db.DB
 	testmode bool
+	journal  *pruneJournal
 }
 
 // NewChainStateDB creates instance of ChainStateDB
@@ -30,8 +31,9 @@ func (sdb *ChainStateDB) Clone() *ChainStateDB {
 	defer sdb.Unlock()
 
 	newSdb := &ChainStateDB{
-		store:  sdb.store,
-		states: sdb.GetStateDB().Clone(),
+		store:   sdb.store,
+		states:  sdb.GetStateDB().Clone(),
+		journal: sdb.journal,
 	}
 	return newSdb
 }
@@ -84,7 +86,9 @@ func (sdb *ChainStateDB) GetSystemAccountState() (*ContractState, error) {
 
 // OpenNewStateDB returns new instance of statedb given state root hash
 func (sdb *ChainStateDB) OpenNewStateDB(root []byte) *StateDB {
-	return NewStateDB(&sdb.store, root, sdb.testmode)
+	states := NewStateDB(&sdb.store, root, sdb.testmode)
+	states.journal = sdb.journal
+	return states
 }
 
 func (sdb *ChainStateDB) SetGenesis(genesis *types.Genesis, bpInit func(*StateDB, *types.Genesis) error) error {
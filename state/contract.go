@@ -37,6 +37,39 @@ func (states *StateDB) StageContractState(st *ContractState) error {
 	return nil
 }
 
+// StorageSnapshot represents revision numbers of the staged contract storages
+type StorageSnapshot map[types.AccountID]int
+
+// SnapshotStorages returns revision numbers of the staged contract storages.
+// The state buffer is not included, which is snapshotted by Snapshot.
+func (states *StateDB) SnapshotStorages() StorageSnapshot {
+	states.cache.lock.RLock()
+	defer states.cache.lock.RUnlock()
+	snapshot := make(StorageSnapshot, len(states.cache.storages))
+	for id, storage := range states.cache.storages {
+		snapshot[id] = storage.buffer.snapshot()
+	}
+	return snapshot
+}
+
+// RollbackStorages discards changes of the contract storages to the revision
+// numbers. The storages staged after the snapshot are dropped.
+func (states *StateDB) RollbackStorages(snapshot StorageSnapshot) error {
+	states.cache.lock.Lock()
+	defer states.cache.lock.Unlock()
+	for id, storage := range states.cache.storages {
+		revision, ok := snapshot[id]
+		if !ok {
+			delete(states.cache.storages, id)
+			continue
+		}
+		if err := storage.buffer.rollback(revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetSystemAccountState returns the ContractState of the AERGO system account.
 func (states *StateDB) GetSystemAccountState() (*ContractState, error) {
 	return states.OpenContractStateAccount(types.ToAccountID([]byte(types.AergoSystem)))
@@ -76,6 +109,7 @@ func (st *ContractState) SetCode(code []byte) error {
 		return err
 	}
 	st.State.CodeHash = codeHash[:]
+	st.code = code
 	return nil
 }
 func (st *ContractState) GetCode() ([]byte, error) {
@@ -294,3 +294,50 @@ func TestContractStateRollback(t *testing.T) {
 	res, _ = contractState.GetData(testKey)
 	assert.Nil(t, res)
 }
+
+func TestContractStateRollbackStorages(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	testAddress := []byte("test_address")
+	otherAddress := []byte("other_address")
+	testKey := []byte("test_key")
+
+	// stage test data before the snapshot
+	contractState, err := stateDB.OpenContractStateAccount(types.ToAccountID(testAddress))
+	assert.NoError(t, err, "could not open contract state")
+	err = contractState.SetData(testKey, []byte("before"))
+	assert.NoError(t, err, "set data to contract state")
+	err = stateDB.StageContractState(contractState)
+	assert.NoError(t, err, "stage contract state")
+
+	snapshot := stateDB.SnapshotStorages()
+
+	// change the staged storage and stage a new one
+	contractState, err = stateDB.OpenContractState(types.ToAccountID(testAddress), contractState.State)
+	assert.NoError(t, err, "could not open contract state")
+	err = contractState.SetData(testKey, []byte("after"))
+	assert.NoError(t, err, "set data to contract state")
+	err = stateDB.StageContractState(contractState)
+	assert.NoError(t, err, "stage contract state")
+	otherState, err := stateDB.OpenContractStateAccount(types.ToAccountID(otherAddress))
+	assert.NoError(t, err, "could not open contract state")
+	err = otherState.SetData(testKey, []byte("after"))
+	assert.NoError(t, err, "set data to contract state")
+	err = stateDB.StageContractState(otherState)
+	assert.NoError(t, err, "stage contract state")
+
+	err = stateDB.RollbackStorages(snapshot)
+	assert.NoError(t, err, "rollback storages")
+
+	contractState, err = stateDB.OpenContractState(types.ToAccountID(testAddress), contractState.State)
+	assert.NoError(t, err, "could not open contract state")
+	res, err := contractState.GetData(testKey)
+	assert.NoError(t, err, "get data from contract state")
+	assert.Equal(t, []byte("before"), res, "storage is not rolled back")
+
+	otherState, err = stateDB.OpenContractStateAccount(types.ToAccountID(otherAddress))
+	assert.NoError(t, err, "could not open contract state")
+	res, err = otherState.GetData(testKey)
+	assert.NoError(t, err, "get data from contract state")
+	assert.Nil(t, res, "staged storage is not dropped")
+}
@@ -0,0 +1,259 @@
+package state
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/aergoio/aergo/types"
+)
+
+// A key index keeps the keys of the contract storage in the order of the
+// insertion, which the storage trie can't enumerate. It is the doubly linked
+// list of the keys stored in the contract storage itself, so that it is
+// updated and rolled back together with the data. The meta entry of an index
+// has the number of the keys, the first and the last key, and the node entry
+// of a key has the previous and the next key.
+const keyIndexPrefix = "KeyIndex_"
+
+var (
+	ErrInvalidKeyCursor = errors.New("key index: the key of the cursor is not found")
+
+	errKeyIndexCorrupted = errors.New("key index: corrupted")
+)
+
+type keyIndexMeta struct {
+	count      uint64
+	head, tail []byte
+}
+
+type keyIndexNode struct {
+	prev, next []byte
+}
+
+// AddIndexedKey appends the key to the index, unless the index already has
+// the key. It returns the size of the updated data.
+func (st *ContractState) AddIndexedKey(index, key []byte) (int64, error) {
+	node, err := st.getKeyIndexNode(index, key)
+	if err != nil || node != nil {
+		return 0, err
+	}
+	meta, err := st.getKeyIndexMeta(index)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	if meta.tail != nil {
+		tail, err := st.getKeyIndexNode(index, meta.tail)
+		if err != nil {
+			return 0, err
+		}
+		if tail == nil {
+			return 0, errKeyIndexCorrupted
+		}
+		tail.next = key
+		size += st.putKeyIndexNode(index, meta.tail, tail)
+	} else {
+		meta.head = key
+	}
+	size += st.putKeyIndexNode(index, key, &keyIndexNode{prev: meta.tail})
+	meta.tail = key
+	meta.count++
+	size += st.putKeyIndexMeta(index, meta)
+	return size, nil
+}
+
+// RemoveIndexedKey removes the key from the index. It returns the size of the
+// updated data, which is zero if the index doesn't have the key.
+func (st *ContractState) RemoveIndexedKey(index, key []byte) (int64, error) {
+	node, err := st.getKeyIndexNode(index, key)
+	if err != nil || node == nil {
+		return 0, err
+	}
+	meta, err := st.getKeyIndexMeta(index)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	if node.prev != nil {
+		prev, err := st.getKeyIndexNode(index, node.prev)
+		if err != nil {
+			return 0, err
+		}
+		if prev == nil {
+			return 0, errKeyIndexCorrupted
+		}
+		prev.next = node.next
+		size += st.putKeyIndexNode(index, node.prev, prev)
+	} else {
+		meta.head = node.next
+	}
+	if node.next != nil {
+		next, err := st.getKeyIndexNode(index, node.next)
+		if err != nil {
+			return 0, err
+		}
+		if next == nil {
+			return 0, errKeyIndexCorrupted
+		}
+		next.prev = node.prev
+		size += st.putKeyIndexNode(index, node.next, next)
+	} else {
+		meta.tail = node.prev
+	}
+	if err := st.DeleteData(keyIndexNodeKey(index, key)); err != nil {
+		return 0, err
+	}
+	size += types.HashIDLength
+	meta.count--
+	size += st.putKeyIndexMeta(index, meta)
+	return size, nil
+}
+
+// GetIndexedKeys returns at most limit keys of the index following the key
+// of the cursor, or from the first key if the cursor is nil. All the keys are
+// returned if the limit isn't positive.
+func (st *ContractState) GetIndexedKeys(index, cursor []byte, limit int) ([][]byte, error) {
+	var key []byte
+	if cursor == nil {
+		meta, err := st.getKeyIndexMeta(index)
+		if err != nil {
+			return nil, err
+		}
+		key = meta.head
+	} else {
+		node, err := st.getKeyIndexNode(index, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			return nil, ErrInvalidKeyCursor
+		}
+		key = node.next
+	}
+	var keys [][]byte
+	for key != nil && (limit <= 0 || len(keys) < limit) {
+		node, err := st.getKeyIndexNode(index, key)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			return nil, errKeyIndexCorrupted
+		}
+		keys = append(keys, key)
+		key = node.next
+	}
+	return keys, nil
+}
+
+// GetIndexedKeyCount returns the number of the keys of the index.
+func (st *ContractState) GetIndexedKeyCount(index []byte) (uint64, error) {
+	meta, err := st.getKeyIndexMeta(index)
+	if err != nil {
+		return 0, err
+	}
+	return meta.count, nil
+}
+
+func (st *ContractState) getKeyIndexMeta(index []byte) (*keyIndexMeta, error) {
+	data, err := st.GetData(keyIndexMetaKey(index))
+	if err != nil {
+		return nil, err
+	}
+	meta := &keyIndexMeta{}
+	if len(data) == 0 {
+		return meta, nil
+	}
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errKeyIndexCorrupted
+	}
+	keys, err := decodeIndexedKeys(data[n:])
+	if err != nil {
+		return nil, err
+	}
+	meta.count, meta.head, meta.tail = count, keys[0], keys[1]
+	return meta, nil
+}
+
+func (st *ContractState) putKeyIndexMeta(index []byte, meta *keyIndexMeta) int64 {
+	buf := make([]byte, binary.MaxVarintLen64)
+	data := append(buf[:binary.PutUvarint(buf, meta.count)], encodeIndexedKeys(meta.head, meta.tail)...)
+	_ = st.SetData(keyIndexMetaKey(index), data)
+	return int64(types.HashIDLength + len(data))
+}
+
+func (st *ContractState) getKeyIndexNode(index, key []byte) (*keyIndexNode, error) {
+	data, err := st.GetData(keyIndexNodeKey(index, key))
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	keys, err := decodeIndexedKeys(data)
+	if err != nil {
+		return nil, err
+	}
+	return &keyIndexNode{prev: keys[0], next: keys[1]}, nil
+}
+
+func (st *ContractState) putKeyIndexNode(index, key []byte, node *keyIndexNode) int64 {
+	data := encodeIndexedKeys(node.prev, node.next)
+	_ = st.SetData(keyIndexNodeKey(index, key), data)
+	return int64(types.HashIDLength + len(data))
+}
+
+func keyIndexMetaKey(index []byte) []byte {
+	return keyIndexNodeKey(index, nil)
+}
+
+// keyIndexNodeKey returns the storage key of the node, which is prefixed by
+// the length of the index name to separate the names and the keys.
+func keyIndexNodeKey(index, key []byte) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64(len(index)))
+	dataKey := append([]byte(keyIndexPrefix), buf[:n]...)
+	dataKey = append(dataKey, index...)
+	if key != nil {
+		dataKey = append(dataKey, '-')
+		dataKey = append(dataKey, key...)
+	}
+	return dataKey
+}
+
+// encodeIndexedKeys encodes the pair of the keys, each of which is prefixed
+// by its length plus one, or zero if the key is nil.
+func encodeIndexedKeys(first, second []byte) []byte {
+	var data []byte
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, key := range [][]byte{first, second} {
+		if key == nil {
+			data = append(data, 0)
+			continue
+		}
+		n := binary.PutUvarint(buf, uint64(len(key))+1)
+		data = append(data, buf[:n]...)
+		data = append(data, key...)
+	}
+	return data
+}
+
+func decodeIndexedKeys(data []byte) ([2][]byte, error) {
+	var keys [2][]byte
+	for i := range keys {
+		l, n := binary.Uvarint(data)
+		if n <= 0 {
+			return keys, errKeyIndexCorrupted
+		}
+		data = data[n:]
+		if l == 0 {
+			continue
+		}
+		if uint64(len(data)) < l-1 {
+			return keys, errKeyIndexCorrupted
+		}
+		keys[i] = append([]byte{}, data[:l-1]...)
+		data = data[l-1:]
+	}
+	if len(data) != 0 {
+		return keys, errKeyIndexCorrupted
+	}
+	return keys, nil
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testIndexedKeys(t *testing.T, contractState *ContractState, index []byte, expected ...string) {
+	keys, err := contractState.GetIndexedKeys(index, nil, 0)
+	assert.NoError(t, err, "get indexed keys")
+	var res []string
+	for _, key := range keys {
+		res = append(res, string(key))
+	}
+	assert.Equal(t, expected, res, "different indexed keys")
+	count, err := contractState.GetIndexedKeyCount(index)
+	assert.NoError(t, err, "get indexed key count")
+	assert.Equal(t, uint64(len(expected)), count, "different indexed key count")
+}
+
+func TestContractStateKeyIndex(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	testAddress := []byte("test_address")
+	index := []byte("test_index")
+
+	contractState, err := stateDB.OpenContractStateAccount(types.ToAccountID(testAddress))
+	assert.NoError(t, err, "could not open contract state")
+	testIndexedKeys(t, contractState, index)
+
+	for _, key := range []string{"b", "a", "", "c", "a"} {
+		_, err = contractState.AddIndexedKey(index, []byte(key))
+		assert.NoError(t, err, "add indexed key")
+	}
+	testIndexedKeys(t, contractState, index, "b", "a", "", "c")
+	testIndexedKeys(t, contractState, []byte("test_index-b"))
+
+	// paging by the cursor
+	keys, err := contractState.GetIndexedKeys(index, nil, 2)
+	assert.NoError(t, err, "get indexed keys")
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a")}, keys)
+	keys, err = contractState.GetIndexedKeys(index, keys[1], 2)
+	assert.NoError(t, err, "get indexed keys")
+	assert.Equal(t, [][]byte{{}, []byte("c")}, keys)
+	keys, err = contractState.GetIndexedKeys(index, keys[1], 2)
+	assert.NoError(t, err, "get indexed keys")
+	assert.Empty(t, keys)
+	_, err = contractState.GetIndexedKeys(index, []byte("d"), 2)
+	assert.Equal(t, ErrInvalidKeyCursor, err)
+
+	// remove the middle, the first and the last key
+	for _, key := range []string{"", "b", "c", "x"} {
+		_, err = contractState.RemoveIndexedKey(index, []byte(key))
+		assert.NoError(t, err, "remove indexed key")
+	}
+	testIndexedKeys(t, contractState, index, "a")
+
+	snapshot := contractState.Snapshot()
+	_, err = contractState.AddIndexedKey(index, []byte("d"))
+	assert.NoError(t, err, "add indexed key")
+	_, err = contractState.RemoveIndexedKey(index, []byte("a"))
+	assert.NoError(t, err, "remove indexed key")
+	testIndexedKeys(t, contractState, index, "d")
+	err = contractState.Rollback(snapshot)
+	assert.NoError(t, err, "rollback")
+	testIndexedKeys(t, contractState, index, "a")
+
+	size, err := contractState.RemoveIndexedKey(index, []byte("a"))
+	assert.NoError(t, err, "remove indexed key")
+	assert.True(t, size > 0, "no update size")
+	testIndexedKeys(t, contractState, index)
+}
@@ -0,0 +1,313 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/pkg/trie"
+	"github.com/aergoio/aergo/types"
+	"github.com/willf/bloom"
+)
+
+// The entries of the states are keyed by the hash of their contents and shared
+// by the states, so an entry can't be deleted when a state is discarded. In the
+// pruned mode, the keys written by each commit of a state are journaled under
+// the sequence of the commit, and only the journaled keys are deleted by Prune
+// once they are unreachable. The genesis state committed before the pruning is
+// enabled, the states installed by the snapshot sync and the contract codes
+// aren't journaled, so they are never deleted.
+const (
+	// pruneBulkSize is the number of the keys of a carry journal.
+	pruneBulkSize = 10000
+
+	// the number of the live entries expected by the bloom filter, which
+	// bounds the memory used by a pruning regardless of the state size
+	pruneBloomMinItems      = 1 << 20
+	pruneBloomMaxItems      = 1 << 26
+	pruneBloomFalsePositive = 0.01
+)
+
+var (
+	pruneJournalPrefix = []byte("_prune_journal_")
+	pruneCarryPrefix   = []byte("_prune_carry_")
+	pruneSeqKey        = []byte("_prune_seq_")
+
+	ErrPruneDisabled = errors.New("state pruning is not enabled")
+)
+
+// pruneJournal records the keys written by the commits of the states. A commit
+// and a deletion batch of the pruning exclude each other by the lock, so that
+// a key rewritten by a commit is never deleted.
+type pruneJournal struct {
+	sync.Mutex
+	store db.DB
+	seq   uint64
+
+	// liveItems is the number of the live entries marked by the last pruning
+	liveItems uint
+}
+
+func newPruneJournal(store db.DB) *pruneJournal {
+	j := &pruneJournal{store: store, liveItems: pruneBloomMinItems}
+	if seq := store.Get(pruneSeqKey); len(seq) == 8 {
+		j.seq = binary.BigEndian.Uint64(seq)
+	}
+	return j
+}
+
+// write adds the journal of keys to txn under the next sequence. The lock must
+// be held.
+func (j *pruneJournal) write(txn trie.DbTx, prefix, keys []byte) {
+	if len(keys) == 0 {
+		return
+	}
+	j.seq++
+	seq := make([]byte, 8)
+	binary.BigEndian.PutUint64(seq, j.seq)
+	txn.Set(pruneJournalKey(prefix, j.seq), keys)
+	txn.Set(pruneSeqKey, seq)
+}
+
+// Seq returns the sequence of the last journal.
+func (j *pruneJournal) Seq() uint64 {
+	j.Lock()
+	defer j.Unlock()
+	return j.seq
+}
+
+// entries returns the db keys of the journals of the prefix from the sequence
+// from to to.
+func (j *pruneJournal) entries(prefix []byte, from, to uint64) [][]byte {
+	if from > to {
+		return nil
+	}
+	lower := pruneJournalKey(prefix, from)
+	upper := pruneJournalKey(prefix, to+1)
+	var keys [][]byte
+	iter := j.store.Iterator(lower, upper)
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if len(key) != len(lower) || bytes.Compare(key, lower) < 0 || bytes.Compare(key, upper) >= 0 {
+			continue
+		}
+		keys = append(keys, append([]byte(nil), key...))
+	}
+	return keys
+}
+
+func pruneJournalKey(prefix []byte, seq uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], seq)
+	return key
+}
+
+// journalTx records the hash keys set through the transaction.
+type journalTx struct {
+	trie.DbTx
+	keys []byte
+}
+
+func (tx *journalTx) Set(key, value []byte) {
+	if len(key) == types.HashIDLength {
+		tx.keys = append(tx.keys, key...)
+	}
+	tx.DbTx.Set(key, value)
+}
+
+// EnablePruning makes the commits of the states journal their keys for Prune.
+func (sdb *ChainStateDB) EnablePruning() {
+	sdb.Lock()
+	defer sdb.Unlock()
+
+	if sdb.journal == nil {
+		sdb.journal = newPruneJournal(sdb.store)
+		sdb.states.journal = sdb.journal
+	}
+}
+
+// PruneSeq returns the sequence of the last journal, which covers the commit
+// of the current best state.
+func (sdb *ChainStateDB) PruneSeq() uint64 {
+	if sdb.journal == nil {
+		return 0
+	}
+	return sdb.journal.Seq()
+}
+
+// Prune deletes the entries journaled up to the sequence seq, which are not
+// reachable from root. root must be the state committed until seq, and the
+// retained states must be root or its descendants, so that every entry of
+// them is either reachable from root or journaled after seq.
+//
+// The entries reachable from root are marked in a bloom filter, so the memory
+// is bounded and a dead entry may be kept by a false positive. The kept
+// entries, dead or alive, are journaled again to be checked by the next
+// pruning with a different salt. Prune may run in the background while the
+// blocks are committed. It returns the number of the deleted entries.
+func (sdb *ChainStateDB) Prune(root []byte, seq uint64) (int, error) {
+	j := sdb.journal
+	if j == nil {
+		return 0, ErrPruneDisabled
+	}
+
+	// the carry journals written by this pruning aren't checked again
+	round := j.Seq()
+	candidates := append(j.entries(pruneJournalPrefix, 0, seq), j.entries(pruneCarryPrefix, 0, round)...)
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	m := &pruneMarker{
+		sdb:  sdb,
+		live: bloom.NewWithEstimates(j.liveItems, pruneBloomFalsePositive),
+		salt: pruneJournalKey(nil, round),
+	}
+	if err := m.markState(root); err != nil {
+		return 0, err
+	}
+	j.liveItems = boundLiveItems(m.count * 2)
+
+	s := &pruneSweeper{journal: j, marker: m, rewritten: make(map[types.HashID]struct{}), loaded: seq}
+	for _, entry := range candidates {
+		s.sweep(entry)
+	}
+	s.flushCarry()
+	return s.deleted, nil
+}
+
+func boundLiveItems(n uint) uint {
+	if n < pruneBloomMinItems {
+		return pruneBloomMinItems
+	}
+	if n > pruneBloomMaxItems {
+		return pruneBloomMaxItems
+	}
+	return n
+}
+
+// pruneMarker marks the entries reachable from the state root in the bloom
+// filter. The keys are salted by the round of the pruning, so that a dead
+// entry kept by a false positive is deleted by a later pruning.
+type pruneMarker struct {
+	sdb   *ChainStateDB
+	live  *bloom.BloomFilter
+	salt  []byte
+	count uint
+}
+
+func (m *pruneMarker) salted(key []byte) []byte {
+	return append(append([]byte(nil), m.salt...), key...)
+}
+
+func (m *pruneMarker) mark(key []byte) bool {
+	m.live.Add(m.salted(key))
+	m.count++
+	return true
+}
+
+func (m *pruneMarker) isLive(key []byte) bool {
+	return m.live.Test(m.salted(key))
+}
+
+func (m *pruneMarker) markValue(key, value []byte) error {
+	m.mark(value)
+	return nil
+}
+
+// markState marks the entries reachable from the state root.
+func (m *pruneMarker) markState(root []byte) error {
+	if len(root) == 0 {
+		return nil
+	}
+	// marker of the finalized state root
+	m.mark(common.Hasher(root))
+
+	stateTrie := trie.NewTrie(nil, common.Hasher, m.sdb.store)
+	return stateTrie.Walk(root, m.mark, m.markAccount)
+}
+
+// markAccount marks the account state and its contract storage.
+func (m *pruneMarker) markAccount(key, value []byte) error {
+	m.mark(value)
+
+	st := &types.State{}
+	if err := loadData(&m.sdb.store, value, st); err != nil {
+		return err
+	}
+	if storageRoot := common.Compactz(st.StorageRoot); len(storageRoot) > 0 {
+		storageTrie := trie.NewTrie(nil, common.Hasher, m.sdb.store)
+		return storageTrie.Walk(storageRoot, m.mark, m.markValue)
+	}
+	return nil
+}
+
+// pruneSweeper deletes the dead keys of the candidate journals. The keys
+// journaled after the pruned sequence are rewritten by the later commits, so
+// they are neither deleted nor carried.
+type pruneSweeper struct {
+	journal   *pruneJournal
+	marker    *pruneMarker
+	rewritten map[types.HashID]struct{}
+	loaded    uint64
+	carry     []byte
+	deleted   int
+}
+
+// sweep deletes the dead keys of the journal entry, and the entry itself.
+func (s *pruneSweeper) sweep(entry []byte) {
+	j := s.journal
+	keys := j.store.Get(entry)
+
+	j.Lock()
+	defer j.Unlock()
+
+	s.loadRewritten()
+	bulk := j.store.NewBulk()
+	for i := 0; i+types.HashIDLength <= len(keys); i += types.HashIDLength {
+		key := keys[i : i+types.HashIDLength]
+		if _, exist := s.rewritten[types.ToHashID(key)]; exist {
+			continue
+		}
+		if s.marker.isLive(key) {
+			s.carry = append(s.carry, key...)
+			continue
+		}
+		bulk.Delete(key)
+		s.deleted++
+	}
+	bulk.Delete(entry)
+	if len(s.carry) >= pruneBulkSize*types.HashIDLength {
+		j.write(bulk, pruneCarryPrefix, s.carry)
+		s.carry = nil
+	}
+	bulk.Flush()
+}
+
+// loadRewritten adds the keys of the journals committed since the last load.
+// The lock must be held.
+func (s *pruneSweeper) loadRewritten() {
+	j := s.journal
+	for _, entry := range j.entries(pruneJournalPrefix, s.loaded+1, j.seq) {
+		keys := j.store.Get(entry)
+		for i := 0; i+types.HashIDLength <= len(keys); i += types.HashIDLength {
+			s.rewritten[types.ToHashID(keys[i:i+types.HashIDLength])] = struct{}{}
+		}
+	}
+	s.loaded = j.seq
+}
+
+func (s *pruneSweeper) flushCarry() {
+	j := s.journal
+	j.Lock()
+	defer j.Unlock()
+
+	bulk := j.store.NewBulk()
+	j.write(bulk, pruneCarryPrefix, s.carry)
+	bulk.Flush()
+	s.carry = nil
+}
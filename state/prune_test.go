@@ -0,0 +1,51 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneJournaledStates(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	chainStateDB.EnablePruning()
+
+	commitState := func(i int) []byte {
+		assert.NoError(t, stateDB.PutState(testAccount, &testStates[i]), "put state")
+		assert.NoError(t, stateDB.Update(), "update")
+		assert.NoError(t, stateDB.Commit(), "commit")
+		return stateDB.GetRoot()
+	}
+	firstRoot := commitState(0)
+	secondRoot := commitState(1)
+	seq := chainStateDB.PruneSeq()
+	assert.True(t, stateDB.HasMarker(firstRoot))
+
+	deleted, err := chainStateDB.Prune(secondRoot, seq)
+	assert.NoError(t, err, "prune")
+	assert.NotZero(t, deleted, "deleted entries")
+	assert.False(t, stateDB.HasMarker(firstRoot), "marker of the pruned state")
+	assert.True(t, stateDB.HasMarker(secondRoot), "marker of the retained state")
+
+	st, err := chainStateDB.OpenNewStateDB(secondRoot).GetAccountState(testAccount)
+	assert.NoError(t, err, "get retained state")
+	assert.True(t, stateEquals(&testStates[1], st))
+
+	// the live entries are carried and kept by the next pruning
+	deleted, err = chainStateDB.Prune(secondRoot, seq)
+	assert.NoError(t, err, "prune again")
+	assert.Zero(t, deleted, "deleted live entries")
+	st, err = chainStateDB.OpenNewStateDB(secondRoot).GetAccountState(testAccount)
+	assert.NoError(t, err, "get retained state")
+	assert.True(t, stateEquals(&testStates[1], st))
+
+	// the entries rewritten after the pruned sequence are kept
+	thirdRoot := commitState(0)
+	deleted, err = chainStateDB.Prune(secondRoot, seq)
+	assert.NoError(t, err, "prune with a later commit")
+	assert.True(t, stateDB.HasMarker(thirdRoot), "marker of the later state")
+	st, err = chainStateDB.OpenNewStateDB(thirdRoot).GetAccountState(testAccount)
+	assert.NoError(t, err, "get later state")
+	assert.True(t, stateEquals(&testStates[0], st))
+}
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/pkg/trie"
+	"github.com/aergoio/aergo/types"
+)
+
+var (
+	ErrSnapshotInvalidRange = errors.New("snapshot: keys are not in order")
+	ErrSnapshotRootMismatch = errors.New("snapshot: root of the rebuilt trie is not matched")
+	ErrSnapshotIncomplete   = errors.New("snapshot: state is not completely downloaded")
+	ErrSnapshotSQLContract  = errors.New("snapshot: chain having sql contracts can not be synced by a snapshot")
+)
+
+// GetStateRange returns at most size leaves of the trie given a root, starting
+// from start. The values are the data stored under the leaf hashes, that is
+// the marshaled account states for the state trie and the raw contract
+// storage values for a storage trie.
+func (sdb *ChainStateDB) GetStateRange(root, start []byte, size int) ([][]byte, [][]byte, bool, error) {
+	tr := trie.NewTrie(nil, common.Hasher, sdb.store)
+	keys, hashes, more, err := tr.Range(root, start, size)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	values := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		values[i] = sdb.store.Get(hash)
+	}
+	return keys, values, more, nil
+}
+
+// GetContractCodes returns the contract codes of the code hashes. The code
+// is nil if it is not found.
+func (sdb *ChainStateDB) GetContractCodes(hashes [][]byte) [][]byte {
+	codes := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = sdb.store.Get(hash)
+	}
+	return codes
+}
+
+// Snapshot rebuilds the state of a block from the leaves of its tries which
+// are downloaded from the other nodes. The tries are built in the state db as
+// the leaves arrive. Since every entry is keyed by the hash of its content,
+// the entries of an incomplete or a forged snapshot are never reached from a
+// finalized state root. Install makes the state available after all the tries
+// are verified against their roots.
+type Snapshot struct {
+	sdb      *ChainStateDB
+	tries    map[types.HashID]*trie.Trie
+	lastKeys map[types.HashID][]byte
+	verified map[types.HashID]bool
+}
+
+// NewSnapshot returns a new snapshot builder on the state db.
+func (sdb *ChainStateDB) NewSnapshot() *Snapshot {
+	return &Snapshot{
+		sdb:      sdb,
+		tries:    make(map[types.HashID]*trie.Trie),
+		lastKeys: make(map[types.HashID][]byte),
+		verified: make(map[types.HashID]bool),
+	}
+}
+
+// Put adds the leaves of the trie whose root is root. The keys must be in
+// order over all the calls for the same root. done is true for the last
+// leaves of the trie, then the root of the rebuilt trie is compared with root.
+func (s *Snapshot) Put(root []byte, keys, values [][]byte, done bool) error {
+	id := types.ToHashID(root)
+	if s.verified[id] {
+		return nil
+	}
+	if len(keys) != len(values) {
+		return ErrSnapshotInvalidRange
+	}
+	tr, exist := s.tries[id]
+	if !exist {
+		tr = trie.NewTrie(nil, common.Hasher, s.sdb.store)
+		s.tries[id] = tr
+	}
+
+	if len(keys) > 0 {
+		last := s.lastKeys[id]
+		for _, key := range keys {
+			if len(key) != types.HashIDLength || (last != nil && bytes.Compare(last, key) >= 0) {
+				return ErrSnapshotInvalidRange
+			}
+			last = key
+		}
+		s.lastKeys[id] = last
+
+		hashes := make([][]byte, len(values))
+		bulk := s.sdb.store.NewBulk()
+		for i, value := range values {
+			hashes[i] = common.Hasher(value)
+			bulk.Set(hashes[i], value)
+		}
+		bulk.Flush()
+
+		if _, err := tr.Update(keys, hashes); err != nil {
+			return err
+		}
+		if err := tr.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if !done {
+		return nil
+	}
+	delete(s.tries, id)
+	delete(s.lastKeys, id)
+	if !bytes.Equal(tr.Root, root) {
+		return ErrSnapshotRootMismatch
+	}
+	s.verified[id] = true
+	return nil
+}
+
+// PutContractCodes saves the contract codes under their hashes.
+func (s *Snapshot) PutContractCodes(codes [][]byte) {
+	bulk := s.sdb.store.NewBulk()
+	for _, code := range codes {
+		bulk.Set(common.Hasher(code), code)
+	}
+	bulk.Flush()
+}
+
+// Install checks that the storages and the codes of all the accounts in the
+// state of root are present, and makes it the current state of the state db.
+func (s *Snapshot) Install(root []byte) error {
+	if !s.verified[types.ToHashID(root)] {
+		return ErrSnapshotIncomplete
+	}
+
+	stateTrie := trie.NewTrie(nil, common.Hasher, s.sdb.store)
+	err := stateTrie.Walk(root, func([]byte) bool { return true }, func(key, value []byte) error {
+		st := &types.State{}
+		if err := loadData(&s.sdb.store, value, st); err != nil {
+			return err
+		}
+		if st.SqlRecoveryPoint > 0 {
+			return ErrSnapshotSQLContract
+		}
+		if storageRoot := common.Compactz(st.StorageRoot); len(storageRoot) > 0 && !s.verified[types.ToHashID(storageRoot)] {
+			return ErrSnapshotIncomplete
+		}
+		if len(st.CodeHash) > 0 && len(s.sdb.store.Get(st.CodeHash)) == 0 {
+			return ErrSnapshotIncomplete
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sdb.store.Set(common.Hasher(root), stateMarker)
+	return s.sdb.SetRoot(root)
+}
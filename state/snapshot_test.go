@@ -0,0 +1,114 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func nextTestKey(key []byte) []byte {
+	next := append([]byte(nil), key...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func TestSnapshotInstall(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	testAddress := []byte("test_address")
+	contractState, err := stateDB.OpenContractStateAccount(types.ToAccountID(testAddress))
+	assert.NoError(t, err, "could not open contract state")
+	assert.NoError(t, contractState.SetCode([]byte("test_code")))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, contractState.SetData([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	assert.NoError(t, stateDB.StageContractState(contractState))
+	assert.NoError(t, stateDB.Update())
+	assert.NoError(t, stateDB.Commit())
+	root := stateDB.GetRoot()
+
+	target := NewChainStateDB()
+	_ = target.Init(string(db.BadgerImpl), "test_snapshot", nil, false)
+	defer func() {
+		_ = target.Close()
+		_ = os.RemoveAll("test_snapshot")
+	}()
+	snapshot := target.NewSnapshot()
+
+	copyTrie := func(root []byte) [][]byte {
+		var start []byte
+		var all [][]byte
+		for {
+			keys, values, more, err := chainStateDB.GetStateRange(root, start, 3)
+			assert.NoError(t, err)
+			assert.NoError(t, snapshot.Put(root, keys, values, !more))
+			all = append(all, values...)
+			if !more {
+				return all
+			}
+			start = nextTestKey(keys[len(keys)-1])
+		}
+	}
+
+	var storageRoots, codeHashes [][]byte
+	for _, value := range copyTrie(root) {
+		st := &types.State{}
+		assert.NoError(t, loadData(&chainStateDB.store, common.Hasher(value), st))
+		if len(st.StorageRoot) > 0 {
+			storageRoots = append(storageRoots, st.StorageRoot)
+		}
+		if len(st.CodeHash) > 0 {
+			codeHashes = append(codeHashes, st.CodeHash)
+		}
+	}
+	assert.Equal(t, 1, len(storageRoots))
+	assert.Equal(t, 1, len(codeHashes))
+
+	// storage and code are missing
+	assert.Equal(t, ErrSnapshotIncomplete, snapshot.Install(root))
+
+	for _, storageRoot := range storageRoots {
+		copyTrie(storageRoot)
+	}
+	snapshot.PutContractCodes(chainStateDB.GetContractCodes(codeHashes))
+	assert.NoError(t, snapshot.Install(root))
+	assert.Equal(t, root, target.GetRoot())
+	assert.True(t, target.GetStateDB().HasMarker(root))
+
+	installed, err := target.GetStateDB().OpenContractStateAccount(types.ToAccountID(testAddress))
+	assert.NoError(t, err)
+	code, err := installed.GetCode()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test_code"), code)
+	data, err := installed.GetData([]byte("key3"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value3"), data)
+}
+
+func TestSnapshotRootMismatch(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	root := chainStateDB.GetRoot()
+	keys, values, _, err := chainStateDB.GetStateRange(root, nil, 100)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, keys)
+
+	snapshot := chainStateDB.NewSnapshot()
+	values[0] = append(values[0], 0)
+	assert.Equal(t, ErrSnapshotRootMismatch, snapshot.Put(root, keys, values, true))
+
+	snapshot = chainStateDB.NewSnapshot()
+	assert.Equal(t, ErrSnapshotInvalidRange, snapshot.Put(root, [][]byte{keys[0], keys[0]}, [][]byte{values[0], values[0]}, false))
+}
@@ -64,6 +64,7 @@ type StateDB struct {
 	store    *db.DB
 	batchtx  db.Transaction
 	testmode bool
+	journal  *pruneJournal
 }
 
 // NewStateDB craete StateDB instance
@@ -83,7 +84,9 @@ func (states *StateDB) Clone() *StateDB {
 	states.lock.RLock()
 	defer states.lock.RUnlock()
 
-	return NewStateDB(states.store, states.GetRoot(), states.testmode)
+	clone := NewStateDB(states.store, states.GetRoot(), states.testmode)
+	clone.journal = states.journal
+	return clone
 }
 
 // GetRoot returns root hash of trie
@@ -485,17 +488,27 @@ func (states *StateDB) Commit() error {
 	defer states.lock.Unlock()
 
 	bulk := (*states.store).NewBulk()
+	var txn trie.DbTx = bulk
+	if states.journal != nil {
+		txn = &journalTx{DbTx: bulk}
+	}
 	for _, storage := range states.cache.storages {
 		// stage changes
-		if err := storage.stage(bulk); err != nil {
+		if err := storage.stage(txn); err != nil {
 			bulk.DiscardLast()
 			return err
 		}
 	}
-	if err := states.stage(bulk); err != nil {
+	if err := states.stage(txn); err != nil {
 		bulk.DiscardLast()
 		return err
 	}
+	if jtx, ok := txn.(*journalTx); ok {
+		// the keys are written with their journal, while no pruning deletes
+		states.journal.Lock()
+		defer states.journal.Unlock()
+		states.journal.write(bulk, pruneJournalPrefix, jtx.keys)
+	}
 	bulk.Flush()
 	return nil
 }
@@ -0,0 +1,368 @@
+package syncer
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/aergoio/aergo/internal/common"
+	"github.com/aergoio/aergo/message"
+	"github.com/aergoio/aergo/pkg/component"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// StateFetcher downloads the state of a recent block (the pivot) from the
+// sync peer and installs it to the chain, so that a new node starts the block
+// sync from the pivot instead of replaying all the blocks from the genesis.
+//
+// The pivot is the checkpoint block given by the operator, which must be
+// finalized. The peer is trusted for nothing: the pivot is accepted only if
+// the hash of its header is the checkpoint hash and it is signed by its BP.
+//
+// The state trie and the storage tries are downloaded by the ranges of their
+// leaves. The chain rebuilds each trie from the leaves and compares its root
+// with the state root of the pivot block header, or with the storage root of
+// the account state.
+//
+// The databases of the SQL contracts are not in the state trie, so the chain
+// having a SQL contract can't be synced by a snapshot.
+type StateFetcher struct {
+	compRequester component.IComponentRequester
+	chain         types.ChainAccessor
+
+	ctx *types.SyncContext
+
+	responseCh chan interface{}
+	quitCh     chan interface{}
+
+	checkpoint []byte
+	pivot      *types.Block
+	timeout    time.Duration
+
+	accounts int
+	storages int
+	codes    int
+
+	isRunning bool
+	waitGroup *sync.WaitGroup
+}
+
+var (
+	DfltStateRangeSize = uint32(1000)
+	DfltCodeReqSize    = 100
+	dfltImportTimeout  = time.Second * 60
+)
+
+var (
+	ErrQuitStateFetcher    = errors.New("StateFetcher quit")
+	ErrStateFetcherTimeout = errors.New("StateFetcher response timeout")
+	ErrInvalidPivotBlock   = errors.New("invalid pivot block for snapshot sync")
+	ErrInvalidStateChunk   = errors.New("invalid state chunk reply")
+)
+
+func newStateFetcher(ctx *types.SyncContext, compRequester component.IComponentRequester, chain types.ChainAccessor,
+	checkpoint []byte) *StateFetcher {
+	sf := &StateFetcher{ctx: ctx, compRequester: compRequester, chain: chain, checkpoint: checkpoint}
+
+	sf.quitCh = make(chan interface{})
+	sf.responseCh = make(chan interface{}, 1)
+	sf.timeout = DfltFetchTimeOut
+
+	return sf
+}
+
+// useSnapshotSync returns true if the state can be synced by a snapshot.
+func useSnapshotSync(bestNo types.BlockNo, checkpoint []byte) bool {
+	return bestNo == 0 && len(checkpoint) > 0
+}
+
+func (sf *StateFetcher) GetSeq() uint64 {
+	return sf.ctx.Seq
+}
+
+func (sf *StateFetcher) Start() {
+	sf.waitGroup = &sync.WaitGroup{}
+	sf.waitGroup.Add(1)
+
+	sf.isRunning = true
+
+	run := func() {
+		defer RecoverSyncer(NameStateFetcher, sf.GetSeq(), sf.compRequester, func() { sf.waitGroup.Done() })
+
+		logger.Info().Uint64("target", sf.ctx.TargetNo).Msg("start state fetcher")
+
+		err := sf.fetchPivot()
+		if err == nil {
+			err = sf.fetchState()
+		}
+		if err == nil {
+			err = sf.install()
+		}
+
+		switch err {
+		case nil:
+			logger.Info().Uint64("pivot", sf.pivot.BlockNo()).Int("accounts", sf.accounts).Int("storages", sf.storages).
+				Int("codes", sf.codes).Msg("StateFetcher finished")
+			closeFetcher(sf.compRequester, sf.GetSeq(), NameStateFetcher)
+		case ErrQuitStateFetcher:
+			logger.Info().Msg("StateFetcher exited")
+		default:
+			logger.Error().Err(err).Msg("StateFetcher failed")
+			stopSyncer(sf.compRequester, sf.GetSeq(), NameStateFetcher, err)
+		}
+	}
+
+	go run()
+}
+
+// fetchPivot gets the checkpoint block from the sync peer. The state of the
+// block is verified by its state root, so the block itself must be verified
+// by the checkpoint hash before downloading the state.
+func (sf *StateFetcher) fetchPivot() error {
+	sf.compRequester.TellTo(message.P2PSvc, &message.GetBlockChunks{Seq: sf.GetSeq(),
+		GetBlockInfos: message.GetBlockInfos{ToWhom: sf.ctx.PeerID, Hashes: []message.BlockHash{sf.checkpoint}}, TTL: DfltFetchTimeOut})
+	msg, err := sf.waitResponse()
+	if err != nil {
+		return err
+	}
+	blockRsp, ok := msg.(*message.GetBlockChunksRsp)
+	if !ok {
+		return ErrInvalidPivotBlock
+	}
+	if blockRsp.Err != nil {
+		return blockRsp.Err
+	}
+	if len(blockRsp.Blocks) != 1 {
+		return ErrInvalidPivotBlock
+	}
+
+	pivot := blockRsp.Blocks[0]
+	if !bytes.Equal(pivot.GetHash(), sf.checkpoint) || !pivot.ValidHash() {
+		return ErrInvalidPivotBlock
+	}
+	if pivot.BlockNo() == 0 || pivot.BlockNo() > sf.ctx.TargetNo {
+		return ErrInvalidPivotBlock
+	}
+	if valid, err := pivot.VerifySign(); !valid || err != nil {
+		return ErrInvalidPivotBlock
+	}
+
+	genesisHash, err := sf.chain.GetHashByNo(0)
+	if err != nil {
+		return err
+	}
+	genesis, err := sf.chain.GetBlock(genesisHash)
+	if err != nil {
+		return err
+	}
+	if !pivot.ValidChildOf(genesis) {
+		return ErrInvalidPivotBlock
+	}
+
+	sf.pivot = pivot
+	logger.Info().Uint64("no", pivot.BlockNo()).Str("hash", pivot.ID()).Msg("snapshot pivot block fetched")
+	return nil
+}
+
+// fetchState downloads the state trie, the storage tries and the codes of
+// the contracts.
+func (sf *StateFetcher) fetchState() error {
+	var storageRoots, codeHashes [][]byte
+	storageSet := make(map[types.HashID]bool)
+	codeSet := make(map[types.HashID]bool)
+
+	err := sf.fetchTrie(sf.pivot.GetHeader().GetBlocksRootHash(), func(values [][]byte) error {
+		for _, value := range values {
+			st := &types.State{}
+			if err := proto.Unmarshal(value, st); err != nil {
+				return ErrInvalidStateChunk
+			}
+			if st.SqlRecoveryPoint > 0 {
+				return state.ErrSnapshotSQLContract
+			}
+			if storageRoot := common.Compactz(st.StorageRoot); len(storageRoot) > 0 && !storageSet[types.ToHashID(storageRoot)] {
+				storageSet[types.ToHashID(storageRoot)] = true
+				storageRoots = append(storageRoots, storageRoot)
+			}
+			if len(st.CodeHash) > 0 && !codeSet[types.ToHashID(st.CodeHash)] {
+				codeSet[types.ToHashID(st.CodeHash)] = true
+				codeHashes = append(codeHashes, st.CodeHash)
+			}
+		}
+		sf.accounts += len(values)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, storageRoot := range storageRoots {
+		if err := sf.fetchTrie(storageRoot, nil); err != nil {
+			return err
+		}
+		sf.storages++
+	}
+
+	return sf.fetchCodes(codeHashes)
+}
+
+// fetchTrie downloads all the leaves of the trie given a root, and imports
+// them to the chain.
+func (sf *StateFetcher) fetchTrie(root []byte, onValues func(values [][]byte) error) error {
+	var start []byte
+	for {
+		sf.compRequester.TellTo(message.P2PSvc, &message.GetStateChunk{Seq: sf.GetSeq(), ToWhom: sf.ctx.PeerID,
+			Root: root, Start: start, Size: DfltStateRangeSize, TTL: DfltFetchTimeOut})
+		msg, err := sf.waitResponse()
+		if err != nil {
+			return err
+		}
+		rsp, ok := msg.(*message.GetStateChunkRsp)
+		if !ok || !bytes.Equal(rsp.Root, root) {
+			return ErrInvalidStateChunk
+		}
+		if rsp.Err != nil {
+			return rsp.Err
+		}
+		if rsp.HasNext && len(rsp.Keys) == 0 {
+			return ErrInvalidStateChunk
+		}
+
+		if onValues != nil {
+			if err := onValues(rsp.Values); err != nil {
+				return err
+			}
+		}
+
+		result, err := sf.compRequester.RequestToFutureResult(message.ChainSvc,
+			&message.ImportStateRange{Root: root, Keys: rsp.Keys, Values: rsp.Values, Done: !rsp.HasNext},
+			dfltImportTimeout, "syncer/(*StateFetcher).fetchTrie")
+		if err != nil {
+			return err
+		}
+		if err := result.(message.ImportStateRangeRsp).Err; err != nil {
+			return err
+		}
+
+		if !rsp.HasNext {
+			return nil
+		}
+		start = nextKey(rsp.Keys[len(rsp.Keys)-1])
+	}
+}
+
+// fetchCodes downloads the contract codes and imports them to the chain.
+func (sf *StateFetcher) fetchCodes(hashes [][]byte) error {
+	for len(hashes) > 0 {
+		size := DfltCodeReqSize
+		if len(hashes) < size {
+			size = len(hashes)
+		}
+
+		sf.compRequester.TellTo(message.P2PSvc, &message.GetCodeChunk{Seq: sf.GetSeq(), ToWhom: sf.ctx.PeerID,
+			Hashes: hashes[:size], TTL: DfltFetchTimeOut})
+		msg, err := sf.waitResponse()
+		if err != nil {
+			return err
+		}
+		rsp, ok := msg.(*message.GetCodeChunkRsp)
+		if !ok {
+			return ErrInvalidStateChunk
+		}
+		if rsp.Err != nil {
+			return rsp.Err
+		}
+		// the peer may return the first part of the requested codes
+		if len(rsp.Codes) == 0 || len(rsp.Codes) > size {
+			return ErrInvalidStateChunk
+		}
+		for i, code := range rsp.Codes {
+			if !bytes.Equal(common.Hasher(code), hashes[i]) {
+				return ErrInvalidStateChunk
+			}
+		}
+
+		result, err := sf.compRequester.RequestToFutureResult(message.ChainSvc,
+			&message.ImportContractCode{Codes: rsp.Codes}, dfltImportTimeout, "syncer/(*StateFetcher).fetchCodes")
+		if err != nil {
+			return err
+		}
+		if err := result.(message.ImportContractCodeRsp).Err; err != nil {
+			return err
+		}
+
+		sf.codes += len(rsp.Codes)
+		hashes = hashes[len(rsp.Codes):]
+	}
+	return nil
+}
+
+// install makes the chain start from the pivot block with the downloaded
+// state.
+func (sf *StateFetcher) install() error {
+	result, err := sf.compRequester.RequestToFutureResult(message.ChainSvc,
+		&message.InstallSnapshot{Block: sf.pivot}, dfltImportTimeout, "syncer/(*StateFetcher).install")
+	if err != nil {
+		return err
+	}
+	return result.(message.InstallSnapshotRsp).Err
+}
+
+func (sf *StateFetcher) waitResponse() (interface{}, error) {
+	timer := time.NewTimer(sf.timeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-sf.responseCh:
+		return msg, nil
+	case <-timer.C:
+		return nil, ErrStateFetcherTimeout
+	case <-sf.quitCh:
+		return nil, ErrQuitStateFetcher
+	}
+}
+
+// receive passes the response of p2p to the fetcher. The response which the
+// fetcher does not wait for is dropped.
+func (sf *StateFetcher) receive(msg interface{}) {
+	if sf == nil {
+		return
+	}
+
+	select {
+	case sf.responseCh <- msg:
+	default:
+		logger.Debug().Msgf("StateFetcher dropped unexpected response %T", msg)
+	}
+}
+
+func (sf *StateFetcher) stop() {
+	if sf == nil {
+		return
+	}
+
+	if sf.isRunning {
+		logger.Info().Msg("StateFetcher stop#1")
+
+		close(sf.quitCh)
+
+		sf.waitGroup.Wait()
+		sf.isRunning = false
+	}
+	logger.Info().Msg("StateFetcher stopped")
+}
+
+// nextKey returns the smallest key greater than key.
+func nextKey(key []byte) []byte {
+	next := append([]byte(nil), key...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aergoio/aergo-lib/log"
 	cfg "github.com/aergoio/aergo/config"
+	"github.com/aergoio/aergo/internal/enc"
 	"github.com/aergoio/aergo/pkg/component"
 
 	"fmt"
@@ -33,6 +34,7 @@ type Syncer struct {
 	finder       *Finder
 	hashFetcher  *HashFetcher
 	blockFetcher *BlockFetcher
+	stateFetcher *StateFetcher
 
 	compRequester component.IComponentRequester //for test
 }
@@ -70,6 +72,7 @@ var (
 	NameHashFetcher    = "HashFetcher"
 	NameBlockFetcher   = "BlockFetcher"
 	NameBlockProcessor = "BlockProcessor"
+	NameStateFetcher   = "StateFetcher"
 	SyncerCfg          = &SyncerConfig{
 		maxHashReqSize:   DfltHashReqSize,
 		maxBlockReqSize:  DfltBlockFetchSize,
@@ -133,10 +136,12 @@ func (syncer *Syncer) Reset(err error) {
 		syncer.finder.stop()
 		syncer.hashFetcher.stop()
 		syncer.blockFetcher.stop()
+		syncer.stateFetcher.stop()
 
 		syncer.finder = nil
 		syncer.hashFetcher = nil
 		syncer.blockFetcher = nil
+		syncer.stateFetcher = nil
 		syncer.isRunning = false
 
 		syncer.notifyStop(err)
@@ -195,6 +200,8 @@ func (syncer *Syncer) Receive(context actor.Context) {
 			*message.GetBlockChunks,
 			*message.GetBlockChunksRsp,
 			*message.AddBlockRsp,
+			*message.GetStateChunkRsp,
+			*message.GetCodeChunkRsp,
 			*message.SyncStop,
 			*message.CloseFetcher:
 			return
@@ -231,6 +238,12 @@ func (syncer *Syncer) verifySeq(inmsg interface{}) bool {
 	case *message.GetBlockChunksRsp:
 		seq = msg.Seq
 		match = isMatch(seq)
+	case *message.GetStateChunkRsp:
+		seq = msg.Seq
+		match = isMatch(seq)
+	case *message.GetCodeChunkRsp:
+		seq = msg.Seq
+		match = isMatch(seq)
 	case *message.SyncStop:
 		seq = msg.Seq
 		match = isMatch(seq)
@@ -275,6 +288,10 @@ func (syncer *Syncer) handleMessage(inmsg interface{}) {
 		syncer.hashFetcher.GetHahsesRsp(msg)
 
 	case *message.GetBlockChunksRsp:
+		if syncer.stateFetcher != nil {
+			syncer.stateFetcher.receive(msg)
+			break
+		}
 		err := syncer.blockFetcher.handleBlockRsp(msg)
 		if err != nil {
 			syncer.Reset(err)
@@ -286,6 +303,10 @@ func (syncer *Syncer) handleMessage(inmsg interface{}) {
 			syncer.Reset(err)
 			logger.Error().Err(err).Msg("AddBlockRsp failed")
 		}
+	case *message.GetStateChunkRsp:
+		syncer.stateFetcher.receive(msg)
+	case *message.GetCodeChunkRsp:
+		syncer.stateFetcher.receive(msg)
 	case *message.SyncStop:
 		if msg.Err == nil {
 			logger.Info().Str("from", msg.FromWho).Msg("syncer try to stop successfully")
@@ -298,6 +319,8 @@ func (syncer *Syncer) handleMessage(inmsg interface{}) {
 			syncer.hashFetcher.stop()
 		} else if msg.FromWho == NameBlockFetcher {
 			syncer.blockFetcher.stop()
+		} else if msg.FromWho == NameStateFetcher {
+			syncer.handleSnapshotInstalled()
 		} else {
 			logger.Error().Msg("invalid closing module message to syncer")
 		}
@@ -347,12 +370,36 @@ func (syncer *Syncer) handleSyncStart(msg *message.SyncStart) error {
 	syncer.ctx = types.NewSyncCtx(syncer.GetSeq(), msg.PeerID, msg.TargetNo, bestBlockNo, msg.NotifyC)
 	syncer.isRunning = true
 
+	if checkpoint := syncer.snapshotCheckpoint(); useSnapshotSync(bestBlockNo, checkpoint) {
+		logger.Info().Uint64("seq", syncer.GetSeq()).Str("checkpoint", enc.ToString(checkpoint)).
+			Msg("syncer starts with the snapshot of state")
+
+		syncer.stateFetcher = newStateFetcher(syncer.ctx, syncer.getCompRequester(), syncer.chain, checkpoint)
+		syncer.stateFetcher.Start()
+		return nil
+	}
+
 	syncer.finder = newFinder(syncer.ctx, syncer.getCompRequester(), syncer.chain, syncer.syncerCfg)
 	syncer.finder.start()
 
 	return err
 }
 
+// snapshotCheckpoint returns the hash of the block to sync the state from, or
+// nil if the snapshot sync isn't enabled.
+func (syncer *Syncer) snapshotCheckpoint() []byte {
+	if syncer.cfg == nil || syncer.cfg.Blockchain == nil || !syncer.cfg.Blockchain.SnapshotSync {
+		return nil
+	}
+	checkpoint, err := enc.ToBytes(syncer.cfg.Blockchain.SnapshotCheckpoint)
+	if err != nil || len(checkpoint) != types.HashIDLength {
+		logger.Error().Str("checkpoint", syncer.cfg.Blockchain.SnapshotCheckpoint).
+			Msg("snapshot sync is disabled by the invalid checkpoint hash")
+		return nil
+	}
+	return checkpoint
+}
+
 func (syncer *Syncer) handleAncestorRsp(msg *message.GetSyncAncestorRsp) {
 	var ancestorNo uint64
 
@@ -416,6 +463,26 @@ func (syncer *Syncer) handleFinderResult(msg *message.FinderResult) error {
 	return nil
 }
 
+// handleSnapshotInstalled starts to fetch the blocks after the pivot block
+// whose state is installed by the state fetcher.
+func (syncer *Syncer) handleSnapshotInstalled() {
+	pivot := syncer.stateFetcher.pivot
+
+	syncer.stateFetcher.stop()
+	syncer.stateFetcher = nil
+
+	logger.Info().Uint64("pivot", pivot.BlockNo()).Msg("syncer received snapshot installed message")
+
+	syncer.ctx.BestNo = pivot.BlockNo()
+	syncer.ctx.SetAncestor(pivot)
+
+	syncer.blockFetcher = newBlockFetcher(syncer.ctx, syncer.getCompRequester(), syncer.syncerCfg)
+	syncer.hashFetcher = newHashFetcher(syncer.ctx, syncer.getCompRequester(), syncer.blockFetcher.hfCh, syncer.syncerCfg)
+
+	syncer.blockFetcher.Start()
+	syncer.hashFetcher.Start()
+}
+
 func (syncer *Syncer) Statistics() *map[string]interface{} {
 	var start, end, total, added, blockfetched uint64
 
@@ -1,17 +1,12 @@
 package main
 
 import (
-	"bufio"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 
 	"github.com/aergoio/aergo/cmd/aergocli/util"
-	"github.com/aergoio/aergo/internal/enc"
-	"github.com/aergoio/aergo/types"
-	"github.com/gogo/protobuf/proto"
+	"github.com/aergoio/aergo/mempool/journal"
 )
 
 func main() {
@@ -23,35 +18,13 @@ func main() {
 
 	filename := argsWithoutProg[0]
 
-	file, err := os.Open(filename)
+	// the journal is replayed, so only the pending txs are printed
+	txs, err := journal.Load(filename)
 	if err != nil {
-		fmt.Println("error: failed to open file")
-		return
+		fmt.Printf("error: read file err (%s), print txs before it\n", err)
 	}
-	reader := csv.NewReader(bufio.NewReader(file))
-	var count int
-	for {
-		buf := types.Tx{}
-		rc, err := reader.Read()
-		if err != nil {
-			if err != io.EOF {
-				fmt.Println("error: read file err")
-			}
-			break
-		}
-		count++
-		dataBuf, err := enc.ToBytes(rc[0])
-		if err != nil {
-			fmt.Println("error: decode tx err, continue")
-			continue
-		}
-		err = proto.Unmarshal(dataBuf, &buf)
-		if err != nil {
-			fmt.Println("error: unmarshall tx err, continue")
-			continue
-		}
-		//mp.put(types.NewTransaction(&buf)) // nolint: errcheck
-		b, e := json.MarshalIndent(util.ConvTx(types.NewTransaction(&buf).GetTx()), "", " ")
+	for _, tx := range txs {
+		b, e := json.MarshalIndent(util.ConvTx(tx), "", " ")
 		if e == nil {
 			fmt.Printf("%s\n", b)
 		} else {
@@ -59,6 +32,6 @@ func main() {
 		}
 	}
 
-	//fmt.Println("total ", count, "txs")
+	//fmt.Println("total ", len(txs), "txs")
 
 }
@@ -0,0 +1,296 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// The types of the parameters, the return values and the event arguments,
+// which the contract declares by abi.types and abi.event. A type suffixed by
+// [] is the array of the type.
+const (
+	ABITypeAny     = "any"
+	ABITypeString  = "string"
+	ABITypeNumber  = "number"
+	ABITypeInteger = "integer"
+	ABITypeBoolean = "boolean"
+	ABITypeBignum  = "bignum"
+	ABITypeAddress = "address"
+	ABITypeBytes   = "bytes"
+	ABITypeTable   = "table"
+
+	abiTypeArraySuffix = "[]"
+
+	// abiNumberPrec is the precision to keep the integer of a bignum exactly.
+	abiNumberPrec = 512
+)
+
+var abiTypes = map[string]bool{
+	ABITypeAny:     true,
+	ABITypeString:  true,
+	ABITypeNumber:  true,
+	ABITypeInteger: true,
+	ABITypeBoolean: true,
+	ABITypeBignum:  true,
+	ABITypeAddress: true,
+	ABITypeBytes:   true,
+	ABITypeTable:   true,
+}
+
+// IsValidABIType reports whether t is a type of the ABI.
+func IsValidABIType(t string) bool {
+	return abiTypes[strings.TrimSuffix(t, abiTypeArraySuffix)]
+}
+
+// Function returns the function of the name, or the default function if the
+// contract doesn't have the function. It returns nil if neither is found.
+func (m *ABI) Function(name string) *Function {
+	var defaultFunc *Function
+	for _, f := range m.GetFunctions() {
+		if f.Name == name {
+			return f
+		}
+		if f.Name == "default" {
+			defaultFunc = f
+		}
+	}
+	return defaultFunc
+}
+
+// SetFunctionTypes sets the types of the parameters and the return values of
+// the registered function.
+func (m *ABI) SetFunctionTypes(name string, params, returns []string) error {
+	var f *Function
+	for _, v := range m.GetFunctions() {
+		if v.Name == name {
+			f = v
+			break
+		}
+	}
+	if f == nil {
+		return fmt.Errorf("types of the unregistered function: %s", name)
+	}
+	if len(params) != len(f.Arguments) {
+		return fmt.Errorf("the number of the parameter types of %s is %d, but expected %d",
+			name, len(params), len(f.Arguments))
+	}
+	for _, t := range append(append([]string{}, params...), returns...) {
+		if !IsValidABIType(t) {
+			return fmt.Errorf("invalid type of %s: %s", name, t)
+		}
+	}
+	for i, t := range params {
+		f.Arguments[i].Type = t
+	}
+	f.Returns = returns
+	return nil
+}
+
+// AddEvent adds the schema of the event of the name and the argument types.
+func (m *ABI) AddEvent(name string, args []string) error {
+	for _, e := range m.Events {
+		if e.Name == name {
+			return fmt.Errorf("duplicated event: %s", name)
+		}
+	}
+	event := &EventSchema{Name: name}
+	for _, t := range args {
+		if !IsValidABIType(t) {
+			return fmt.Errorf("invalid type of the event %s: %s", name, t)
+		}
+		event.Arguments = append(event.Arguments, &FnArgument{Type: t})
+	}
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+// EncodeCallInfo validates the arguments of the call against the parameter
+// types of the function, and encodes them in the form which the contract
+// receives. The call of the function without types isn't changed.
+func (m *ABI) EncodeCallInfo(ci *CallInfo) error {
+	f := m.Function(ci.Name)
+	if f == nil {
+		return nil
+	}
+	args, err := f.EncodeArgs(ci.Args)
+	if err != nil {
+		return err
+	}
+	ci.Args = args
+	return nil
+}
+
+// EncodeArgs validates and encodes the arguments by the parameter types. An
+// absent or null argument is passed as nil to the contract regardless of the
+// type.
+func (m *Function) EncodeArgs(args []interface{}) ([]interface{}, error) {
+	if !m.isTyped() {
+		return args, nil
+	}
+	if len(args) > len(m.Arguments) {
+		return nil, fmt.Errorf("too many arguments of %s: %d (expected %d)", m.Name, len(args), len(m.Arguments))
+	}
+	encoded := make([]interface{}, len(args))
+	for i, arg := range args {
+		v, err := encodeABIValue(m.Arguments[i].Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %s of %s: %s", m.Arguments[i].Name, m.Name, err.Error())
+		}
+		encoded[i] = v
+	}
+	return encoded, nil
+}
+
+func (m *Function) isTyped() bool {
+	for _, arg := range m.Arguments {
+		if arg.Type != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeABIValue(t string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if strings.HasSuffix(t, abiTypeArraySuffix) {
+		arr, ok := v.([]interface{})
+		if !ok {
+			return nil, abiTypeError(t, v)
+		}
+		elemType := strings.TrimSuffix(t, abiTypeArraySuffix)
+		encoded := make([]interface{}, len(arr))
+		for i, elem := range arr {
+			e, err := encodeABIValue(elemType, elem)
+			if err != nil {
+				return nil, err
+			}
+			encoded[i] = e
+		}
+		return encoded, nil
+	}
+
+	switch t {
+	case "", ABITypeAny:
+		return v, nil
+	case ABITypeString:
+		if _, ok := v.(string); ok {
+			return v, nil
+		}
+	case ABITypeNumber:
+		if _, ok := abiNumber(v); ok {
+			return v, nil
+		}
+	case ABITypeInteger:
+		if n, ok := abiNumber(v); ok && n.IsInt() {
+			return v, nil
+		}
+	case ABITypeBoolean:
+		if _, ok := v.(bool); ok {
+			return v, nil
+		}
+	case ABITypeBignum:
+		if n := abiBignum(v); n != nil {
+			return map[string]interface{}{"_bignum": n.String()}, nil
+		}
+	case ABITypeAddress:
+		if s, ok := v.(string); ok {
+			if len(s) == NameLength {
+				return v, nil
+			}
+			if address, err := DecodeAddress(s); err == nil && len(address) == AddressLength {
+				return v, nil
+			}
+			return nil, errors.New("invalid address: " + s)
+		}
+	case ABITypeBytes:
+		if s, ok := v.(string); ok {
+			if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+				s = s[2:]
+			}
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				return nil, errors.New("invalid hex string: " + err.Error())
+			}
+			return "0x" + hex.EncodeToString(b), nil
+		}
+	case ABITypeTable:
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return v, nil
+		}
+	default:
+		return nil, errors.New("unknown type: " + t)
+	}
+	return nil, abiTypeError(t, v)
+}
+
+func abiTypeError(t string, v interface{}) error {
+	var got string
+	switch v.(type) {
+	case string:
+		got = "string"
+	case bool:
+		got = "boolean"
+	case json.Number, float64, float32, int, int64, uint64:
+		got = "number"
+	case []interface{}:
+		got = "array"
+	case map[string]interface{}:
+		got = "table"
+	default:
+		got = fmt.Sprintf("%T", v)
+	}
+	return fmt.Errorf("%s expected, got %s", t, got)
+}
+
+// abiNumber returns the JSON number, which is decoded either as json.Number or
+// as float64.
+func abiNumber(v interface{}) (*big.Float, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, ok := new(big.Float).SetPrec(abiNumberPrec).SetString(n.String())
+		return f, ok
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, false
+		}
+		return big.NewFloat(n), true
+	case int:
+		return new(big.Float).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Float).SetInt64(n), true
+	case uint64:
+		return new(big.Float).SetUint64(n), true
+	}
+	return nil, false
+}
+
+// abiBignum returns the integer of the number, the decimal string or the
+// bignum object such as {"_bignum":"100"}.
+func abiBignum(v interface{}) *big.Int {
+	switch n := v.(type) {
+	case string:
+		if i, ok := new(big.Int).SetString(n, 10); ok {
+			return i
+		}
+	case map[string]interface{}:
+		for k, v := range n {
+			if s, ok := v.(string); ok && len(n) == 1 && strings.EqualFold(k, "_bignum") {
+				return abiBignum(s)
+			}
+		}
+	default:
+		if f, ok := abiNumber(v); ok && f.IsInt() {
+			i, _ := f.Int(nil)
+			return i
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestABIEncodeCallInfo(t *testing.T) {
+	var abi ABI
+	err := json.Unmarshal([]byte(`{"version":"0.2","language":"lua","functions":[
+		{"name":"transfer","arguments":[{"name":"to"},{"name":"amount"},{"name":"memo"}]},
+		{"name":"batch","arguments":[{"name":"ids"},{"name":"data"}]},
+		{"name":"untyped","arguments":[{"name":"a"}]}]}`), &abi)
+	assert.NoError(t, err)
+
+	assert.NoError(t, abi.SetFunctionTypes("transfer", []string{"address", "bignum", "string"}, []string{"boolean"}))
+	assert.NoError(t, abi.SetFunctionTypes("batch", []string{"integer[]", "bytes"}, nil))
+	assert.Error(t, abi.SetFunctionTypes("batch", []string{"integer"}, nil), "wrong number of the types")
+	assert.Error(t, abi.SetFunctionTypes("batch", []string{"int", "bytes"}, nil), "invalid type")
+	assert.Error(t, abi.SetFunctionTypes("unknown", nil, nil), "unregistered function")
+	assert.NoError(t, abi.AddEvent("Transfer", []string{"address", "address", "bignum"}))
+	assert.Error(t, abi.AddEvent("Transfer", nil), "duplicated event")
+
+	address := EncodeAddress(make([]byte, AddressLength))
+	ci := &CallInfo{Name: "transfer", Args: []interface{}{address, json.Number("1000000000000000000001")}}
+	assert.NoError(t, abi.EncodeCallInfo(ci))
+	assert.Equal(t, []interface{}{address, map[string]interface{}{"_bignum": "1000000000000000000001"}}, ci.Args)
+
+	ci = &CallInfo{Name: "transfer", Args: []interface{}{"aergo.system", map[string]interface{}{"_bignum": "5"}, nil}}
+	assert.NoError(t, abi.EncodeCallInfo(ci))
+	assert.Equal(t, []interface{}{"aergo.system", map[string]interface{}{"_bignum": "5"}, nil}, ci.Args)
+
+	for _, args := range [][]interface{}{
+		{"invalid", "1"},
+		{address, "1.5"},
+		{address, 1.5},
+		{address, "1", 1},
+		{address, "1", "memo", "too many"},
+	} {
+		assert.Error(t, abi.EncodeCallInfo(&CallInfo{Name: "transfer", Args: args}), "%v", args)
+	}
+
+	ci = &CallInfo{Name: "batch", Args: []interface{}{[]interface{}{1.0, json.Number("2")}, "0xABCD"}}
+	assert.NoError(t, abi.EncodeCallInfo(ci))
+	assert.Equal(t, []interface{}{[]interface{}{1.0, json.Number("2")}, "0xabcd"}, ci.Args)
+	assert.Error(t, abi.EncodeCallInfo(&CallInfo{Name: "batch", Args: []interface{}{[]interface{}{1.5}}}))
+	assert.Error(t, abi.EncodeCallInfo(&CallInfo{Name: "batch", Args: []interface{}{nil, "0xzz"}}))
+
+	// the call of the function without types or of an unknown function is kept
+	ci = &CallInfo{Name: "untyped", Args: []interface{}{1.5, "x"}}
+	assert.NoError(t, abi.EncodeCallInfo(ci))
+	assert.Equal(t, []interface{}{1.5, "x"}, ci.Args)
+	assert.NoError(t, abi.EncodeCallInfo(&CallInfo{Name: "unknown", Args: []interface{}{1}}))
+}
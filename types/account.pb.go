@@ -94,9 +94,123 @@ func (m *AccountList) GetAccounts() []*Account {
 	return nil
 }
 
+// HDAccountRequest is the request to recover the keys of a HD wallet from the mnemonic
+type HDAccountRequest struct {
+	Mnemonic             string   `protobuf:"bytes,1,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	Account              uint32   `protobuf:"varint,2,opt,name=account,proto3" json:"account,omitempty"`
+	Index                uint32   `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Count                uint32   `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Passphrase           string   `protobuf:"bytes,5,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HDAccountRequest) Reset()         { *m = HDAccountRequest{} }
+func (m *HDAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*HDAccountRequest) ProtoMessage()    {}
+func (m *HDAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HDAccountRequest.Unmarshal(m, b)
+}
+func (m *HDAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HDAccountRequest.Marshal(b, m, deterministic)
+}
+func (m *HDAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HDAccountRequest.Merge(m, src)
+}
+func (m *HDAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_HDAccountRequest.Size(m)
+}
+func (m *HDAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HDAccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HDAccountRequest proto.InternalMessageInfo
+
+func (m *HDAccountRequest) GetMnemonic() string {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return ""
+}
+
+func (m *HDAccountRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
+}
+
+func (m *HDAccountRequest) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *HDAccountRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *HDAccountRequest) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+// HDAccounts is the accounts of a HD wallet, which has the mnemonic only when the wallet is created
+type HDAccounts struct {
+	Mnemonic             string     `protobuf:"bytes,1,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	Accounts             []*Account `protobuf:"bytes,2,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *HDAccounts) Reset()         { *m = HDAccounts{} }
+func (m *HDAccounts) String() string { return proto.CompactTextString(m) }
+func (*HDAccounts) ProtoMessage()    {}
+func (m *HDAccounts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HDAccounts.Unmarshal(m, b)
+}
+func (m *HDAccounts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HDAccounts.Marshal(b, m, deterministic)
+}
+func (m *HDAccounts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HDAccounts.Merge(m, src)
+}
+func (m *HDAccounts) XXX_Size() int {
+	return xxx_messageInfo_HDAccounts.Size(m)
+}
+func (m *HDAccounts) XXX_DiscardUnknown() {
+	xxx_messageInfo_HDAccounts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HDAccounts proto.InternalMessageInfo
+
+func (m *HDAccounts) GetMnemonic() string {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return ""
+}
+
+func (m *HDAccounts) GetAccounts() []*Account {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "types.Account")
 	proto.RegisterType((*AccountList)(nil), "types.AccountList")
+	proto.RegisterType((*HDAccountRequest)(nil), "types.HDAccountRequest")
+	proto.RegisterType((*HDAccounts)(nil), "types.HDAccounts")
 }
 
 func init() { proto.RegisterFile("account.proto", fileDescriptor_8e28828dcb8d24f0) }
@@ -0,0 +1,125 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/aergoio/aergo/fee"
+)
+
+// A batch tx carries the ordered list of operations, which are signed once by
+// the sender and executed atomically. Each operation is executed as a normal
+// or governance tx of the sender, which shares the nonce, the gas limit and
+// the gas price of the batch tx. If an operation fails, the changes of all the
+// operations are rolled back and the fee used so far is charged.
+const (
+	// MaxBatchOps is the maximum number of the operations of a batch tx.
+	MaxBatchOps = 32
+)
+
+var (
+	//ErrTxInvalidBatch is returned if the operations of the batch tx are malformed
+	ErrTxInvalidBatch = errors.New("tx invalid batch")
+)
+
+// OpBody returns the body of the tx, which executes the i-th operation of the
+// batch tx.
+func (b *TxBody) OpBody(i int) *TxBody {
+	op := b.Ops[i]
+	return &TxBody{
+		Nonce:       b.Nonce,
+		Account:     b.Account,
+		Recipient:   op.Recipient,
+		Amount:      op.Amount,
+		Payload:     op.Payload,
+		GasLimit:    b.GasLimit,
+		GasPrice:    b.GasPrice,
+		Type:        op.Type,
+		ChainIdHash: b.ChainIdHash,
+	}
+}
+
+// WriteOps adds the operations of the batch tx to the hash of the tx. The
+// length of each field is written as well, so that the fields of adjacent
+// operations can't be shifted without changing the hash.
+func (b *TxBody) WriteOps(w io.Writer) {
+	for _, op := range b.Ops {
+		binary.Write(w, binary.LittleEndian, op.GetType())
+		for _, field := range [][]byte{op.GetRecipient(), op.GetAmount(), op.GetPayload()} {
+			binary.Write(w, binary.LittleEndian, uint32(len(field)))
+			w.Write(field)
+		}
+	}
+}
+
+// opsAmount returns the total amount sent by the operations of the batch tx.
+func (b *TxBody) opsAmount() *big.Int {
+	amount := new(big.Int)
+	for _, op := range b.Ops {
+		amount.Add(amount, new(big.Int).SetBytes(op.GetAmount()))
+	}
+	return amount
+}
+
+// opsMaxFee returns the maximum fee of the operations of the batch tx, which
+// aren't gas metered. The governance operations are free as the governance
+// txs.
+func (b *TxBody) opsMaxFee() *big.Int {
+	maxFee := new(big.Int)
+	for _, op := range b.Ops {
+		if op.GetType() == TxType_NORMAL {
+			maxFee.Add(maxFee, fee.MaxPayloadTxFee(len(op.GetPayload())))
+		}
+	}
+	return maxFee
+}
+
+// validateBatch checks that only the batch tx has the operations, each of
+// which is a valid normal or governance tx. The batch tx itself sends and
+// calls nothing.
+func validateBatch(tx *TxBody) error {
+	if tx.GetType() != TxType_BATCH {
+		if len(tx.GetOps()) != 0 {
+			return ErrTxInvalidBatch
+		}
+		return nil
+	}
+	if len(tx.GetOps()) == 0 || len(tx.GetOps()) > MaxBatchOps {
+		return ErrTxInvalidBatch
+	}
+	if len(tx.GetRecipient()) != 0 || len(tx.GetAmount()) != 0 || len(tx.GetPayload()) != 0 {
+		return ErrTxInvalidBatch
+	}
+
+	var txGas uint64
+	for i := range tx.Ops {
+		op := tx.OpBody(i)
+		if len(op.GetRecipient()) == 0 || len(op.GetRecipient()) > AddressLength {
+			return ErrTxInvalidRecipient
+		}
+		if op.GetAmountBigInt().Cmp(MaxAER) > 0 {
+			return ErrTxInvalidAmount
+		}
+		switch op.GetType() {
+		case TxType_NORMAL:
+			txGas += fee.TxGas(len(op.GetPayload()))
+		case TxType_GOVERNANCE:
+			if err := validateGovernanceTx(op); err != nil {
+				return err
+			}
+		default:
+			return ErrTxInvalidBatch
+		}
+	}
+	if fee.IsGasMetered(tx.GetGasLimit()) && tx.GetGasLimit() < txGas {
+		return ErrTxGasLimitTooLow
+	}
+	return nil
+}
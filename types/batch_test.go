@@ -0,0 +1,62 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchTx(t *testing.T) {
+	account := make([]byte, AddressLength)
+	account[0] = 0x02
+	recipient := make([]byte, AddressLength)
+	recipient[0] = 0x03
+	chainIdHash := []byte("chain id hash")
+
+	newTx := func(ops ...*BatchOp) Transaction {
+		tx := &Tx{Body: &TxBody{
+			Nonce:       1,
+			Account:     account,
+			Type:        TxType_BATCH,
+			ChainIdHash: chainIdHash,
+			Sign:        []byte("sign"),
+			Ops:         ops,
+		}}
+		tx.Hash = tx.CalculateTxHash()
+		return NewTransaction(tx)
+	}
+	transfer := &BatchOp{Recipient: recipient, Amount: new(big.Int).SetUint64(10).Bytes()}
+	stake := &BatchOp{Type: TxType_GOVERNANCE, Recipient: []byte(AergoSystem), Amount: StakingMinimum.Bytes(),
+		Payload: []byte(`{"Name":"v1stake"}`)}
+
+	assert.NoError(t, newTx(transfer, stake).Validate(chainIdHash))
+	assert.Equal(t, ErrTxInvalidBatch, newTx().Validate(chainIdHash))
+	assert.Equal(t, ErrTxInvalidRecipient, newTx(&BatchOp{}).Validate(chainIdHash))
+	assert.Equal(t, ErrTxInvalidBatch, newTx(&BatchOp{Type: TxType_BATCH, Recipient: recipient}).Validate(chainIdHash))
+	assert.Equal(t, ErrTxInvalidPayload,
+		newTx(&BatchOp{Type: TxType_GOVERNANCE, Recipient: []byte(AergoSystem), Payload: []byte("{}")}).Validate(chainIdHash))
+
+	// the batch tx itself sends nothing
+	tx := newTx(transfer)
+	tx.GetBody().Amount = transfer.Amount
+	tx.GetTx().Hash = tx.CalculateTxHash()
+	assert.Equal(t, ErrTxInvalidBatch, tx.Validate(chainIdHash))
+
+	// the operations are only for the batch tx
+	tx = newTx(transfer)
+	tx.GetBody().Type = TxType_NORMAL
+	tx.GetBody().Recipient = recipient
+	tx.GetTx().Hash = tx.CalculateTxHash()
+	assert.Equal(t, ErrTxInvalidBatch, tx.Validate(chainIdHash))
+
+	// the sender pays the amounts of all the operations
+	tx = newTx(transfer, stake)
+	spending := new(big.Int).Add(StakingMinimum, new(big.Int).SetUint64(10))
+	assert.Equal(t, ErrInsufficientBalance, tx.ValidateWithSenderState(&State{Balance: StakingMinimum.Bytes()}))
+	assert.NoError(t, tx.ValidateWithSenderState(&State{Balance: new(big.Int).Add(spending, tx.GetMaxFee()).Bytes()}))
+
+	// the fields of the operations can't be shifted
+	shifted := newTx(&BatchOp{Recipient: recipient[:AddressLength-1], Amount: append(recipient[AddressLength-1:], transfer.Amount...)})
+	assert.NotEqual(t, newTx(transfer).GetHash(), shifted.GetHash())
+}
@@ -292,6 +292,11 @@ func (block *Block) BlockHash() []byte {
 	return block.GetHash()
 }
 
+// ValidHash reports whether the hash of the block is the hash of its header.
+func (block *Block) ValidHash() bool {
+	return bytes.Equal(block.GetHash(), block.calculateBlockHash())
+}
+
 // BlockID converts block hash ([]byte) to BlockID.
 func (block *Block) BlockID() BlockID {
 	return ToBlockID(block.BlockHash())
@@ -499,7 +504,12 @@ func (tx *Tx) CalculateTxHash() []byte {
 	digest.Write(txBody.GasPrice)
 	binary.Write(digest, binary.LittleEndian, txBody.Type)
 	digest.Write(txBody.ChainIdHash)
+	txBody.writeValidityWindow(digest)
+	digest.Write(txBody.FeePayer)
 	digest.Write(txBody.Sign)
+	digest.Write(txBody.PayerSign)
+	txBody.writeMultisig(digest)
+	txBody.WriteOps(digest)
 	return digest.Sum(nil)
 }
 
@@ -533,6 +543,13 @@ func (tx *Tx) Clone() *Tx {
 		Type:        tx.Body.Type,
 		ChainIdHash: Clone(tx.Body.ChainIdHash).([]byte),
 		Sign:        Clone(tx.Body.Sign).([]byte),
+		NotBefore:   tx.Body.NotBefore,
+		ValidUntil:  tx.Body.ValidUntil,
+		FeePayer:    Clone(tx.Body.FeePayer).([]byte),
+		PayerSign:   Clone(tx.Body.PayerSign).([]byte),
+		Multisig:    tx.Body.Multisig,
+		Signatures:  tx.Body.Signatures,
+		Ops:         tx.Body.Ops,
 	}
 	res := &Tx{
 		Body: body,
@@ -541,6 +558,24 @@ func (tx *Tx) Clone() *Tx {
 	return res
 }
 
+// Match reports whether tx satisfies all the conditions of the filter. An
+// empty filter matches every tx. Contract matches the txs which call the
+// contract with a payload.
+func (f *TxFilter) Match(tx *Tx) bool {
+	body := tx.GetBody()
+	if len(f.GetSender()) != 0 && !bytes.Equal(f.GetSender(), body.GetAccount()) {
+		return false
+	}
+	if len(f.GetRecipient()) != 0 && !bytes.Equal(f.GetRecipient(), body.GetRecipient()) {
+		return false
+	}
+	if len(f.GetContract()) != 0 &&
+		(len(body.GetPayload()) == 0 || !bytes.Equal(f.GetContract(), body.GetRecipient())) {
+		return false
+	}
+	return true
+}
+
 func (b *TxBody) GetAmountBigInt() *big.Int {
 	return new(big.Int).SetBytes(b.GetAmount())
 }
@@ -549,6 +584,39 @@ func (b *TxBody) GetGasPriceBigInt() *big.Int {
 	return new(big.Int).SetBytes(b.GetGasPrice())
 }
 
+// HasValidityWindow reports whether the tx is valid only in a range of blocks.
+// A zero NotBefore or ValidUntil leaves that side of the range open.
+func (b *TxBody) HasValidityWindow() bool {
+	return b.GetNotBefore() != 0 || b.GetValidUntil() != 0
+}
+
+// ValidateWindow checks that the tx can be included in the block of blockNo.
+func (b *TxBody) ValidateWindow(blockNo BlockNo) error {
+	if blockNo < b.GetNotBefore() {
+		return ErrTxNotYetValid
+	}
+	if b.IsExpired(blockNo) {
+		return ErrTxExpired
+	}
+	return nil
+}
+
+// IsExpired reports whether the tx can't be included in the block of blockNo
+// or any block after it.
+func (b *TxBody) IsExpired(blockNo BlockNo) bool {
+	return b.GetValidUntil() != 0 && blockNo > b.GetValidUntil()
+}
+
+// writeValidityWindow adds the validity window to the hash of the tx. Nothing
+// is written for the tx without the window, so that the hashes of the txs
+// made before the window was introduced don't change.
+func (b *TxBody) writeValidityWindow(w io.Writer) {
+	if b.HasValidityWindow() {
+		binary.Write(w, binary.LittleEndian, b.NotBefore)
+		binary.Write(w, binary.LittleEndian, b.ValidUntil)
+	}
+}
+
 type MovingAverage struct {
 	values []int64
 	size   int
@@ -21,18 +21,30 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type TxType int32
 
 const (
-	TxType_NORMAL     TxType = 0
-	TxType_GOVERNANCE TxType = 1
+	TxType_NORMAL         TxType = 0
+	TxType_GOVERNANCE     TxType = 1
+	TxType_FEE_DELEGATION TxType = 2
+	TxType_MULTISIG       TxType = 3
+	TxType_BATCH          TxType = 4
+	TxType_REDEPLOY       TxType = 5
 )
 
 var TxType_name = map[int32]string{
 	0: "NORMAL",
 	1: "GOVERNANCE",
+	2: "FEE_DELEGATION",
+	3: "MULTISIG",
+	4: "BATCH",
+	5: "REDEPLOY",
 }
 
 var TxType_value = map[string]int32{
-	"NORMAL":     0,
-	"GOVERNANCE": 1,
+	"NORMAL":         0,
+	"GOVERNANCE":     1,
+	"FEE_DELEGATION": 2,
+	"MULTISIG":       3,
+	"BATCH":          4,
+	"REDEPLOY":       5,
 }
 
 func (x TxType) String() string {
@@ -338,19 +350,26 @@ func (m *Tx) GetBody() *TxBody {
 }
 
 type TxBody struct {
-	Nonce                uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Account              []byte   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
-	Recipient            []byte   `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	Amount               []byte   `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	Payload              []byte   `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
-	GasLimit             uint64   `protobuf:"varint,6,opt,name=gasLimit,proto3" json:"gasLimit,omitempty"`
-	GasPrice             []byte   `protobuf:"bytes,7,opt,name=gasPrice,proto3" json:"gasPrice,omitempty"`
-	Type                 TxType   `protobuf:"varint,8,opt,name=type,proto3,enum=types.TxType" json:"type,omitempty"`
-	ChainIdHash          []byte   `protobuf:"bytes,9,opt,name=chainIdHash,proto3" json:"chainIdHash,omitempty"`
-	Sign                 []byte   `protobuf:"bytes,10,opt,name=sign,proto3" json:"sign,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Nonce                uint64               `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Account              []byte               `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Recipient            []byte               `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount               []byte               `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Payload              []byte               `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	GasLimit             uint64               `protobuf:"varint,6,opt,name=gasLimit,proto3" json:"gasLimit,omitempty"`
+	GasPrice             []byte               `protobuf:"bytes,7,opt,name=gasPrice,proto3" json:"gasPrice,omitempty"`
+	Type                 TxType               `protobuf:"varint,8,opt,name=type,proto3,enum=types.TxType" json:"type,omitempty"`
+	ChainIdHash          []byte               `protobuf:"bytes,9,opt,name=chainIdHash,proto3" json:"chainIdHash,omitempty"`
+	Sign                 []byte               `protobuf:"bytes,10,opt,name=sign,proto3" json:"sign,omitempty"`
+	NotBefore            uint64               `protobuf:"varint,11,opt,name=notBefore,proto3" json:"notBefore,omitempty"`
+	ValidUntil           uint64               `protobuf:"varint,12,opt,name=validUntil,proto3" json:"validUntil,omitempty"`
+	FeePayer             []byte               `protobuf:"bytes,13,opt,name=feePayer,proto3" json:"feePayer,omitempty"`
+	PayerSign            []byte               `protobuf:"bytes,14,opt,name=payerSign,proto3" json:"payerSign,omitempty"`
+	Multisig             *MultisigKeys        `protobuf:"bytes,15,opt,name=multisig,proto3" json:"multisig,omitempty"`
+	Signatures           []*MultisigSignature `protobuf:"bytes,16,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	Ops                  []*BatchOp           `protobuf:"bytes,17,rep,name=ops,proto3" json:"ops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *TxBody) Reset()         { *m = TxBody{} }
@@ -447,6 +466,55 @@ func (m *TxBody) GetSign() []byte {
 	return nil
 }
 
+func (m *TxBody) GetNotBefore() uint64 {
+	if m != nil {
+		return m.NotBefore
+	}
+	return 0
+}
+
+func (m *TxBody) GetValidUntil() uint64 {
+	if m != nil {
+		return m.ValidUntil
+	}
+	return 0
+}
+
+func (m *TxBody) GetFeePayer() []byte {
+	if m != nil {
+		return m.FeePayer
+	}
+	return nil
+}
+
+func (m *TxBody) GetPayerSign() []byte {
+	if m != nil {
+		return m.PayerSign
+	}
+	return nil
+}
+
+func (m *TxBody) GetMultisig() *MultisigKeys {
+	if m != nil {
+		return m.Multisig
+	}
+	return nil
+}
+
+func (m *TxBody) GetSignatures() []*MultisigSignature {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+func (m *TxBody) GetOps() []*BatchOp {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
 // TxIdx specifies a transaction's block hash and index within the block body
 type TxIdx struct {
 	BlockHash            []byte   `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
@@ -541,14 +609,15 @@ func (m *TxInBlock) GetTx() *Tx {
 }
 
 type State struct {
-	Nonce                uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Balance              []byte   `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
-	CodeHash             []byte   `protobuf:"bytes,3,opt,name=codeHash,proto3" json:"codeHash,omitempty"`
-	StorageRoot          []byte   `protobuf:"bytes,4,opt,name=storageRoot,proto3" json:"storageRoot,omitempty"`
-	SqlRecoveryPoint     uint64   `protobuf:"varint,5,opt,name=sqlRecoveryPoint,proto3" json:"sqlRecoveryPoint,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Nonce                uint64        `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Balance              []byte        `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	CodeHash             []byte        `protobuf:"bytes,3,opt,name=codeHash,proto3" json:"codeHash,omitempty"`
+	StorageRoot          []byte        `protobuf:"bytes,4,opt,name=storageRoot,proto3" json:"storageRoot,omitempty"`
+	SqlRecoveryPoint     uint64        `protobuf:"varint,5,opt,name=sqlRecoveryPoint,proto3" json:"sqlRecoveryPoint,omitempty"`
+	Multisig             *MultisigKeys `protobuf:"bytes,6,opt,name=multisig,proto3" json:"multisig,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *State) Reset()         { *m = State{} }
@@ -610,6 +679,13 @@ func (m *State) GetSqlRecoveryPoint() uint64 {
 	return 0
 }
 
+func (m *State) GetMultisig() *MultisigKeys {
+	if m != nil {
+		return m.Multisig
+	}
+	return nil
+}
+
 type AccountProof struct {
 	State                *State   `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
 	Inclusion            bool     `protobuf:"varint,2,opt,name=inclusion,proto3" json:"inclusion,omitempty"`
@@ -845,22 +921,25 @@ func (m *StateQueryProof) GetVarProofs() []*ContractVarProof {
 }
 
 type Receipt struct {
-	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
-	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Ret                  string   `protobuf:"bytes,3,opt,name=ret,proto3" json:"ret,omitempty"`
-	TxHash               []byte   `protobuf:"bytes,4,opt,name=txHash,proto3" json:"txHash,omitempty"`
-	FeeUsed              []byte   `protobuf:"bytes,5,opt,name=feeUsed,proto3" json:"feeUsed,omitempty"`
-	CumulativeFeeUsed    []byte   `protobuf:"bytes,6,opt,name=cumulativeFeeUsed,proto3" json:"cumulativeFeeUsed,omitempty"`
-	Bloom                []byte   `protobuf:"bytes,7,opt,name=bloom,proto3" json:"bloom,omitempty"`
-	Events               []*Event `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
-	BlockNo              uint64   `protobuf:"varint,9,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
-	BlockHash            []byte   `protobuf:"bytes,10,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
-	TxIndex              int32    `protobuf:"varint,11,opt,name=txIndex,proto3" json:"txIndex,omitempty"`
-	From                 []byte   `protobuf:"bytes,12,opt,name=from,proto3" json:"from,omitempty"`
-	To                   []byte   `protobuf:"bytes,13,opt,name=to,proto3" json:"to,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ContractAddress      []byte           `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	Status               string           `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Ret                  string           `protobuf:"bytes,3,opt,name=ret,proto3" json:"ret,omitempty"`
+	TxHash               []byte           `protobuf:"bytes,4,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	FeeUsed              []byte           `protobuf:"bytes,5,opt,name=feeUsed,proto3" json:"feeUsed,omitempty"`
+	CumulativeFeeUsed    []byte           `protobuf:"bytes,6,opt,name=cumulativeFeeUsed,proto3" json:"cumulativeFeeUsed,omitempty"`
+	Bloom                []byte           `protobuf:"bytes,7,opt,name=bloom,proto3" json:"bloom,omitempty"`
+	Events               []*Event         `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
+	BlockNo              uint64           `protobuf:"varint,9,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
+	BlockHash            []byte           `protobuf:"bytes,10,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	TxIndex              int32            `protobuf:"varint,11,opt,name=txIndex,proto3" json:"txIndex,omitempty"`
+	From                 []byte           `protobuf:"bytes,12,opt,name=from,proto3" json:"from,omitempty"`
+	To                   []byte           `protobuf:"bytes,13,opt,name=to,proto3" json:"to,omitempty"`
+	GasUsed              uint64           `protobuf:"varint,14,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
+	FeePayer             []byte           `protobuf:"bytes,15,opt,name=feePayer,proto3" json:"feePayer,omitempty"`
+	OpResults            []*BatchOpResult `protobuf:"bytes,16,rep,name=opResults,proto3" json:"opResults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *Receipt) Reset()         { *m = Receipt{} }
@@ -978,6 +1057,27 @@ func (m *Receipt) GetTo() []byte {
 	return nil
 }
 
+func (m *Receipt) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *Receipt) GetFeePayer() []byte {
+	if m != nil {
+		return m.FeePayer
+	}
+	return nil
+}
+
+func (m *Receipt) GetOpResults() []*BatchOpResult {
+	if m != nil {
+		return m.OpResults
+	}
+	return nil
+}
+
 type Event struct {
 	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
 	EventName            string   `protobuf:"bytes,2,opt,name=eventName,proto3" json:"eventName,omitempty"`
@@ -1074,6 +1174,7 @@ func (m *Event) GetTxIndex() int32 {
 
 type FnArgument struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1110,11 +1211,19 @@ func (m *FnArgument) GetName() string {
 	return ""
 }
 
+func (m *FnArgument) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
 type Function struct {
 	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Arguments            []*FnArgument `protobuf:"bytes,2,rep,name=arguments,proto3" json:"arguments,omitempty"`
 	Payable              bool          `protobuf:"varint,3,opt,name=payable,proto3" json:"payable,omitempty"`
 	View                 bool          `protobuf:"varint,4,opt,name=view,proto3" json:"view,omitempty"`
+	Returns              []string      `protobuf:"bytes,5,rep,name=returns,proto3" json:"returns,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
@@ -1172,6 +1281,13 @@ func (m *Function) GetView() bool {
 	return false
 }
 
+func (m *Function) GetReturns() []string {
+	if m != nil {
+		return m.Returns
+	}
+	return nil
+}
+
 type StateVar struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
@@ -1227,13 +1343,14 @@ func (m *StateVar) GetLen() int32 {
 }
 
 type ABI struct {
-	Version              string      `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	Language             string      `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
-	Functions            []*Function `protobuf:"bytes,3,rep,name=functions,proto3" json:"functions,omitempty"`
-	StateVariables       []*StateVar `protobuf:"bytes,4,rep,name=state_variables,json=stateVariables,proto3" json:"state_variables,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Version              string         `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Language             string         `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Functions            []*Function    `protobuf:"bytes,3,rep,name=functions,proto3" json:"functions,omitempty"`
+	StateVariables       []*StateVar    `protobuf:"bytes,4,rep,name=state_variables,json=stateVariables,proto3" json:"state_variables,omitempty"`
+	Events               []*EventSchema `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *ABI) Reset()         { *m = ABI{} }
@@ -1288,9 +1405,17 @@ func (m *ABI) GetStateVariables() []*StateVar {
 	return nil
 }
 
+func (m *ABI) GetEvents() []*EventSchema {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type Query struct {
 	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
 	Queryinfo            []byte   `protobuf:"bytes,2,opt,name=queryinfo,proto3" json:"queryinfo,omitempty"`
+	Block                []byte   `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1334,11 +1459,19 @@ func (m *Query) GetQueryinfo() []byte {
 	return nil
 }
 
+func (m *Query) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
 type StateQuery struct {
 	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
 	StorageKeys          []string `protobuf:"bytes,2,rep,name=storageKeys,proto3" json:"storageKeys,omitempty"`
 	Root                 []byte   `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
 	Compressed           bool     `protobuf:"varint,4,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	Block                []byte   `protobuf:"bytes,5,opt,name=block,proto3" json:"block,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1396,6 +1529,13 @@ func (m *StateQuery) GetCompressed() bool {
 	return false
 }
 
+func (m *StateQuery) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
 type FilterInfo struct {
 	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
 	EventName            string   `protobuf:"bytes,2,opt,name=eventName,proto3" json:"eventName,omitempty"`
@@ -1404,6 +1544,8 @@ type FilterInfo struct {
 	Desc                 bool     `protobuf:"varint,5,opt,name=desc,proto3" json:"desc,omitempty"`
 	ArgFilter            []byte   `protobuf:"bytes,6,opt,name=argFilter,proto3" json:"argFilter,omitempty"`
 	RecentBlockCnt       int32    `protobuf:"varint,7,opt,name=recentBlockCnt,proto3" json:"recentBlockCnt,omitempty"`
+	Cursor               []byte   `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32    `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1482,6 +1624,547 @@ func (m *FilterInfo) GetRecentBlockCnt() int32 {
 	return 0
 }
 
+func (m *FilterInfo) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
+func (m *FilterInfo) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// ArchiveHeader is the first record of a chain archive file. It describes
+// the chain and the range of the blocks in the archive.
+type ArchiveHeader struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Genesis              []byte   `protobuf:"bytes,2,opt,name=genesis,proto3" json:"genesis,omitempty"`
+	GenesisHash          []byte   `protobuf:"bytes,3,opt,name=genesisHash,proto3" json:"genesisHash,omitempty"`
+	From                 uint64   `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	To                   uint64   `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchiveHeader) Reset()         { *m = ArchiveHeader{} }
+func (m *ArchiveHeader) String() string { return proto.CompactTextString(m) }
+func (*ArchiveHeader) ProtoMessage()    {}
+func (m *ArchiveHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchiveHeader.Unmarshal(m, b)
+}
+func (m *ArchiveHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchiveHeader.Marshal(b, m, deterministic)
+}
+func (m *ArchiveHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveHeader.Merge(m, src)
+}
+func (m *ArchiveHeader) XXX_Size() int {
+	return xxx_messageInfo_ArchiveHeader.Size(m)
+}
+func (m *ArchiveHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchiveHeader proto.InternalMessageInfo
+
+func (m *ArchiveHeader) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ArchiveHeader) GetGenesis() []byte {
+	if m != nil {
+		return m.Genesis
+	}
+	return nil
+}
+
+func (m *ArchiveHeader) GetGenesisHash() []byte {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return nil
+}
+
+func (m *ArchiveHeader) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *ArchiveHeader) GetTo() uint64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+// ArchiveBlock is a block record of a chain archive file.
+type ArchiveBlock struct {
+	Block                *Block   `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Receipts             []byte   `protobuf:"bytes,2,opt,name=receipts,proto3" json:"receipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchiveBlock) Reset()         { *m = ArchiveBlock{} }
+func (m *ArchiveBlock) String() string { return proto.CompactTextString(m) }
+func (*ArchiveBlock) ProtoMessage()    {}
+func (m *ArchiveBlock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchiveBlock.Unmarshal(m, b)
+}
+func (m *ArchiveBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchiveBlock.Marshal(b, m, deterministic)
+}
+func (m *ArchiveBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveBlock.Merge(m, src)
+}
+func (m *ArchiveBlock) XXX_Size() int {
+	return xxx_messageInfo_ArchiveBlock.Size(m)
+}
+func (m *ArchiveBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchiveBlock proto.InternalMessageInfo
+
+func (m *ArchiveBlock) GetBlock() *Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *ArchiveBlock) GetReceipts() []byte {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+// MultisigKeys is the M-of-N public key set of a multisig account
+type MultisigKeys struct {
+	Threshold            uint32   `protobuf:"varint,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	PubKeys              [][]byte `protobuf:"bytes,2,rep,name=pubKeys,proto3" json:"pubKeys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultisigKeys) Reset()         { *m = MultisigKeys{} }
+func (m *MultisigKeys) String() string { return proto.CompactTextString(m) }
+func (*MultisigKeys) ProtoMessage()    {}
+func (m *MultisigKeys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultisigKeys.Unmarshal(m, b)
+}
+func (m *MultisigKeys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultisigKeys.Marshal(b, m, deterministic)
+}
+func (m *MultisigKeys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultisigKeys.Merge(m, src)
+}
+func (m *MultisigKeys) XXX_Size() int {
+	return xxx_messageInfo_MultisigKeys.Size(m)
+}
+func (m *MultisigKeys) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultisigKeys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultisigKeys proto.InternalMessageInfo
+
+func (m *MultisigKeys) GetThreshold() uint32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *MultisigKeys) GetPubKeys() [][]byte {
+	if m != nil {
+		return m.PubKeys
+	}
+	return nil
+}
+
+// MultisigSignature is the signature by the key of PubKeyIndex in the key set
+type MultisigSignature struct {
+	PubKeyIndex          uint32   `protobuf:"varint,1,opt,name=pubKeyIndex,proto3" json:"pubKeyIndex,omitempty"`
+	Sign                 []byte   `protobuf:"bytes,2,opt,name=sign,proto3" json:"sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultisigSignature) Reset()         { *m = MultisigSignature{} }
+func (m *MultisigSignature) String() string { return proto.CompactTextString(m) }
+func (*MultisigSignature) ProtoMessage()    {}
+func (m *MultisigSignature) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultisigSignature.Unmarshal(m, b)
+}
+func (m *MultisigSignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultisigSignature.Marshal(b, m, deterministic)
+}
+func (m *MultisigSignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultisigSignature.Merge(m, src)
+}
+func (m *MultisigSignature) XXX_Size() int {
+	return xxx_messageInfo_MultisigSignature.Size(m)
+}
+func (m *MultisigSignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultisigSignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultisigSignature proto.InternalMessageInfo
+
+func (m *MultisigSignature) GetPubKeyIndex() uint32 {
+	if m != nil {
+		return m.PubKeyIndex
+	}
+	return 0
+}
+
+func (m *MultisigSignature) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+// BatchOp is an operation of a batch tx, which is executed as a tx of the sender
+type BatchOp struct {
+	Type                 TxType   `protobuf:"varint,1,opt,name=type,proto3,enum=types.TxType" json:"type,omitempty"`
+	Recipient            []byte   `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount               []byte   `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Payload              []byte   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchOp) Reset()         { *m = BatchOp{} }
+func (m *BatchOp) String() string { return proto.CompactTextString(m) }
+func (*BatchOp) ProtoMessage()    {}
+func (m *BatchOp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchOp.Unmarshal(m, b)
+}
+func (m *BatchOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchOp.Marshal(b, m, deterministic)
+}
+func (m *BatchOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchOp.Merge(m, src)
+}
+func (m *BatchOp) XXX_Size() int {
+	return xxx_messageInfo_BatchOp.Size(m)
+}
+func (m *BatchOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchOp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchOp proto.InternalMessageInfo
+
+func (m *BatchOp) GetType() TxType {
+	if m != nil {
+		return m.Type
+	}
+	return TxType_NORMAL
+}
+
+func (m *BatchOp) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *BatchOp) GetAmount() []byte {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *BatchOp) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// BatchOpResult is the result of an operation of a batch tx
+type BatchOpResult struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Ret                  string   `protobuf:"bytes,2,opt,name=ret,proto3" json:"ret,omitempty"`
+	GasUsed              uint64   `protobuf:"varint,3,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchOpResult) Reset()         { *m = BatchOpResult{} }
+func (m *BatchOpResult) String() string { return proto.CompactTextString(m) }
+func (*BatchOpResult) ProtoMessage()    {}
+func (m *BatchOpResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchOpResult.Unmarshal(m, b)
+}
+func (m *BatchOpResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchOpResult.Marshal(b, m, deterministic)
+}
+func (m *BatchOpResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchOpResult.Merge(m, src)
+}
+func (m *BatchOpResult) XXX_Size() int {
+	return xxx_messageInfo_BatchOpResult.Size(m)
+}
+func (m *BatchOpResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchOpResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchOpResult proto.InternalMessageInfo
+
+func (m *BatchOpResult) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *BatchOpResult) GetRet() string {
+	if m != nil {
+		return m.Ret
+	}
+	return ""
+}
+
+func (m *BatchOpResult) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+type CodeVersion struct {
+	Version              uint64   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	CodeHash             []byte   `protobuf:"bytes,2,opt,name=codeHash,proto3" json:"codeHash,omitempty"`
+	BlockNo              uint64   `protobuf:"varint,3,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
+	TxHash               []byte   `protobuf:"bytes,4,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	Deployer             []byte   `protobuf:"bytes,5,opt,name=deployer,proto3" json:"deployer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CodeVersion) Reset()         { *m = CodeVersion{} }
+func (m *CodeVersion) String() string { return proto.CompactTextString(m) }
+func (*CodeVersion) ProtoMessage()    {}
+func (m *CodeVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CodeVersion.Unmarshal(m, b)
+}
+func (m *CodeVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CodeVersion.Marshal(b, m, deterministic)
+}
+func (m *CodeVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CodeVersion.Merge(m, src)
+}
+func (m *CodeVersion) XXX_Size() int {
+	return xxx_messageInfo_CodeVersion.Size(m)
+}
+func (m *CodeVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_CodeVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CodeVersion proto.InternalMessageInfo
+
+func (m *CodeVersion) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *CodeVersion) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
+	}
+	return nil
+}
+
+func (m *CodeVersion) GetBlockNo() uint64 {
+	if m != nil {
+		return m.BlockNo
+	}
+	return 0
+}
+
+func (m *CodeVersion) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+func (m *CodeVersion) GetDeployer() []byte {
+	if m != nil {
+		return m.Deployer
+	}
+	return nil
+}
+
+type CodeVersionList struct {
+	Versions             []*CodeVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CodeVersionList) Reset()         { *m = CodeVersionList{} }
+func (m *CodeVersionList) String() string { return proto.CompactTextString(m) }
+func (*CodeVersionList) ProtoMessage()    {}
+func (m *CodeVersionList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CodeVersionList.Unmarshal(m, b)
+}
+func (m *CodeVersionList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CodeVersionList.Marshal(b, m, deterministic)
+}
+func (m *CodeVersionList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CodeVersionList.Merge(m, src)
+}
+func (m *CodeVersionList) XXX_Size() int {
+	return xxx_messageInfo_CodeVersionList.Size(m)
+}
+func (m *CodeVersionList) XXX_DiscardUnknown() {
+	xxx_messageInfo_CodeVersionList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CodeVersionList proto.InternalMessageInfo
+
+func (m *CodeVersionList) GetVersions() []*CodeVersion {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+// EventSchema is the name of an event and the types of its arguments.
+type EventSchema struct {
+	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments            []*FnArgument `protobuf:"bytes,2,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *EventSchema) Reset()         { *m = EventSchema{} }
+func (m *EventSchema) String() string { return proto.CompactTextString(m) }
+func (*EventSchema) ProtoMessage()    {}
+func (m *EventSchema) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventSchema.Unmarshal(m, b)
+}
+func (m *EventSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventSchema.Marshal(b, m, deterministic)
+}
+func (m *EventSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventSchema.Merge(m, src)
+}
+func (m *EventSchema) XXX_Size() int {
+	return xxx_messageInfo_EventSchema.Size(m)
+}
+func (m *EventSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventSchema proto.InternalMessageInfo
+
+func (m *EventSchema) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *EventSchema) GetArguments() []*FnArgument {
+	if m != nil {
+		return m.Arguments
+	}
+	return nil
+}
+
+// ContractSource is the Lua source of a contract verified against its code.
+type ContractSource struct {
+	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	CodeHash             []byte   `protobuf:"bytes,2,opt,name=codeHash,proto3" json:"codeHash,omitempty"`
+	Source               string   `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	CompilerVersion      string   `protobuf:"bytes,4,opt,name=compilerVersion,proto3" json:"compilerVersion,omitempty"`
+	BlockNo              uint64   `protobuf:"varint,5,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractSource) Reset()         { *m = ContractSource{} }
+func (m *ContractSource) String() string { return proto.CompactTextString(m) }
+func (*ContractSource) ProtoMessage()    {}
+func (m *ContractSource) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractSource.Unmarshal(m, b)
+}
+func (m *ContractSource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractSource.Marshal(b, m, deterministic)
+}
+func (m *ContractSource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractSource.Merge(m, src)
+}
+func (m *ContractSource) XXX_Size() int {
+	return xxx_messageInfo_ContractSource.Size(m)
+}
+func (m *ContractSource) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractSource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractSource proto.InternalMessageInfo
+
+func (m *ContractSource) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *ContractSource) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
+	}
+	return nil
+}
+
+func (m *ContractSource) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *ContractSource) GetCompilerVersion() string {
+	if m != nil {
+		return m.CompilerVersion
+	}
+	return ""
+}
+
+func (m *ContractSource) GetBlockNo() uint64 {
+	if m != nil {
+		return m.BlockNo
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Block)(nil), "types.Block")
 	proto.RegisterType((*BlockHeader)(nil), "types.BlockHeader")
@@ -1504,6 +2187,16 @@ func init() {
 	proto.RegisterType((*Query)(nil), "types.Query")
 	proto.RegisterType((*StateQuery)(nil), "types.StateQuery")
 	proto.RegisterType((*FilterInfo)(nil), "types.FilterInfo")
+	proto.RegisterType((*ArchiveHeader)(nil), "types.ArchiveHeader")
+	proto.RegisterType((*ArchiveBlock)(nil), "types.ArchiveBlock")
+	proto.RegisterType((*MultisigKeys)(nil), "types.MultisigKeys")
+	proto.RegisterType((*MultisigSignature)(nil), "types.MultisigSignature")
+	proto.RegisterType((*BatchOp)(nil), "types.BatchOp")
+	proto.RegisterType((*BatchOpResult)(nil), "types.BatchOpResult")
+	proto.RegisterType((*CodeVersion)(nil), "types.CodeVersion")
+	proto.RegisterType((*CodeVersionList)(nil), "types.CodeVersionList")
+	proto.RegisterType((*EventSchema)(nil), "types.EventSchema")
+	proto.RegisterType((*ContractSource)(nil), "types.ContractSource")
 	proto.RegisterEnum("types.TxType", TxType_name, TxType_value)
 }
 
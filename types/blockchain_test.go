@@ -242,3 +242,34 @@ func TestBlockLimit(t *testing.T) {
 	a.True(block.Size() <= txSize*i+hdrSize, "block size violation")
 	a.True(block.Size() <= limit, "block size violation")
 }
+
+func TestTxFilter(t *testing.T) {
+	tx := &Tx{Body: &TxBody{Account: []byte("sender"), Recipient: []byte("contract"), Payload: []byte("{}")}}
+	transfer := &Tx{Body: &TxBody{Account: []byte("sender"), Recipient: []byte("contract")}}
+
+	assert.True(t, (&TxFilter{}).Match(tx))
+	assert.True(t, (&TxFilter{Sender: []byte("sender"), Recipient: []byte("contract")}).Match(tx))
+	assert.False(t, (&TxFilter{Sender: []byte("other")}).Match(tx))
+	assert.True(t, (&TxFilter{Contract: []byte("contract")}).Match(tx))
+	assert.False(t, (&TxFilter{Contract: []byte("contract")}).Match(transfer))
+	assert.True(t, (&TxFilter{Recipient: []byte("contract")}).Match(transfer))
+}
+
+func TestTxValidityWindow(t *testing.T) {
+	tx := &Tx{Body: &TxBody{Nonce: 1, Account: []byte("sender")}}
+	hash := tx.CalculateTxHash()
+
+	// the window is a part of the hash only if it is set
+	tx.Body.ValidUntil = 20
+	assert.NotEqual(t, hash, tx.CalculateTxHash())
+	tx.Body.ValidUntil = 0
+	assert.Equal(t, hash, tx.CalculateTxHash())
+
+	body := &TxBody{NotBefore: 10, ValidUntil: 20}
+	assert.Equal(t, ErrTxNotYetValid, body.ValidateWindow(9))
+	assert.NoError(t, body.ValidateWindow(10))
+	assert.NoError(t, body.ValidateWindow(20))
+	assert.Equal(t, ErrTxExpired, body.ValidateWindow(21))
+	assert.NoError(t, (&TxBody{}).ValidateWindow(0))
+	assert.False(t, (&TxBody{NotBefore: 10}).IsExpired(math.MaxUint64))
+}
@@ -15,6 +15,15 @@ var (
 	//ErrSameNonceInMempool is returned by MemPool Service if transaction which has same nonce is already exists
 	ErrSameNonceAlreadyInMempool = errors.New("tx with same nonce is already in mempool")
 
+	//ErrTxPriceTooLowToReplace is returned by MemPool Service if transaction doesn't pay enough gas price to replace the one with same nonce
+	ErrTxPriceTooLowToReplace = errors.New("gas price is too low to replace tx with same nonce")
+
+	//ErrMempoolFull is returned by MemPool Service if transaction is evicted by the size limit of mempool
+	ErrMempoolFull = errors.New("mempool is full")
+
+	//ErrTxAccountLimitExceeded is returned by MemPool Service if account has too many pending transactions
+	ErrTxAccountLimitExceeded = errors.New("too many pending txs of the account")
+
 	//ErrTxFormatInvalid is returned by MemPool Service if transaction does not exists ErrTxFormatInvalid = errors.New("tx invalid format")
 	ErrTxFormatInvalid = errors.New("tx invalid format")
 
@@ -40,10 +49,33 @@ var (
 
 	ErrTxInvalidPrice = errors.New("tx invalid price")
 
+	ErrTxInvalidGasLimit = errors.New("tx invalid gas limit")
+
+	//ErrTxGasPriceTooLow is returned if the gas price of the metered tx is lower than the minimum
+	ErrTxGasPriceTooLow = errors.New("gas price is too low")
+
+	//ErrTxGasLimitTooLow is returned if the gas limit is lower than the intrinsic gas of tx
+	ErrTxGasLimitTooLow = errors.New("gas limit is too low")
+
 	ErrTxInvalidPayload = errors.New("tx invalid payload")
 
 	ErrTxInvalidSize = errors.New("size of tx exceeds max length")
 
+	//ErrTxInvalidValidityWindow is returned if the tx is valid until a block before the one it becomes valid from
+	ErrTxInvalidValidityWindow = errors.New("tx invalid validity window")
+
+	//ErrTxNotYetValid is returned if the tx is not valid until a later block
+	ErrTxNotYetValid = errors.New("tx is not valid yet")
+
+	//ErrTxExpired is returned if the tx is no longer valid in the block
+	ErrTxExpired = errors.New("tx is expired")
+
+	//ErrTxInvalidFeePayer is returned if the fee payer of the tx is missing, or is given to the tx which is not a fee delegation
+	ErrTxInvalidFeePayer = errors.New("tx invalid fee payer")
+
+	//ErrInsufficientFeePayerBalance is returned if the fee payer can't pay the max fee of the tx
+	ErrInsufficientFeePayerBalance = errors.New("not enough balance of fee payer")
+
 	ErrSignNotMatch = errors.New("signature not matched")
 
 	ErrCouldNotRecoverPubKey = errors.New("could not recover pubkey from sign")
@@ -0,0 +1,156 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/minio/sha256-simd"
+)
+
+// The address of a multisig account is derived from its key set, so that the
+// address is known before the account is used. The key set is stored in the
+// account state by the first multisig tx of the account, which carries the key
+// set. The later txs may omit it.
+//
+//	address = MultisigAddressPrefix + sha256(threshold + public keys)
+const (
+	MultisigAddressPrefix = 0x10
+
+	// MaxMultisigKeys is the maximum number of the keys of a multisig account.
+	MaxMultisigKeys = 16
+
+	pubKeyLength = AddressLength
+)
+
+var (
+	//ErrInvalidMultisigKeys is returned if the key set is not M-of-N of the distinct compressed public keys
+	ErrInvalidMultisigKeys = errors.New("invalid multisig key set")
+
+	//ErrMultisigKeysNotFound is returned if the key set of a multisig account is neither in the tx nor in the state
+	ErrMultisigKeysNotFound = errors.New("multisig key set is not found")
+
+	//ErrTxInvalidMultisig is returned if the signatures of the multisig tx are malformed
+	ErrTxInvalidMultisig = errors.New("tx invalid multisig")
+)
+
+// Validate checks that the key set is M-of-N of the distinct compressed public
+// keys.
+func (k *MultisigKeys) Validate() error {
+	n := len(k.GetPubKeys())
+	if k.GetThreshold() == 0 || int(k.GetThreshold()) > n || n > MaxMultisigKeys {
+		return ErrInvalidMultisigKeys
+	}
+	unique := make(map[string]bool, n)
+	for _, pubKey := range k.GetPubKeys() {
+		if len(pubKey) != pubKeyLength || (pubKey[0] != 0x02 && pubKey[0] != 0x03) {
+			return ErrInvalidMultisigKeys
+		}
+		if unique[string(pubKey)] {
+			return ErrInvalidMultisigKeys
+		}
+		unique[string(pubKey)] = true
+	}
+	return nil
+}
+
+// Address returns the address of the multisig account of the key set.
+func (k *MultisigKeys) Address() []byte {
+	digest := sha256.New()
+	k.write(digest)
+	return append([]byte{MultisigAddressPrefix}, digest.Sum(nil)...)
+}
+
+// IndexOf returns the index of pubKey in the key set, or -1 if it is not.
+func (k *MultisigKeys) IndexOf(pubKey []byte) int {
+	for i, key := range k.GetPubKeys() {
+		if bytes.Equal(key, pubKey) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (k *MultisigKeys) write(w io.Writer) {
+	binary.Write(w, binary.LittleEndian, k.GetThreshold())
+	for _, pubKey := range k.GetPubKeys() {
+		w.Write(pubKey)
+	}
+}
+
+// IsMultisigAddress reports whether addr is the address of a multisig account.
+func IsMultisigAddress(addr []byte) bool {
+	return len(addr) == AddressLength && addr[0] == MultisigAddressPrefix
+}
+
+// AddSignature puts sign by the key of index into the signatures of the
+// multisig tx. The signatures are kept in the order of the key index, and the
+// existing signature of the same key is replaced.
+func (b *TxBody) AddSignature(index uint32, sign []byte) {
+	sig := &MultisigSignature{PubKeyIndex: index, Sign: sign}
+	for i, s := range b.Signatures {
+		if s.GetPubKeyIndex() == index {
+			b.Signatures[i] = sig
+			return
+		}
+		if s.GetPubKeyIndex() > index {
+			b.Signatures = append(b.Signatures[:i], append([]*MultisigSignature{sig}, b.Signatures[i:]...)...)
+			return
+		}
+	}
+	b.Signatures = append(b.Signatures, sig)
+}
+
+// writeMultisig adds the key set and the signatures of the multisig tx to the
+// hash of the tx.
+func (b *TxBody) writeMultisig(w io.Writer) {
+	if b.Multisig != nil {
+		b.Multisig.write(w)
+	}
+	for _, sig := range b.Signatures {
+		binary.Write(w, binary.LittleEndian, sig.GetPubKeyIndex())
+		w.Write(sig.GetSign())
+	}
+}
+
+// validateMultisig checks that only the multisig tx has the signatures, which
+// are in the order of the key index. The key set in the tx must be the one of
+// the sender address.
+func validateMultisig(tx *TxBody) error {
+	if tx.GetType() != TxType_MULTISIG {
+		if tx.GetMultisig() != nil || len(tx.GetSignatures()) != 0 {
+			return ErrTxInvalidMultisig
+		}
+		return nil
+	}
+	if !IsMultisigAddress(tx.GetAccount()) {
+		return ErrTxInvalidAccount
+	}
+	if len(tx.GetSign()) != 0 || len(tx.GetSignatures()) == 0 {
+		return ErrTxInvalidMultisig
+	}
+	for i, sig := range tx.GetSignatures() {
+		if len(sig.GetSign()) == 0 ||
+			(i > 0 && sig.GetPubKeyIndex() <= tx.Signatures[i-1].GetPubKeyIndex()) {
+			return ErrTxInvalidMultisig
+		}
+	}
+	if keys := tx.GetMultisig(); keys != nil {
+		if err := keys.Validate(); err != nil {
+			return err
+		}
+		if !bytes.Equal(keys.Address(), tx.GetAccount()) {
+			return ErrInvalidMultisigKeys
+		}
+		if int(tx.Signatures[len(tx.Signatures)-1].GetPubKeyIndex()) >= len(keys.GetPubKeys()) {
+			return ErrTxInvalidMultisig
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMultisigKeys(threshold uint32, n int) *MultisigKeys {
+	keys := &MultisigKeys{Threshold: threshold}
+	for i := 0; i < n; i++ {
+		keys.PubKeys = append(keys.PubKeys, append([]byte{0x02}, bytes.Repeat([]byte{byte(i + 1)}, 32)...))
+	}
+	return keys
+}
+
+func TestMultisigKeys(t *testing.T) {
+	keys := testMultisigKeys(2, 3)
+	assert.NoError(t, keys.Validate())
+	assert.True(t, IsMultisigAddress(keys.Address()))
+	assert.Equal(t, 1, keys.IndexOf(keys.PubKeys[1]))
+	assert.Equal(t, -1, keys.IndexOf([]byte("other")))
+
+	assert.Equal(t, ErrInvalidMultisigKeys, testMultisigKeys(0, 3).Validate())
+	assert.Equal(t, ErrInvalidMultisigKeys, testMultisigKeys(4, 3).Validate())
+	assert.Equal(t, ErrInvalidMultisigKeys, testMultisigKeys(1, MaxMultisigKeys+1).Validate())
+	duplicated := testMultisigKeys(1, 2)
+	duplicated.PubKeys[1] = duplicated.PubKeys[0]
+	assert.Equal(t, ErrInvalidMultisigKeys, duplicated.Validate())
+
+	// the address depends on the threshold
+	assert.NotEqual(t, keys.Address(), testMultisigKeys(3, 3).Address())
+}
+
+func TestMultisigTransaction(t *testing.T) {
+	keys := testMultisigKeys(2, 3)
+	body := &TxBody{Account: keys.Address(), Recipient: keys.PubKeys[0], Type: TxType_MULTISIG, Multisig: keys}
+	body.AddSignature(2, []byte("sign2"))
+	body.AddSignature(0, []byte("sign0"))
+	body.AddSignature(2, []byte("sign2'"))
+	assert.Equal(t, 2, len(body.Signatures))
+	assert.Equal(t, uint32(0), body.Signatures[0].PubKeyIndex)
+	assert.Equal(t, []byte("sign2'"), body.Signatures[1].Sign)
+	assert.NoError(t, validateMultisig(body))
+
+	body.AddSignature(3, []byte("sign3"))
+	assert.Equal(t, ErrTxInvalidMultisig, validateMultisig(body))
+	body.Signatures = body.Signatures[:2]
+
+	body.Multisig = testMultisigKeys(1, 3)
+	assert.Equal(t, ErrInvalidMultisigKeys, validateMultisig(body))
+
+	// the key set may be omitted after it is stored in the state
+	body.Multisig = nil
+	assert.NoError(t, validateMultisig(body))
+
+	body.Type = TxType_NORMAL
+	assert.Equal(t, ErrTxInvalidMultisig, validateMultisig(body))
+}
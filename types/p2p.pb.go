@@ -1394,6 +1394,196 @@ func (m *GetHashesResponse) GetHasNext() bool {
 	return false
 }
 
+// GetStateRangeRequest asks the leaves of a state trie or a contract storage trie in the key order
+type GetStateRangeRequest struct {
+	Root                 []byte   `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	Start                []byte   `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	Size                 uint32   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateRangeRequest) Reset()         { *m = GetStateRangeRequest{} }
+func (m *GetStateRangeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateRangeRequest) ProtoMessage()    {}
+func (m *GetStateRangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateRangeRequest.Unmarshal(m, b)
+}
+func (m *GetStateRangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateRangeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStateRangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateRangeRequest.Merge(m, src)
+}
+func (m *GetStateRangeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateRangeRequest.Size(m)
+}
+func (m *GetStateRangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateRangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateRangeRequest proto.InternalMessageInfo
+
+func (m *GetStateRangeRequest) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+func (m *GetStateRangeRequest) GetStart() []byte {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *GetStateRangeRequest) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type GetStateRangeResponse struct {
+	Status               ResultStatus `protobuf:"varint,1,opt,name=status,proto3,enum=types.ResultStatus" json:"status,omitempty"`
+	Keys                 [][]byte     `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	Values               [][]byte     `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	HasNext              bool         `protobuf:"varint,4,opt,name=hasNext,proto3" json:"hasNext,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetStateRangeResponse) Reset()         { *m = GetStateRangeResponse{} }
+func (m *GetStateRangeResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStateRangeResponse) ProtoMessage()    {}
+func (m *GetStateRangeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateRangeResponse.Unmarshal(m, b)
+}
+func (m *GetStateRangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateRangeResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStateRangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateRangeResponse.Merge(m, src)
+}
+func (m *GetStateRangeResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStateRangeResponse.Size(m)
+}
+func (m *GetStateRangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateRangeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateRangeResponse proto.InternalMessageInfo
+
+func (m *GetStateRangeResponse) GetStatus() ResultStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ResultStatus_OK
+}
+
+func (m *GetStateRangeResponse) GetKeys() [][]byte {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *GetStateRangeResponse) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *GetStateRangeResponse) GetHasNext() bool {
+	if m != nil {
+		return m.HasNext
+	}
+	return false
+}
+
+// GetContractCodeRequest asks the contract codes by their hashes
+type GetContractCodeRequest struct {
+	Hashes               [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetContractCodeRequest) Reset()         { *m = GetContractCodeRequest{} }
+func (m *GetContractCodeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetContractCodeRequest) ProtoMessage()    {}
+func (m *GetContractCodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetContractCodeRequest.Unmarshal(m, b)
+}
+func (m *GetContractCodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetContractCodeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetContractCodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetContractCodeRequest.Merge(m, src)
+}
+func (m *GetContractCodeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetContractCodeRequest.Size(m)
+}
+func (m *GetContractCodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetContractCodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetContractCodeRequest proto.InternalMessageInfo
+
+func (m *GetContractCodeRequest) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+type GetContractCodeResponse struct {
+	Status               ResultStatus `protobuf:"varint,1,opt,name=status,proto3,enum=types.ResultStatus" json:"status,omitempty"`
+	Codes                [][]byte     `protobuf:"bytes,2,rep,name=codes,proto3" json:"codes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetContractCodeResponse) Reset()         { *m = GetContractCodeResponse{} }
+func (m *GetContractCodeResponse) String() string { return proto.CompactTextString(m) }
+func (*GetContractCodeResponse) ProtoMessage()    {}
+func (m *GetContractCodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetContractCodeResponse.Unmarshal(m, b)
+}
+func (m *GetContractCodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetContractCodeResponse.Marshal(b, m, deterministic)
+}
+func (m *GetContractCodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetContractCodeResponse.Merge(m, src)
+}
+func (m *GetContractCodeResponse) XXX_Size() int {
+	return xxx_messageInfo_GetContractCodeResponse.Size(m)
+}
+func (m *GetContractCodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetContractCodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetContractCodeResponse proto.InternalMessageInfo
+
+func (m *GetContractCodeResponse) GetStatus() ResultStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ResultStatus_OK
+}
+
+func (m *GetContractCodeResponse) GetCodes() [][]byte {
+	if m != nil {
+		return m.Codes
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*MsgHeader)(nil), "types.MsgHeader")
 	proto.RegisterType((*P2PMessage)(nil), "types.P2PMessage")
@@ -1419,6 +1609,10 @@ func init() {
 	proto.RegisterType((*GetHashByNoResponse)(nil), "types.GetHashByNoResponse")
 	proto.RegisterType((*GetHashesRequest)(nil), "types.GetHashesRequest")
 	proto.RegisterType((*GetHashesResponse)(nil), "types.GetHashesResponse")
+	proto.RegisterType((*GetStateRangeRequest)(nil), "types.GetStateRangeRequest")
+	proto.RegisterType((*GetStateRangeResponse)(nil), "types.GetStateRangeResponse")
+	proto.RegisterType((*GetContractCodeRequest)(nil), "types.GetContractCodeRequest")
+	proto.RegisterType((*GetContractCodeResponse)(nil), "types.GetContractCodeResponse")
 	proto.RegisterEnum("types.ResultStatus", ResultStatus_name, ResultStatus_value)
 }
 
@@ -21,6 +21,23 @@ const (
 	successStatus = 0
 	createdStatus = 1
 	errorStatus   = 2
+
+	// feePayerFlag is set to the status byte of the receipt of a fee
+	// delegation tx, which is followed by the fee payer address. The receipts
+	// without the fee payer keep the old format.
+	feePayerFlag = 0x80
+
+	// batchFlag is set to the status byte of the receipt of a batch tx, which
+	// has the results of the operations after the fee payer.
+	batchFlag = 0x40
+
+	// gasUsedFlag is set to the status byte of the receipt of a gas metered
+	// tx, which has the used gas after the cumulative fee. The receipts of
+	// the txs without the gas limit keep the old format, so that the receipts
+	// root of the existing blocks isn't changed.
+	gasUsedFlag = 0x20
+
+	statusFlags = feePayerFlag | batchFlag | gasUsedFlag
 )
 
 func NewReceipt(contractAddress []byte, status string, jsonRet string) *Receipt {
@@ -34,18 +51,21 @@ func NewReceipt(contractAddress []byte, status string, jsonRet string) *Receipt
 func (r *Receipt) marshalBody(b *bytes.Buffer, isMerkle bool) error {
 	l := make([]byte, 8)
 	b.Write(r.ContractAddress)
-	var status byte
-	switch r.Status {
-	case "SUCCESS":
-		status = successStatus
-	case "CREATED":
-		status = createdStatus
-	case "ERROR":
-		status = errorStatus
-	default:
-		return errors.New("unsupported status in receipt")
+	status, err := statusByte(r.Status)
+	if err != nil {
+		return err
 	}
-	b.WriteByte(status)
+	flags := status
+	if len(r.FeePayer) != 0 {
+		flags |= feePayerFlag
+	}
+	if len(r.OpResults) != 0 {
+		flags |= batchFlag
+	}
+	if r.GasUsed != 0 {
+		flags |= gasUsedFlag
+	}
+	b.WriteByte(flags)
 	if !isMerkle || status != errorStatus {
 		binary.LittleEndian.PutUint32(l[:4], uint32(len(r.Ret)))
 		b.Write(l[:4])
@@ -59,6 +79,31 @@ func (r *Receipt) marshalBody(b *bytes.Buffer, isMerkle bool) error {
 	binary.LittleEndian.PutUint32(l[:4], uint32(len(r.CumulativeFeeUsed)))
 	b.Write(l[:4])
 	b.Write(r.CumulativeFeeUsed)
+	if r.GasUsed != 0 {
+		binary.LittleEndian.PutUint64(l, r.GasUsed)
+		b.Write(l)
+	}
+	if len(r.FeePayer) != 0 {
+		b.Write(r.FeePayer)
+	}
+	if len(r.OpResults) != 0 {
+		binary.LittleEndian.PutUint32(l[:4], uint32(len(r.OpResults)))
+		b.Write(l[:4])
+		for _, result := range r.OpResults {
+			opStatus, err := statusByte(result.Status)
+			if err != nil {
+				return err
+			}
+			b.WriteByte(opStatus)
+			if !isMerkle || opStatus != errorStatus {
+				binary.LittleEndian.PutUint32(l[:4], uint32(len(result.Ret)))
+				b.Write(l[:4])
+				b.WriteString(result.Ret)
+			}
+			binary.LittleEndian.PutUint64(l, result.GasUsed)
+			b.Write(l)
+		}
+	}
 	if len(r.Bloom) == 0 {
 		b.WriteByte(0)
 	} else {
@@ -71,6 +116,31 @@ func (r *Receipt) marshalBody(b *bytes.Buffer, isMerkle bool) error {
 	return nil
 }
 
+func statusByte(status string) (byte, error) {
+	switch status {
+	case "SUCCESS":
+		return successStatus, nil
+	case "CREATED":
+		return createdStatus, nil
+	case "ERROR":
+		return errorStatus, nil
+	default:
+		return 0, errors.New("unsupported status in receipt")
+	}
+}
+
+func statusString(status byte) string {
+	switch status {
+	case successStatus:
+		return "SUCCESS"
+	case createdStatus:
+		return "CREATED"
+	case errorStatus:
+		return "ERROR"
+	}
+	return ""
+}
+
 func (r *Receipt) marshalStoreBinary() ([]byte, error) {
 	var b bytes.Buffer
 
@@ -92,14 +162,10 @@ func (r *Receipt) marshalStoreBinary() ([]byte, error) {
 func (r *Receipt) unmarshalBody(data []byte) ([]byte, uint32) {
 	r.ContractAddress = data[:33]
 	status := data[33]
-	switch status {
-	case successStatus:
-		r.Status = "SUCCESS"
-	case createdStatus:
-		r.Status = "CREATED"
-	case errorStatus:
-		r.Status = "ERROR"
-	}
+	hasFeePayer := status&feePayerFlag != 0
+	hasOpResults := status&batchFlag != 0
+	hasGasUsed := status&gasUsedFlag != 0
+	r.Status = statusString(status &^ statusFlags)
 	pos := uint32(34)
 	l := binary.LittleEndian.Uint32(data[pos:])
 	pos += 4
@@ -115,6 +181,30 @@ func (r *Receipt) unmarshalBody(data []byte) ([]byte, uint32) {
 	pos += 4
 	r.CumulativeFeeUsed = data[pos : pos+l]
 	pos += l
+	if hasGasUsed {
+		r.GasUsed = binary.LittleEndian.Uint64(data[pos:])
+		pos += 8
+	}
+	if hasFeePayer {
+		r.FeePayer = data[pos : pos+AddressLength]
+		pos += AddressLength
+	}
+	if hasOpResults {
+		n := binary.LittleEndian.Uint32(data[pos:])
+		pos += 4
+		r.OpResults = make([]*BatchOpResult, n)
+		for i := range r.OpResults {
+			result := &BatchOpResult{Status: statusString(data[pos])}
+			pos += 1
+			retLen := binary.LittleEndian.Uint32(data[pos:])
+			pos += 4
+			result.Ret = string(data[pos : pos+retLen])
+			pos += retLen
+			result.GasUsed = binary.LittleEndian.Uint64(data[pos:])
+			pos += 8
+			r.OpResults[i] = result
+		}
+	}
 	bloomCheck := data[pos]
 	pos += 1
 	if bloomCheck == 1 {
@@ -233,8 +323,37 @@ func (r *Receipt) MarshalJSON() ([]byte, error) {
 	b.WriteString(EncodeAddress(r.From))
 	b.WriteString(`","to":"`)
 	b.WriteString(EncodeAddress(r.To))
+	if len(r.FeePayer) != 0 {
+		b.WriteString(`","feePayer":"`)
+		b.WriteString(EncodeAddress(r.FeePayer))
+	}
 	b.WriteString(`","usedFee":`)
 	b.WriteString(new(big.Int).SetBytes(r.FeeUsed).String())
+	b.WriteString(`,"gasUsed":`)
+	b.WriteString(strconv.FormatUint(r.GasUsed, 10))
+	if len(r.OpResults) != 0 {
+		b.WriteString(`,"opResults":[`)
+		for i, result := range r.OpResults {
+			if i != 0 {
+				b.WriteString(`,`)
+			}
+			b.WriteString(`{"status":"`)
+			b.WriteString(result.Status)
+			b.WriteString(`","ret":`)
+			if len(result.Ret) == 0 {
+				b.WriteString(`{}`)
+			} else if result.Status == "ERROR" {
+				js, _ := json.Marshal(result.Ret)
+				b.Write(js)
+			} else {
+				b.WriteString(result.Ret)
+			}
+			b.WriteString(`,"gasUsed":`)
+			b.WriteString(strconv.FormatUint(result.GasUsed, 10))
+			b.WriteString(`}`)
+		}
+		b.WriteString(`]`)
+	}
 	b.WriteString(`,"events":[`)
 	for i, ev := range r.Events {
 		if i != 0 {
@@ -637,14 +756,19 @@ func AddressOrigin(addr []byte) []byte {
 	return addr
 }
 
+// ArgNo returns the position of the argument to be matched.
+func (af *ArgFilter) ArgNo() int {
+	return af.argNo
+}
+
+// Value returns the value of the argument to be matched.
+func (af *ArgFilter) Value() interface{} {
+	return af.value
+}
+
 func (fi *FilterInfo) ValidateCheck(to uint64) error {
-	if fi.ContractAddress == nil {
-		return errors.New("invalid contractAddress:" + string(fi.ContractAddress))
-	}
-	if len(fi.ContractAddress) < AddressLength {
-		fi.ContractAddress = AddressPadding(fi.ContractAddress)
-	} else if len(fi.ContractAddress) != AddressLength {
-		return errors.New("invalid contractAddress:" + string(fi.ContractAddress))
+	if err := fi.ValidateAddress(); err != nil {
+		return err
 	}
 	if fi.RecentBlockCnt > 0 {
 		if fi.RecentBlockCnt > MAXBLOCKRANGE {
@@ -661,6 +785,20 @@ func (fi *FilterInfo) ValidateCheck(to uint64) error {
 	return nil
 }
 
+// ValidateAddress checks the contract address of the filter. The address is
+// padded if it is a name.
+func (fi *FilterInfo) ValidateAddress() error {
+	if fi.ContractAddress == nil {
+		return errors.New("invalid contractAddress:" + string(fi.ContractAddress))
+	}
+	if len(fi.ContractAddress) < AddressLength {
+		fi.ContractAddress = AddressPadding(fi.ContractAddress)
+	} else if len(fi.ContractAddress) != AddressLength {
+		return errors.New("invalid contractAddress:" + string(fi.ContractAddress))
+	}
+	return nil
+}
+
 func (fi *FilterInfo) GetExArgFilter() ([]ArgFilter, error) {
 	if len(fi.ArgFilter) == 0 {
 		return nil, nil
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/aergoio/aergo/internal/merkle"
+	"github.com/minio/sha256-simd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptFeePayer(t *testing.T) {
+	contract := make([]byte, AddressLength)
+	payer := make([]byte, AddressLength)
+	payer[0] = 0x02
+	txHash := make([]byte, 32)
+
+	for _, feePayer := range [][]byte{nil, payer} {
+		r := NewReceipt(contract, "ERROR", "error")
+		r.TxHash = txHash
+		r.FeeUsed = []byte{1}
+		r.GasUsed = 100
+		r.FeePayer = feePayer
+
+		data, err := r.MarshalBinary()
+		assert.NoError(t, err)
+		var decoded Receipt
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, "ERROR", decoded.Status)
+		assert.Equal(t, "error", decoded.Ret)
+		assert.Equal(t, uint64(100), decoded.GasUsed)
+		assert.Equal(t, feePayer, decoded.FeePayer)
+	}
+}
+
+func TestReceiptOpResults(t *testing.T) {
+	contract := make([]byte, AddressLength)
+	payer := make([]byte, AddressLength)
+	payer[0] = 0x02
+	txHash := make([]byte, 32)
+
+	r := NewReceipt(contract, "ERROR", "batch operation 1: error")
+	r.TxHash = txHash
+	r.FeePayer = payer
+	r.OpResults = []*BatchOpResult{
+		{Status: "SUCCESS", Ret: `"ok"`, GasUsed: 10},
+		{Status: "ERROR", Ret: "error", GasUsed: 20},
+	}
+
+	data, err := r.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded Receipt
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, "ERROR", decoded.Status)
+	assert.Equal(t, payer, decoded.FeePayer)
+	assert.Equal(t, r.OpResults, decoded.OpResults)
+
+	// the error messages of the operations are out of the merkle hash
+	merkle, err := r.MarshalMerkleBinary()
+	assert.NoError(t, err)
+	r.OpResults[1].Ret = "other error"
+	changed, err := r.MarshalMerkleBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, merkle, changed)
+
+	js, err := r.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(js), `"opResults":[{"status":"SUCCESS","ret":"ok","gasUsed":10},{"status":"ERROR","ret":"other error","gasUsed":20}]`)
+}
+
+// baselineReceipt hashes the merkle binary of the receipt in the format before
+// the gas metering.
+type baselineReceipt struct {
+	r *Receipt
+}
+
+func (br baselineReceipt) GetHash() []byte {
+	var b bytes.Buffer
+	l := make([]byte, 4)
+	b.Write(br.r.ContractAddress)
+	status, _ := statusByte(br.r.Status)
+	b.WriteByte(status)
+	if status != errorStatus {
+		binary.LittleEndian.PutUint32(l, uint32(len(br.r.Ret)))
+		b.Write(l)
+		b.WriteString(br.r.Ret)
+	}
+	b.Write(br.r.TxHash)
+	binary.LittleEndian.PutUint32(l, uint32(len(br.r.FeeUsed)))
+	b.Write(l)
+	b.Write(br.r.FeeUsed)
+	binary.LittleEndian.PutUint32(l, uint32(len(br.r.CumulativeFeeUsed)))
+	b.Write(l)
+	b.Write(br.r.CumulativeFeeUsed)
+	b.WriteByte(0)
+	binary.LittleEndian.PutUint32(l, 0)
+	b.Write(l)
+	h := sha256.Sum256(b.Bytes())
+	return h[:]
+}
+
+func TestReceiptBaselineRoot(t *testing.T) {
+	contract := make([]byte, AddressLength)
+	contract[0] = 0x02
+	var receipts []*Receipt
+	var entries []merkle.MerkleEntry
+	for i, status := range []string{"SUCCESS", "CREATED", "ERROR"} {
+		r := NewReceipt(contract, status, `"result"`)
+		r.TxHash = make([]byte, 32)
+		r.TxHash[0] = byte(i)
+		r.FeeUsed = []byte{byte(i + 1)}
+		r.CumulativeFeeUsed = []byte{byte(i + 1)}
+		receipts = append(receipts, r)
+		entries = append(entries, baselineReceipt{r})
+	}
+	var rs Receipts
+	rs.Set(receipts)
+	assert.Equal(t, merkle.CalculateMerkleRoot(entries), rs.MerkleRoot(), "receipts root of the baseline")
+
+	data, err := receipts[0].MarshalBinary()
+	assert.NoError(t, err)
+	var decoded Receipt
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, "SUCCESS", decoded.Status)
+	assert.Equal(t, uint64(0), decoded.GasUsed)
+
+	// the receipt of a gas metered tx has the used gas in the hash
+	receipts[0].GasUsed = 100
+	assert.NotEqual(t, entries[0].GetHash(), receipts[0].GetHash())
+}
@@ -590,6 +590,7 @@ type AccountAndRoot struct {
 	Account              []byte   `protobuf:"bytes,1,opt,name=Account,proto3" json:"Account,omitempty"`
 	Root                 []byte   `protobuf:"bytes,2,opt,name=Root,proto3" json:"Root,omitempty"`
 	Compressed           bool     `protobuf:"varint,3,opt,name=Compressed,proto3" json:"Compressed,omitempty"`
+	Block                []byte   `protobuf:"bytes,4,opt,name=Block,proto3" json:"Block,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -640,6 +641,13 @@ func (m *AccountAndRoot) GetCompressed() bool {
 	return false
 }
 
+func (m *AccountAndRoot) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
 type Peer struct {
 	Address              *PeerAddress    `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	Bestblock            *NewBlockNotice `protobuf:"bytes,2,opt,name=bestblock,proto3" json:"bestblock,omitempty"`
@@ -1948,6 +1956,7 @@ func (m *ConfigItem) GetProps() map[string]string {
 
 type EventList struct {
 	Events               []*Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextCursor           []byte   `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1984,6 +1993,13 @@ func (m *EventList) GetEvents() []*Event {
 	return nil
 }
 
+func (m *EventList) GetNextCursor() []byte {
+	if m != nil {
+		return m.NextCursor
+	}
+	return nil
+}
+
 // info and bps is json string
 type ConsensusInfo struct {
 	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
@@ -2039,251 +2055,873 @@ func (m *ConsensusInfo) GetBps() []string {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*BlockchainStatus)(nil), "types.BlockchainStatus")
-	proto.RegisterType((*ChainId)(nil), "types.ChainId")
-	proto.RegisterType((*ChainInfo)(nil), "types.ChainInfo")
-	proto.RegisterType((*ChainStats)(nil), "types.ChainStats")
-	proto.RegisterType((*Input)(nil), "types.Input")
-	proto.RegisterType((*Output)(nil), "types.Output")
-	proto.RegisterType((*Empty)(nil), "types.Empty")
-	proto.RegisterType((*SingleBytes)(nil), "types.SingleBytes")
-	proto.RegisterType((*AccountAddress)(nil), "types.AccountAddress")
-	proto.RegisterType((*AccountAndRoot)(nil), "types.AccountAndRoot")
-	proto.RegisterType((*Peer)(nil), "types.Peer")
-	proto.RegisterType((*PeerList)(nil), "types.PeerList")
-	proto.RegisterType((*ListParams)(nil), "types.ListParams")
-	proto.RegisterType((*PageParams)(nil), "types.PageParams")
-	proto.RegisterType((*BlockBodyPaged)(nil), "types.BlockBodyPaged")
-	proto.RegisterType((*BlockBodyParams)(nil), "types.BlockBodyParams")
-	proto.RegisterType((*BlockHeaderList)(nil), "types.BlockHeaderList")
-	proto.RegisterType((*BlockMetadata)(nil), "types.BlockMetadata")
-	proto.RegisterType((*BlockMetadataList)(nil), "types.BlockMetadataList")
-	proto.RegisterType((*CommitResult)(nil), "types.CommitResult")
-	proto.RegisterType((*CommitResultList)(nil), "types.CommitResultList")
-	proto.RegisterType((*VerifyResult)(nil), "types.VerifyResult")
-	proto.RegisterType((*Personal)(nil), "types.Personal")
-	proto.RegisterType((*ImportFormat)(nil), "types.ImportFormat")
-	proto.RegisterType((*Staking)(nil), "types.Staking")
-	proto.RegisterType((*Vote)(nil), "types.Vote")
-	proto.RegisterType((*VoteParams)(nil), "types.VoteParams")
-	proto.RegisterType((*AccountVoteInfo)(nil), "types.AccountVoteInfo")
-	proto.RegisterType((*VoteInfo)(nil), "types.VoteInfo")
-	proto.RegisterType((*VoteList)(nil), "types.VoteList")
-	proto.RegisterType((*NodeReq)(nil), "types.NodeReq")
-	proto.RegisterType((*Name)(nil), "types.Name")
-	proto.RegisterType((*NameInfo)(nil), "types.NameInfo")
-	proto.RegisterType((*PeersParams)(nil), "types.PeersParams")
-	proto.RegisterType((*KeyParams)(nil), "types.KeyParams")
-	proto.RegisterType((*ServerInfo)(nil), "types.ServerInfo")
-	proto.RegisterMapType((map[string]*ConfigItem)(nil), "types.ServerInfo.ConfigEntry")
-	proto.RegisterMapType((map[string]string)(nil), "types.ServerInfo.StatusEntry")
-	proto.RegisterType((*ConfigItem)(nil), "types.ConfigItem")
-	proto.RegisterMapType((map[string]string)(nil), "types.ConfigItem.PropsEntry")
-	proto.RegisterType((*EventList)(nil), "types.EventList")
-	proto.RegisterType((*ConsensusInfo)(nil), "types.ConsensusInfo")
-	proto.RegisterEnum("types.CommitStatus", CommitStatus_name, CommitStatus_value)
-	proto.RegisterEnum("types.VerifyStatus", VerifyStatus_name, VerifyStatus_value)
+type SimulateResult struct {
+	Receipt              *Receipt `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// AergoRPCServiceClient is the client API for AergoRPCService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AergoRPCServiceClient interface {
-	// Returns the current state of this node
-	NodeState(ctx context.Context, in *NodeReq, opts ...grpc.CallOption) (*SingleBytes, error)
-	// Returns node metrics according to request
-	Metric(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*Metrics, error)
-	// Returns current blockchain status (best block's height and hash)
-	Blockchain(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockchainStatus, error)
-	// Returns current blockchain's basic information
-	GetChainInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainInfo, error)
-	// Returns current chain statistics
-	ChainStat(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainStats, error)
-	// Returns list of Blocks without body according to request
-	ListBlockHeaders(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockHeaderList, error)
-	// Returns list of block metadata (hash, header, and number of transactions) according to request
-	ListBlockMetadata(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockMetadataList, error)
-	// Returns a stream of new blocks as they get added to the blockchain
-	ListBlockStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockStreamClient, error)
-	// Returns a stream of new block's metadata as they get added to the blockchain
-	ListBlockMetadataStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockMetadataStreamClient, error)
-	// Return a single block incl. header and body, queried by hash or number
-	GetBlock(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Block, error)
-	// Return a single block's metdata (hash, header, and number of transactions), queried by hash or number
-	GetBlockMetadata(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*BlockMetadata, error)
-	// Return a single block's body, queried by hash or number and list parameters
-	GetBlockBody(ctx context.Context, in *BlockBodyParams, opts ...grpc.CallOption) (*BlockBodyPaged, error)
-	// Return a single transaction, queried by transaction hash
-	GetTX(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Tx, error)
-	// Return information about transaction in block, queried by transaction hash
-	GetBlockTX(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*TxInBlock, error)
-	// Return transaction receipt, queried by transaction hash
-	GetReceipt(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Receipt, error)
-	// Return ABI stored at contract address
-	GetABI(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*ABI, error)
-	// Sign and send a transaction from an unlocked account
-	SendTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*CommitResult, error)
-	// Sign transaction with unlocked account
-	SignTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*Tx, error)
-	// Verify validity of transaction
-	VerifyTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*VerifyResult, error)
-	// Commit a signed transaction
-	CommitTX(ctx context.Context, in *TxList, opts ...grpc.CallOption) (*CommitResultList, error)
-	// Return state of account
-	GetState(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*State, error)
-	// Return state of account, including merkle proof
-	GetStateAndProof(ctx context.Context, in *AccountAndRoot, opts ...grpc.CallOption) (*AccountProof, error)
-	// Create a new account in this node
-	CreateAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*Account, error)
-	// Return list of accounts in this node
-	GetAccounts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AccountList, error)
-	// Lock account in this node
-	LockAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*Account, error)
-	// Unlock account in this node
-	UnlockAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*Account, error)
-	// Import account to this node
-	ImportAccount(ctx context.Context, in *ImportFormat, opts ...grpc.CallOption) (*Account, error)
-	// Export account stored in this node
-	ExportAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*SingleBytes, error)
-	// Query a contract method
-	QueryContract(ctx context.Context, in *Query, opts ...grpc.CallOption) (*SingleBytes, error)
-	// Query contract state
-	QueryContractState(ctx context.Context, in *StateQuery, opts ...grpc.CallOption) (*StateQueryProof, error)
-	// Return list of peers of this node and their state
-	GetPeers(ctx context.Context, in *PeersParams, opts ...grpc.CallOption) (*PeerList, error)
-	// Return result of vote
-	GetVotes(ctx context.Context, in *VoteParams, opts ...grpc.CallOption) (*VoteList, error)
-	// Return staking, voting info for account
-	GetAccountVotes(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*AccountVoteInfo, error)
-	// Return staking information
-	GetStaking(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*Staking, error)
-	// Return name information
-	GetNameInfo(ctx context.Context, in *Name, opts ...grpc.CallOption) (*NameInfo, error)
-	// Returns a stream of event as they get added to the blockchain
-	ListEventStream(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (AergoRPCService_ListEventStreamClient, error)
-	// Returns list of event
-	ListEvents(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (*EventList, error)
-	// Returns configs and statuses of server
-	GetServerInfo(ctx context.Context, in *KeyParams, opts ...grpc.CallOption) (*ServerInfo, error)
-	// Returns status of consensus and bps
-	GetConsensusInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConsensusInfo, error)
-	// Add & remove member of raft cluster
-	ChangeMembership(ctx context.Context, in *MembershipChange, opts ...grpc.CallOption) (*MembershipChangeReply, error)
+func (m *SimulateResult) Reset()         { *m = SimulateResult{} }
+func (m *SimulateResult) String() string { return proto.CompactTextString(m) }
+func (*SimulateResult) ProtoMessage()    {}
+func (m *SimulateResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SimulateResult.Unmarshal(m, b)
 }
-
-type aergoRPCServiceClient struct {
-	cc *grpc.ClientConn
+func (m *SimulateResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SimulateResult.Marshal(b, m, deterministic)
 }
-
-func NewAergoRPCServiceClient(cc *grpc.ClientConn) AergoRPCServiceClient {
-	return &aergoRPCServiceClient{cc}
+func (m *SimulateResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SimulateResult.Merge(m, src)
+}
+func (m *SimulateResult) XXX_Size() int {
+	return xxx_messageInfo_SimulateResult.Size(m)
+}
+func (m *SimulateResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SimulateResult.DiscardUnknown(m)
 }
 
-func (c *aergoRPCServiceClient) NodeState(ctx context.Context, in *NodeReq, opts ...grpc.CallOption) (*SingleBytes, error) {
-	out := new(SingleBytes)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/NodeState", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SimulateResult proto.InternalMessageInfo
+
+func (m *SimulateResult) GetReceipt() *Receipt {
+	if m != nil {
+		return m.Receipt
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aergoRPCServiceClient) Metric(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*Metrics, error) {
-	out := new(Metrics)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/Metric", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SimulateResult) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aergoRPCServiceClient) Blockchain(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockchainStatus, error) {
-	out := new(BlockchainStatus)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/Blockchain", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type AccountTxsRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Cursor               []byte   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                uint32   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Desc                 bool     `protobuf:"varint,4,opt,name=desc,proto3" json:"desc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *aergoRPCServiceClient) GetChainInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainInfo, error) {
-	out := new(ChainInfo)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetChainInfo", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *AccountTxsRequest) Reset()         { *m = AccountTxsRequest{} }
+func (m *AccountTxsRequest) String() string { return proto.CompactTextString(m) }
+func (*AccountTxsRequest) ProtoMessage()    {}
+func (m *AccountTxsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountTxsRequest.Unmarshal(m, b)
+}
+func (m *AccountTxsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountTxsRequest.Marshal(b, m, deterministic)
+}
+func (m *AccountTxsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountTxsRequest.Merge(m, src)
+}
+func (m *AccountTxsRequest) XXX_Size() int {
+	return xxx_messageInfo_AccountTxsRequest.Size(m)
+}
+func (m *AccountTxsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountTxsRequest.DiscardUnknown(m)
 }
 
-func (c *aergoRPCServiceClient) ChainStat(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainStats, error) {
-	out := new(ChainStats)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ChainStat", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_AccountTxsRequest proto.InternalMessageInfo
+
+func (m *AccountTxsRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aergoRPCServiceClient) ListBlockHeaders(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockHeaderList, error) {
-	out := new(BlockHeaderList)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListBlockHeaders", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AccountTxsRequest) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aergoRPCServiceClient) ListBlockMetadata(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockMetadataList, error) {
-	out := new(BlockMetadataList)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListBlockMetadata", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AccountTxsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
 	}
-	return out, nil
+	return 0
 }
 
-func (c *aergoRPCServiceClient) ListBlockStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[0], "/types.AergoRPCService/ListBlockStream", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &aergoRPCServiceListBlockStreamClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *AccountTxsRequest) GetDesc() bool {
+	if m != nil {
+		return m.Desc
 	}
-	return x, nil
+	return false
 }
 
-type AergoRPCService_ListBlockStreamClient interface {
-	Recv() (*Block, error)
-	grpc.ClientStream
+type AccountTxList struct {
+	Txs                  []*TxInBlock `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	NextCursor           []byte       `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-type aergoRPCServiceListBlockStreamClient struct {
-	grpc.ClientStream
+func (m *AccountTxList) Reset()         { *m = AccountTxList{} }
+func (m *AccountTxList) String() string { return proto.CompactTextString(m) }
+func (*AccountTxList) ProtoMessage()    {}
+func (m *AccountTxList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountTxList.Unmarshal(m, b)
 }
-
-func (x *aergoRPCServiceListBlockStreamClient) Recv() (*Block, error) {
-	m := new(Block)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (m *AccountTxList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountTxList.Marshal(b, m, deterministic)
+}
+func (m *AccountTxList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountTxList.Merge(m, src)
+}
+func (m *AccountTxList) XXX_Size() int {
+	return xxx_messageInfo_AccountTxList.Size(m)
+}
+func (m *AccountTxList) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountTxList.DiscardUnknown(m)
 }
 
-func (c *aergoRPCServiceClient) ListBlockMetadataStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockMetadataStreamClient, error) {
+var xxx_messageInfo_AccountTxList proto.InternalMessageInfo
+
+func (m *AccountTxList) GetTxs() []*TxInBlock {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+func (m *AccountTxList) GetNextCursor() []byte {
+	if m != nil {
+		return m.NextCursor
+	}
+	return nil
+}
+
+type TxFilter struct {
+	Sender               []byte   `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Recipient            []byte   `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Contract             []byte   `protobuf:"bytes,3,opt,name=contract,proto3" json:"contract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxFilter) Reset()         { *m = TxFilter{} }
+func (m *TxFilter) String() string { return proto.CompactTextString(m) }
+func (*TxFilter) ProtoMessage()    {}
+func (m *TxFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxFilter.Unmarshal(m, b)
+}
+func (m *TxFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxFilter.Marshal(b, m, deterministic)
+}
+func (m *TxFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxFilter.Merge(m, src)
+}
+func (m *TxFilter) XXX_Size() int {
+	return xxx_messageInfo_TxFilter.Size(m)
+}
+func (m *TxFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxFilter proto.InternalMessageInfo
+
+func (m *TxFilter) GetSender() []byte {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *TxFilter) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *TxFilter) GetContract() []byte {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+type MempoolTx struct {
+	Tx                   *Tx      `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	Ready                bool     `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolTx) Reset()         { *m = MempoolTx{} }
+func (m *MempoolTx) String() string { return proto.CompactTextString(m) }
+func (*MempoolTx) ProtoMessage()    {}
+func (m *MempoolTx) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTx.Unmarshal(m, b)
+}
+func (m *MempoolTx) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTx.Marshal(b, m, deterministic)
+}
+func (m *MempoolTx) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTx.Merge(m, src)
+}
+func (m *MempoolTx) XXX_Size() int {
+	return xxx_messageInfo_MempoolTx.Size(m)
+}
+func (m *MempoolTx) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTx.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTx proto.InternalMessageInfo
+
+func (m *MempoolTx) GetTx() *Tx {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *MempoolTx) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *MempoolTx) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type MempoolTxsRequest struct {
+	Cursor               []byte   `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                uint32   `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolTxsRequest) Reset()         { *m = MempoolTxsRequest{} }
+func (m *MempoolTxsRequest) String() string { return proto.CompactTextString(m) }
+func (*MempoolTxsRequest) ProtoMessage()    {}
+func (m *MempoolTxsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTxsRequest.Unmarshal(m, b)
+}
+func (m *MempoolTxsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTxsRequest.Marshal(b, m, deterministic)
+}
+func (m *MempoolTxsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTxsRequest.Merge(m, src)
+}
+func (m *MempoolTxsRequest) XXX_Size() int {
+	return xxx_messageInfo_MempoolTxsRequest.Size(m)
+}
+func (m *MempoolTxsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTxsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTxsRequest proto.InternalMessageInfo
+
+func (m *MempoolTxsRequest) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
+func (m *MempoolTxsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type MempoolTxList struct {
+	Txs                  []*MempoolTx `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	NextCursor           []byte       `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *MempoolTxList) Reset()         { *m = MempoolTxList{} }
+func (m *MempoolTxList) String() string { return proto.CompactTextString(m) }
+func (*MempoolTxList) ProtoMessage()    {}
+func (m *MempoolTxList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTxList.Unmarshal(m, b)
+}
+func (m *MempoolTxList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTxList.Marshal(b, m, deterministic)
+}
+func (m *MempoolTxList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTxList.Merge(m, src)
+}
+func (m *MempoolTxList) XXX_Size() int {
+	return xxx_messageInfo_MempoolTxList.Size(m)
+}
+func (m *MempoolTxList) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTxList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTxList proto.InternalMessageInfo
+
+func (m *MempoolTxList) GetTxs() []*MempoolTx {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+func (m *MempoolTxList) GetNextCursor() []byte {
+	if m != nil {
+		return m.NextCursor
+	}
+	return nil
+}
+
+type NonceGap struct {
+	From                 uint64   `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   uint64   `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NonceGap) Reset()         { *m = NonceGap{} }
+func (m *NonceGap) String() string { return proto.CompactTextString(m) }
+func (*NonceGap) ProtoMessage()    {}
+func (m *NonceGap) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NonceGap.Unmarshal(m, b)
+}
+func (m *NonceGap) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NonceGap.Marshal(b, m, deterministic)
+}
+func (m *NonceGap) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NonceGap.Merge(m, src)
+}
+func (m *NonceGap) XXX_Size() int {
+	return xxx_messageInfo_NonceGap.Size(m)
+}
+func (m *NonceGap) XXX_DiscardUnknown() {
+	xxx_messageInfo_NonceGap.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NonceGap proto.InternalMessageInfo
+
+func (m *NonceGap) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *NonceGap) GetTo() uint64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type MempoolAccount struct {
+	Account              []byte       `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Nonce                uint64       `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Balance              []byte       `protobuf:"bytes,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Ready                uint32       `protobuf:"varint,4,opt,name=ready,proto3" json:"ready,omitempty"`
+	Orphan               uint32       `protobuf:"varint,5,opt,name=orphan,proto3" json:"orphan,omitempty"`
+	Gaps                 []*NonceGap  `protobuf:"bytes,6,rep,name=gaps,proto3" json:"gaps,omitempty"`
+	Txs                  []*MempoolTx `protobuf:"bytes,7,rep,name=txs,proto3" json:"txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *MempoolAccount) Reset()         { *m = MempoolAccount{} }
+func (m *MempoolAccount) String() string { return proto.CompactTextString(m) }
+func (*MempoolAccount) ProtoMessage()    {}
+func (m *MempoolAccount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolAccount.Unmarshal(m, b)
+}
+func (m *MempoolAccount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolAccount.Marshal(b, m, deterministic)
+}
+func (m *MempoolAccount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolAccount.Merge(m, src)
+}
+func (m *MempoolAccount) XXX_Size() int {
+	return xxx_messageInfo_MempoolAccount.Size(m)
+}
+func (m *MempoolAccount) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolAccount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolAccount proto.InternalMessageInfo
+
+func (m *MempoolAccount) GetAccount() []byte {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func (m *MempoolAccount) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *MempoolAccount) GetBalance() []byte {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *MempoolAccount) GetReady() uint32 {
+	if m != nil {
+		return m.Ready
+	}
+	return 0
+}
+
+func (m *MempoolAccount) GetOrphan() uint32 {
+	if m != nil {
+		return m.Orphan
+	}
+	return 0
+}
+
+func (m *MempoolAccount) GetGaps() []*NonceGap {
+	if m != nil {
+		return m.Gaps
+	}
+	return nil
+}
+
+func (m *MempoolAccount) GetTxs() []*MempoolTx {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+type MempoolStats struct {
+	Total                uint64   `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Orphan               uint64   `protobuf:"varint,2,opt,name=orphan,proto3" json:"orphan,omitempty"`
+	Accounts             uint64   `protobuf:"varint,3,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	Bytes                uint64   `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Evicted              uint64   `protobuf:"varint,5,opt,name=evicted,proto3" json:"evicted,omitempty"`
+	Rejected             uint64   `protobuf:"varint,6,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolStats) Reset()         { *m = MempoolStats{} }
+func (m *MempoolStats) String() string { return proto.CompactTextString(m) }
+func (*MempoolStats) ProtoMessage()    {}
+func (m *MempoolStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolStats.Unmarshal(m, b)
+}
+func (m *MempoolStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolStats.Marshal(b, m, deterministic)
+}
+func (m *MempoolStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolStats.Merge(m, src)
+}
+func (m *MempoolStats) XXX_Size() int {
+	return xxx_messageInfo_MempoolStats.Size(m)
+}
+func (m *MempoolStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolStats proto.InternalMessageInfo
+
+func (m *MempoolStats) GetTotal() uint64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *MempoolStats) GetOrphan() uint64 {
+	if m != nil {
+		return m.Orphan
+	}
+	return 0
+}
+
+func (m *MempoolStats) GetAccounts() uint64 {
+	if m != nil {
+		return m.Accounts
+	}
+	return 0
+}
+
+func (m *MempoolStats) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *MempoolStats) GetEvicted() uint64 {
+	if m != nil {
+		return m.Evicted
+	}
+	return 0
+}
+
+func (m *MempoolStats) GetRejected() uint64 {
+	if m != nil {
+		return m.Rejected
+	}
+	return 0
+}
+
+type VerifyContractRequest struct {
+	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	Source               string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	CompilerVersion      string   `protobuf:"bytes,3,opt,name=compilerVersion,proto3" json:"compilerVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyContractRequest) Reset()         { *m = VerifyContractRequest{} }
+func (m *VerifyContractRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyContractRequest) ProtoMessage()    {}
+func (m *VerifyContractRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyContractRequest.Unmarshal(m, b)
+}
+func (m *VerifyContractRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyContractRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyContractRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyContractRequest.Merge(m, src)
+}
+func (m *VerifyContractRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyContractRequest.Size(m)
+}
+func (m *VerifyContractRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyContractRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyContractRequest proto.InternalMessageInfo
+
+func (m *VerifyContractRequest) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *VerifyContractRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *VerifyContractRequest) GetCompilerVersion() string {
+	if m != nil {
+		return m.CompilerVersion
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*BlockchainStatus)(nil), "types.BlockchainStatus")
+	proto.RegisterType((*ChainId)(nil), "types.ChainId")
+	proto.RegisterType((*ChainInfo)(nil), "types.ChainInfo")
+	proto.RegisterType((*ChainStats)(nil), "types.ChainStats")
+	proto.RegisterType((*Input)(nil), "types.Input")
+	proto.RegisterType((*Output)(nil), "types.Output")
+	proto.RegisterType((*Empty)(nil), "types.Empty")
+	proto.RegisterType((*SingleBytes)(nil), "types.SingleBytes")
+	proto.RegisterType((*AccountAddress)(nil), "types.AccountAddress")
+	proto.RegisterType((*AccountAndRoot)(nil), "types.AccountAndRoot")
+	proto.RegisterType((*Peer)(nil), "types.Peer")
+	proto.RegisterType((*PeerList)(nil), "types.PeerList")
+	proto.RegisterType((*ListParams)(nil), "types.ListParams")
+	proto.RegisterType((*PageParams)(nil), "types.PageParams")
+	proto.RegisterType((*BlockBodyPaged)(nil), "types.BlockBodyPaged")
+	proto.RegisterType((*BlockBodyParams)(nil), "types.BlockBodyParams")
+	proto.RegisterType((*BlockHeaderList)(nil), "types.BlockHeaderList")
+	proto.RegisterType((*BlockMetadata)(nil), "types.BlockMetadata")
+	proto.RegisterType((*BlockMetadataList)(nil), "types.BlockMetadataList")
+	proto.RegisterType((*CommitResult)(nil), "types.CommitResult")
+	proto.RegisterType((*CommitResultList)(nil), "types.CommitResultList")
+	proto.RegisterType((*VerifyResult)(nil), "types.VerifyResult")
+	proto.RegisterType((*Personal)(nil), "types.Personal")
+	proto.RegisterType((*ImportFormat)(nil), "types.ImportFormat")
+	proto.RegisterType((*Staking)(nil), "types.Staking")
+	proto.RegisterType((*Vote)(nil), "types.Vote")
+	proto.RegisterType((*VoteParams)(nil), "types.VoteParams")
+	proto.RegisterType((*AccountVoteInfo)(nil), "types.AccountVoteInfo")
+	proto.RegisterType((*VoteInfo)(nil), "types.VoteInfo")
+	proto.RegisterType((*VoteList)(nil), "types.VoteList")
+	proto.RegisterType((*NodeReq)(nil), "types.NodeReq")
+	proto.RegisterType((*Name)(nil), "types.Name")
+	proto.RegisterType((*NameInfo)(nil), "types.NameInfo")
+	proto.RegisterType((*PeersParams)(nil), "types.PeersParams")
+	proto.RegisterType((*KeyParams)(nil), "types.KeyParams")
+	proto.RegisterType((*ServerInfo)(nil), "types.ServerInfo")
+	proto.RegisterMapType((map[string]*ConfigItem)(nil), "types.ServerInfo.ConfigEntry")
+	proto.RegisterMapType((map[string]string)(nil), "types.ServerInfo.StatusEntry")
+	proto.RegisterType((*ConfigItem)(nil), "types.ConfigItem")
+	proto.RegisterMapType((map[string]string)(nil), "types.ConfigItem.PropsEntry")
+	proto.RegisterType((*EventList)(nil), "types.EventList")
+	proto.RegisterType((*ConsensusInfo)(nil), "types.ConsensusInfo")
+	proto.RegisterType((*SimulateResult)(nil), "types.SimulateResult")
+	proto.RegisterType((*AccountTxsRequest)(nil), "types.AccountTxsRequest")
+	proto.RegisterType((*AccountTxList)(nil), "types.AccountTxList")
+	proto.RegisterType((*TxFilter)(nil), "types.TxFilter")
+	proto.RegisterType((*MempoolTx)(nil), "types.MempoolTx")
+	proto.RegisterType((*MempoolTxsRequest)(nil), "types.MempoolTxsRequest")
+	proto.RegisterType((*MempoolTxList)(nil), "types.MempoolTxList")
+	proto.RegisterType((*NonceGap)(nil), "types.NonceGap")
+	proto.RegisterType((*MempoolAccount)(nil), "types.MempoolAccount")
+	proto.RegisterType((*MempoolStats)(nil), "types.MempoolStats")
+	proto.RegisterType((*VerifyContractRequest)(nil), "types.VerifyContractRequest")
+	proto.RegisterEnum("types.CommitStatus", CommitStatus_name, CommitStatus_value)
+	proto.RegisterEnum("types.VerifyStatus", VerifyStatus_name, VerifyStatus_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AergoRPCServiceClient is the client API for AergoRPCService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AergoRPCServiceClient interface {
+	// Returns the current state of this node
+	NodeState(ctx context.Context, in *NodeReq, opts ...grpc.CallOption) (*SingleBytes, error)
+	// Returns node metrics according to request
+	Metric(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*Metrics, error)
+	// Returns current blockchain status (best block's height and hash)
+	Blockchain(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockchainStatus, error)
+	// Returns current blockchain's basic information
+	GetChainInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainInfo, error)
+	// Returns current chain statistics
+	ChainStat(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainStats, error)
+	// Returns list of Blocks without body according to request
+	ListBlockHeaders(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockHeaderList, error)
+	// Returns list of block metadata (hash, header, and number of transactions) according to request
+	ListBlockMetadata(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockMetadataList, error)
+	// Returns a stream of new blocks as they get added to the blockchain
+	ListBlockStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockStreamClient, error)
+	// Returns a stream of new block's metadata as they get added to the blockchain
+	ListBlockMetadataStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockMetadataStreamClient, error)
+	// Return a single block incl. header and body, queried by hash or number
+	GetBlock(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Block, error)
+	// Return a single block's metdata (hash, header, and number of transactions), queried by hash or number
+	GetBlockMetadata(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*BlockMetadata, error)
+	// Return a single block's body, queried by hash or number and list parameters
+	GetBlockBody(ctx context.Context, in *BlockBodyParams, opts ...grpc.CallOption) (*BlockBodyPaged, error)
+	// Return a single transaction, queried by transaction hash
+	GetTX(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Tx, error)
+	// Return information about transaction in block, queried by transaction hash
+	GetBlockTX(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*TxInBlock, error)
+	// Return transaction receipt, queried by transaction hash
+	GetReceipt(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Receipt, error)
+	// Return ABI stored at contract address
+	GetABI(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*ABI, error)
+	// Sign and send a transaction from an unlocked account
+	SendTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*CommitResult, error)
+	// Sign transaction with unlocked account
+	SignTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*Tx, error)
+	// Verify validity of transaction
+	VerifyTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*VerifyResult, error)
+	// Commit a signed transaction
+	CommitTX(ctx context.Context, in *TxList, opts ...grpc.CallOption) (*CommitResultList, error)
+	// Return state of account
+	GetState(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*State, error)
+	// Return state of account, including merkle proof
+	GetStateAndProof(ctx context.Context, in *AccountAndRoot, opts ...grpc.CallOption) (*AccountProof, error)
+	// Create a new account in this node
+	CreateAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*Account, error)
+	// Return list of accounts in this node
+	GetAccounts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AccountList, error)
+	// Lock account in this node
+	LockAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*Account, error)
+	// Unlock account in this node
+	UnlockAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*Account, error)
+	// Import account to this node
+	ImportAccount(ctx context.Context, in *ImportFormat, opts ...grpc.CallOption) (*Account, error)
+	// Export account stored in this node
+	ExportAccount(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*SingleBytes, error)
+	// Query a contract method
+	QueryContract(ctx context.Context, in *Query, opts ...grpc.CallOption) (*SingleBytes, error)
+	// Query contract state
+	QueryContractState(ctx context.Context, in *StateQuery, opts ...grpc.CallOption) (*StateQueryProof, error)
+	// Return list of peers of this node and their state
+	GetPeers(ctx context.Context, in *PeersParams, opts ...grpc.CallOption) (*PeerList, error)
+	// Return result of vote
+	GetVotes(ctx context.Context, in *VoteParams, opts ...grpc.CallOption) (*VoteList, error)
+	// Return staking, voting info for account
+	GetAccountVotes(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*AccountVoteInfo, error)
+	// Return staking information
+	GetStaking(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*Staking, error)
+	// Return name information
+	GetNameInfo(ctx context.Context, in *Name, opts ...grpc.CallOption) (*NameInfo, error)
+	// Returns a stream of event as they get added to the blockchain
+	ListEventStream(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (AergoRPCService_ListEventStreamClient, error)
+	// Returns list of event
+	ListEvents(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (*EventList, error)
+	// Returns configs and statuses of server
+	GetServerInfo(ctx context.Context, in *KeyParams, opts ...grpc.CallOption) (*ServerInfo, error)
+	// Returns status of consensus and bps
+	GetConsensusInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConsensusInfo, error)
+	// Add & remove member of raft cluster
+	ChangeMembership(ctx context.Context, in *MembershipChange, opts ...grpc.CallOption) (*MembershipChangeReply, error)
+	// Execute a transaction against the best state without committing it
+	SimulateTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*SimulateResult, error)
+	// Return state of account at the given block
+	GetStateAt(ctx context.Context, in *AccountAndRoot, opts ...grpc.CallOption) (*State, error)
+	// Returns the txs sent by or sent to an account from the account tx index
+	ListAccountTxs(ctx context.Context, in *AccountTxsRequest, opts ...grpc.CallOption) (*AccountTxList, error)
+	// Returns the stream of the txs accepted into the mempool
+	ListPendingTxStream(ctx context.Context, in *TxFilter, opts ...grpc.CallOption) (AergoRPCService_ListPendingTxStreamClient, error)
+	// Returns the pending txs in the mempool page by page
+	ListMempoolTxs(ctx context.Context, in *MempoolTxsRequest, opts ...grpc.CallOption) (*MempoolTxList, error)
+	// Returns the pending txs of an account in the mempool with their nonce gaps
+	GetMempoolAccount(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*MempoolAccount, error)
+	// Returns the statistics of the mempool
+	GetMempoolStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MempoolStats, error)
+	// Export account stored in this node as keystore json
+	ExportAccountKeystore(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*SingleBytes, error)
+	// Create a new HD wallet and its first account in the node
+	CreateAccountMnemonic(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*HDAccounts, error)
+	// Recover the accounts of a HD wallet from the mnemonic in the node
+	RecoverAccounts(ctx context.Context, in *HDAccountRequest, opts ...grpc.CallOption) (*HDAccounts, error)
+	// Return the code versions of the contract, of which the last one is the current code
+	GetCodeVersions(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*CodeVersionList, error)
+	// Verify the source of a contract by compiling it and comparing with the deployed code
+	VerifyContract(ctx context.Context, in *VerifyContractRequest, opts ...grpc.CallOption) (*ContractSource, error)
+	// Return the verified source of a contract
+	GetContractSource(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*ContractSource, error)
+}
+
+type aergoRPCServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAergoRPCServiceClient(cc *grpc.ClientConn) AergoRPCServiceClient {
+	return &aergoRPCServiceClient{cc}
+}
+
+func (c *aergoRPCServiceClient) NodeState(ctx context.Context, in *NodeReq, opts ...grpc.CallOption) (*SingleBytes, error) {
+	out := new(SingleBytes)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/NodeState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) Metric(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*Metrics, error) {
+	out := new(Metrics)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/Metric", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) Blockchain(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockchainStatus, error) {
+	out := new(BlockchainStatus)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/Blockchain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetChainInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainInfo, error) {
+	out := new(ChainInfo)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetChainInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ChainStat(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ChainStats, error) {
+	out := new(ChainStats)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ChainStat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListBlockHeaders(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockHeaderList, error) {
+	out := new(BlockHeaderList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListBlockHeaders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListBlockMetadata(ctx context.Context, in *ListParams, opts ...grpc.CallOption) (*BlockMetadataList, error) {
+	out := new(BlockMetadataList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListBlockMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListBlockStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[0], "/types.AergoRPCService/ListBlockStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aergoRPCServiceListBlockStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AergoRPCService_ListBlockStreamClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type aergoRPCServiceListBlockStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aergoRPCServiceListBlockStreamClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aergoRPCServiceClient) ListBlockMetadataStream(ctx context.Context, in *Empty, opts ...grpc.CallOption) (AergoRPCService_ListBlockMetadataStreamClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[1], "/types.AergoRPCService/ListBlockMetadataStream", opts...)
 	if err != nil {
 		return nil, err
@@ -2519,98 +3157,238 @@ func (c *aergoRPCServiceClient) GetVotes(ctx context.Context, in *VoteParams, op
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetAccountVotes(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*AccountVoteInfo, error) {
+	out := new(AccountVoteInfo)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetAccountVotes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetStaking(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*Staking, error) {
+	out := new(Staking)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetStaking", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetNameInfo(ctx context.Context, in *Name, opts ...grpc.CallOption) (*NameInfo, error) {
+	out := new(NameInfo)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetNameInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListEventStream(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (AergoRPCService_ListEventStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[2], "/types.AergoRPCService/ListEventStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aergoRPCServiceListEventStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AergoRPCService_ListEventStreamClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type aergoRPCServiceListEventStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aergoRPCServiceListEventStreamClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aergoRPCServiceClient) ListEvents(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (*EventList, error) {
+	out := new(EventList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetServerInfo(ctx context.Context, in *KeyParams, opts ...grpc.CallOption) (*ServerInfo, error) {
+	out := new(ServerInfo)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetServerInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetConsensusInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConsensusInfo, error) {
+	out := new(ConsensusInfo)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetConsensusInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ChangeMembership(ctx context.Context, in *MembershipChange, opts ...grpc.CallOption) (*MembershipChangeReply, error) {
+	out := new(MembershipChangeReply)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ChangeMembership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) SimulateTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*SimulateResult, error) {
+	out := new(SimulateResult)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/SimulateTX", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetStateAt(ctx context.Context, in *AccountAndRoot, opts ...grpc.CallOption) (*State, error) {
+	out := new(State)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetStateAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListAccountTxs(ctx context.Context, in *AccountTxsRequest, opts ...grpc.CallOption) (*AccountTxList, error) {
+	out := new(AccountTxList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListAccountTxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListPendingTxStream(ctx context.Context, in *TxFilter, opts ...grpc.CallOption) (AergoRPCService_ListPendingTxStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[3], "/types.AergoRPCService/ListPendingTxStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aergoRPCServiceListPendingTxStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AergoRPCService_ListPendingTxStreamClient interface {
+	Recv() (*Tx, error)
+	grpc.ClientStream
+}
+
+type aergoRPCServiceListPendingTxStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aergoRPCServiceListPendingTxStreamClient) Recv() (*Tx, error) {
+	m := new(Tx)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func (c *aergoRPCServiceClient) GetAccountVotes(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*AccountVoteInfo, error) {
-	out := new(AccountVoteInfo)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetAccountVotes", in, out, opts...)
+func (c *aergoRPCServiceClient) ListMempoolTxs(ctx context.Context, in *MempoolTxsRequest, opts ...grpc.CallOption) (*MempoolTxList, error) {
+	out := new(MempoolTxList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListMempoolTxs", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aergoRPCServiceClient) GetStaking(ctx context.Context, in *AccountAddress, opts ...grpc.CallOption) (*Staking, error) {
-	out := new(Staking)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetStaking", in, out, opts...)
+func (c *aergoRPCServiceClient) GetMempoolAccount(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*MempoolAccount, error) {
+	out := new(MempoolAccount)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetMempoolAccount", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aergoRPCServiceClient) GetNameInfo(ctx context.Context, in *Name, opts ...grpc.CallOption) (*NameInfo, error) {
-	out := new(NameInfo)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetNameInfo", in, out, opts...)
+func (c *aergoRPCServiceClient) GetMempoolStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MempoolStats, error) {
+	out := new(MempoolStats)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetMempoolStats", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aergoRPCServiceClient) ListEventStream(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (AergoRPCService_ListEventStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[2], "/types.AergoRPCService/ListEventStream", opts...)
+func (c *aergoRPCServiceClient) ExportAccountKeystore(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*SingleBytes, error) {
+	out := new(SingleBytes)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ExportAccountKeystore", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &aergoRPCServiceListEventStreamClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type AergoRPCService_ListEventStreamClient interface {
-	Recv() (*Event, error)
-	grpc.ClientStream
-}
-
-type aergoRPCServiceListEventStreamClient struct {
-	grpc.ClientStream
+	return out, nil
 }
 
-func (x *aergoRPCServiceListEventStreamClient) Recv() (*Event, error) {
-	m := new(Event)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func (c *aergoRPCServiceClient) CreateAccountMnemonic(ctx context.Context, in *Personal, opts ...grpc.CallOption) (*HDAccounts, error) {
+	out := new(HDAccounts)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/CreateAccountMnemonic", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-func (c *aergoRPCServiceClient) ListEvents(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (*EventList, error) {
-	out := new(EventList)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListEvents", in, out, opts...)
+func (c *aergoRPCServiceClient) RecoverAccounts(ctx context.Context, in *HDAccountRequest, opts ...grpc.CallOption) (*HDAccounts, error) {
+	out := new(HDAccounts)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/RecoverAccounts", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aergoRPCServiceClient) GetServerInfo(ctx context.Context, in *KeyParams, opts ...grpc.CallOption) (*ServerInfo, error) {
-	out := new(ServerInfo)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetServerInfo", in, out, opts...)
+func (c *aergoRPCServiceClient) GetCodeVersions(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*CodeVersionList, error) {
+	out := new(CodeVersionList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetCodeVersions", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aergoRPCServiceClient) GetConsensusInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConsensusInfo, error) {
-	out := new(ConsensusInfo)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetConsensusInfo", in, out, opts...)
+func (c *aergoRPCServiceClient) VerifyContract(ctx context.Context, in *VerifyContractRequest, opts ...grpc.CallOption) (*ContractSource, error) {
+	out := new(ContractSource)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/VerifyContract", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aergoRPCServiceClient) ChangeMembership(ctx context.Context, in *MembershipChange, opts ...grpc.CallOption) (*MembershipChangeReply, error) {
-	out := new(MembershipChangeReply)
-	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ChangeMembership", in, out, opts...)
+func (c *aergoRPCServiceClient) GetContractSource(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*ContractSource, error) {
+	out := new(ContractSource)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetContractSource", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -2699,6 +3477,32 @@ type AergoRPCServiceServer interface {
 	GetConsensusInfo(context.Context, *Empty) (*ConsensusInfo, error)
 	// Add & remove member of raft cluster
 	ChangeMembership(context.Context, *MembershipChange) (*MembershipChangeReply, error)
+	// Execute a transaction against the best state without committing it
+	SimulateTX(context.Context, *Tx) (*SimulateResult, error)
+	// Return state of account at the given block
+	GetStateAt(context.Context, *AccountAndRoot) (*State, error)
+	// Returns the txs sent by or sent to an account from the account tx index
+	ListAccountTxs(context.Context, *AccountTxsRequest) (*AccountTxList, error)
+	// Returns the stream of the txs accepted into the mempool
+	ListPendingTxStream(*TxFilter, AergoRPCService_ListPendingTxStreamServer) error
+	// Returns the pending txs in the mempool page by page
+	ListMempoolTxs(context.Context, *MempoolTxsRequest) (*MempoolTxList, error)
+	// Returns the pending txs of an account in the mempool with their nonce gaps
+	GetMempoolAccount(context.Context, *SingleBytes) (*MempoolAccount, error)
+	// Returns the statistics of the mempool
+	GetMempoolStats(context.Context, *Empty) (*MempoolStats, error)
+	// Export account stored in this node as keystore json
+	ExportAccountKeystore(context.Context, *Personal) (*SingleBytes, error)
+	// Create a new HD wallet and its first account in the node
+	CreateAccountMnemonic(context.Context, *Personal) (*HDAccounts, error)
+	// Recover the accounts of a HD wallet from the mnemonic in the node
+	RecoverAccounts(context.Context, *HDAccountRequest) (*HDAccounts, error)
+	// Return the code versions of the contract, of which the last one is the current code
+	GetCodeVersions(context.Context, *SingleBytes) (*CodeVersionList, error)
+	// Verify the source of a contract by compiling it and comparing with the deployed code
+	VerifyContract(context.Context, *VerifyContractRequest) (*ContractSource, error)
+	// Return the verified source of a contract
+	GetContractSource(context.Context, *SingleBytes) (*ContractSource, error)
 }
 
 func RegisterAergoRPCServiceServer(s *grpc.Server, srv AergoRPCServiceServer) {
@@ -3434,6 +4238,243 @@ func _AergoRPCService_ChangeMembership_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AergoRPCService_SimulateTX_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Tx)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).SimulateTX(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/SimulateTX",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).SimulateTX(ctx, req.(*Tx))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetStateAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountAndRoot)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetStateAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetStateAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetStateAt(ctx, req.(*AccountAndRoot))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_ListAccountTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountTxsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).ListAccountTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/ListAccountTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).ListAccountTxs(ctx, req.(*AccountTxsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_ListPendingTxStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TxFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AergoRPCServiceServer).ListPendingTxStream(m, &aergoRPCServiceListPendingTxStreamServer{stream})
+}
+
+type AergoRPCService_ListPendingTxStreamServer interface {
+	Send(*Tx) error
+	grpc.ServerStream
+}
+
+type aergoRPCServiceListPendingTxStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aergoRPCServiceListPendingTxStreamServer) Send(m *Tx) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AergoRPCService_ListMempoolTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MempoolTxsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).ListMempoolTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/ListMempoolTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).ListMempoolTxs(ctx, req.(*MempoolTxsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetMempoolAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SingleBytes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetMempoolAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetMempoolAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetMempoolAccount(ctx, req.(*SingleBytes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetMempoolStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetMempoolStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetMempoolStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetMempoolStats(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_ExportAccountKeystore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Personal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).ExportAccountKeystore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/ExportAccountKeystore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).ExportAccountKeystore(ctx, req.(*Personal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_CreateAccountMnemonic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Personal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).CreateAccountMnemonic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/CreateAccountMnemonic",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).CreateAccountMnemonic(ctx, req.(*Personal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_RecoverAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HDAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).RecoverAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/RecoverAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).RecoverAccounts(ctx, req.(*HDAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetCodeVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SingleBytes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetCodeVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetCodeVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetCodeVersions(ctx, req.(*SingleBytes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_VerifyContract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyContractRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).VerifyContract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/VerifyContract",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).VerifyContract(ctx, req.(*VerifyContractRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetContractSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SingleBytes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetContractSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetContractSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetContractSource(ctx, req.(*SingleBytes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AergoRPCService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "types.AergoRPCService",
 	HandlerType: (*AergoRPCServiceServer)(nil),
@@ -3586,6 +4627,54 @@ var _AergoRPCService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ChangeMembership",
 			Handler:    _AergoRPCService_ChangeMembership_Handler,
 		},
+		{
+			MethodName: "SimulateTX",
+			Handler:    _AergoRPCService_SimulateTX_Handler,
+		},
+		{
+			MethodName: "GetStateAt",
+			Handler:    _AergoRPCService_GetStateAt_Handler,
+		},
+		{
+			MethodName: "ListAccountTxs",
+			Handler:    _AergoRPCService_ListAccountTxs_Handler,
+		},
+		{
+			MethodName: "ListMempoolTxs",
+			Handler:    _AergoRPCService_ListMempoolTxs_Handler,
+		},
+		{
+			MethodName: "GetMempoolAccount",
+			Handler:    _AergoRPCService_GetMempoolAccount_Handler,
+		},
+		{
+			MethodName: "GetMempoolStats",
+			Handler:    _AergoRPCService_GetMempoolStats_Handler,
+		},
+		{
+			MethodName: "ExportAccountKeystore",
+			Handler:    _AergoRPCService_ExportAccountKeystore_Handler,
+		},
+		{
+			MethodName: "CreateAccountMnemonic",
+			Handler:    _AergoRPCService_CreateAccountMnemonic_Handler,
+		},
+		{
+			MethodName: "RecoverAccounts",
+			Handler:    _AergoRPCService_RecoverAccounts_Handler,
+		},
+		{
+			MethodName: "GetCodeVersions",
+			Handler:    _AergoRPCService_GetCodeVersions_Handler,
+		},
+		{
+			MethodName: "VerifyContract",
+			Handler:    _AergoRPCService_VerifyContract_Handler,
+		},
+		{
+			MethodName: "GetContractSource",
+			Handler:    _AergoRPCService_GetContractSource_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3603,6 +4692,11 @@ var _AergoRPCService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _AergoRPCService_ListEventStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListPendingTxStream",
+			Handler:       _AergoRPCService_ListPendingTxStream_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc.proto",
 }
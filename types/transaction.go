@@ -30,6 +30,7 @@ type Transaction interface {
 	CalculateTxHash() []byte
 	Validate([]byte) error
 	ValidateWithSenderState(senderState *State) error
+	ValidateWithFeePayerState(payerState *State) error
 	HasVerifedAccount() bool
 	GetVerifedAccount() Address
 	SetVerifedAccount(account Address) bool
@@ -78,6 +79,9 @@ func (tx *transaction) Validate(chainidhash []byte) error {
 	if proto.Size(tx.GetTx()) > TxMaxSize {
 		return ErrTxInvalidSize
 	}
+	if body := tx.GetBody(); body.GetValidUntil() != 0 && body.GetValidUntil() < body.GetNotBefore() {
+		return ErrTxInvalidValidityWindow
+	}
 
 	account := tx.GetBody().GetAccount()
 	if account == nil {
@@ -97,6 +101,17 @@ func (tx *transaction) Validate(chainidhash []byte) error {
 		return ErrTxInvalidPrice
 	}
 
+	gasLimit := tx.GetBody().GetGasLimit()
+	if gasLimit > fee.MaxGasLimit {
+		return ErrTxInvalidGasLimit
+	}
+	if fee.IsGasMetered(gasLimit) && gasLimit < fee.TxGas(len(tx.GetBody().GetPayload())) {
+		return ErrTxGasLimitTooLow
+	}
+	if fee.IsGasMetered(gasLimit) && gasprice.Cmp(fee.MinGasPrice()) < 0 {
+		return ErrTxGasPriceTooLow
+	}
+
 	if len(tx.GetBody().GetAccount()) > AddressLength {
 		return ErrTxInvalidAccount
 	}
@@ -105,30 +120,78 @@ func (tx *transaction) Validate(chainidhash []byte) error {
 		return ErrTxInvalidRecipient
 	}
 
+	if err := validateFeePayer(tx.GetBody()); err != nil {
+		return err
+	}
+	if err := validateMultisig(tx.GetBody()); err != nil {
+		return err
+	}
+	if err := validateBatch(tx.GetBody()); err != nil {
+		return err
+	}
+
 	switch tx.GetBody().Type {
-	case TxType_NORMAL:
+	case TxType_NORMAL, TxType_FEE_DELEGATION, TxType_MULTISIG:
 		if tx.GetBody().GetRecipient() == nil && len(tx.GetBody().GetPayload()) == 0 {
 			//contract deploy
 			return ErrTxInvalidRecipient
 		}
 	case TxType_GOVERNANCE:
-		if len(tx.GetBody().GetPayload()) <= 0 {
-			return ErrTxFormatInvalid
-		}
-		switch string(tx.GetBody().GetRecipient()) {
-		case AergoSystem:
-			return ValidateSystemTx(tx.GetBody())
-		case AergoName:
-			return validateNameTx(tx.GetBody())
-		default:
-			return ErrTxInvalidRecipient
-		}
+		return validateGovernanceTx(tx.GetBody())
+	case TxType_REDEPLOY:
+		return validateRedeployTx(tx.GetBody())
+	case TxType_BATCH:
 	default:
 		return ErrTxInvalidType
 	}
 	return nil
 }
 
+// validateRedeployTx checks that the redeploy tx sends the new code to the
+// contract without any amount.
+func validateRedeployTx(tx *TxBody) error {
+	if len(tx.GetRecipient()) == 0 {
+		return ErrTxInvalidRecipient
+	}
+	if len(tx.GetPayload()) == 0 {
+		return ErrTxInvalidPayload
+	}
+	if tx.GetAmountBigInt().Sign() != 0 {
+		return ErrTxInvalidAmount
+	}
+	return nil
+}
+
+func validateGovernanceTx(tx *TxBody) error {
+	if len(tx.GetPayload()) <= 0 {
+		return ErrTxFormatInvalid
+	}
+	switch string(tx.GetRecipient()) {
+	case AergoSystem:
+		return ValidateSystemTx(tx)
+	case AergoName:
+		return validateNameTx(tx)
+	default:
+		return ErrTxInvalidRecipient
+	}
+}
+
+// validateFeePayer checks that only the fee delegation tx has the fee payer,
+// which is an address other than the sender and co-signs the tx.
+func validateFeePayer(tx *TxBody) error {
+	if tx.GetType() != TxType_FEE_DELEGATION {
+		if len(tx.GetFeePayer()) != 0 || len(tx.GetPayerSign()) != 0 {
+			return ErrTxInvalidFeePayer
+		}
+		return nil
+	}
+	if len(tx.GetFeePayer()) != AddressLength || len(tx.GetPayerSign()) == 0 ||
+		bytes.Equal(tx.GetFeePayer(), tx.GetAccount()) {
+		return ErrTxInvalidFeePayer
+	}
+	return nil
+}
+
 func ValidateSystemTx(tx *TxBody) error {
 	var ci CallInfo
 	if err := json.Unmarshal(tx.Payload, &ci); err != nil {
@@ -258,11 +321,20 @@ func (tx *transaction) ValidateWithSenderState(senderState *State) error {
 	amount := tx.GetBody().GetAmountBigInt()
 	balance := senderState.GetBalanceBigInt()
 	switch tx.GetBody().GetType() {
-	case TxType_NORMAL:
+	case TxType_NORMAL, TxType_MULTISIG, TxType_REDEPLOY:
 		spending := new(big.Int).Add(amount, tx.GetMaxFee())
 		if spending.Cmp(balance) > 0 {
 			return ErrInsufficientBalance
 		}
+	case TxType_FEE_DELEGATION:
+		if amount.Cmp(balance) > 0 {
+			return ErrInsufficientBalance
+		}
+	case TxType_BATCH:
+		spending := new(big.Int).Add(tx.GetBody().opsAmount(), tx.GetMaxFee())
+		if spending.Cmp(balance) > 0 {
+			return ErrInsufficientBalance
+		}
 	case TxType_GOVERNANCE:
 		switch string(tx.GetBody().GetRecipient()) {
 		case AergoSystem:
@@ -285,6 +357,15 @@ func (tx *transaction) ValidateWithSenderState(senderState *State) error {
 	return nil
 }
 
+// ValidateWithFeePayerState checks that the fee payer of the fee delegation tx
+// can pay the max fee of the tx.
+func (tx *transaction) ValidateWithFeePayerState(payerState *State) error {
+	if tx.GetMaxFee().Cmp(payerState.GetBalanceBigInt()) > 0 {
+		return ErrInsufficientFeePayerBalance
+	}
+	return nil
+}
+
 //TODO : refoctor after ContractState move to types
 func (tx *Tx) ValidateWithContractState(contractState *State) error {
 	//in system.ValidateSystemTx
@@ -317,15 +398,22 @@ func (tx *transaction) Clone() *transaction {
 		return &transaction{}
 	}
 	body := &TxBody{
-		Nonce:     tx.GetBody().Nonce,
-		Account:   Clone(tx.GetBody().Account).([]byte),
-		Recipient: Clone(tx.GetBody().Recipient).([]byte),
-		Amount:    Clone(tx.GetBody().Amount).([]byte),
-		Payload:   Clone(tx.GetBody().Payload).([]byte),
-		GasLimit:  tx.GetBody().GasLimit,
-		GasPrice:  Clone(tx.GetBody().GasPrice).([]byte),
-		Type:      tx.GetBody().Type,
-		Sign:      Clone(tx.GetBody().Sign).([]byte),
+		Nonce:      tx.GetBody().Nonce,
+		Account:    Clone(tx.GetBody().Account).([]byte),
+		Recipient:  Clone(tx.GetBody().Recipient).([]byte),
+		Amount:     Clone(tx.GetBody().Amount).([]byte),
+		Payload:    Clone(tx.GetBody().Payload).([]byte),
+		GasLimit:   tx.GetBody().GasLimit,
+		GasPrice:   Clone(tx.GetBody().GasPrice).([]byte),
+		Type:       tx.GetBody().Type,
+		Sign:       Clone(tx.GetBody().Sign).([]byte),
+		NotBefore:  tx.GetBody().NotBefore,
+		ValidUntil: tx.GetBody().ValidUntil,
+		FeePayer:   Clone(tx.GetBody().FeePayer).([]byte),
+		PayerSign:  Clone(tx.GetBody().PayerSign).([]byte),
+		Multisig:   tx.GetBody().Multisig,
+		Signatures: tx.GetBody().Signatures,
+		Ops:        tx.GetBody().Ops,
 	}
 	res := &transaction{
 		Tx: &Tx{Body: body},
@@ -335,6 +423,12 @@ func (tx *transaction) Clone() *transaction {
 }
 
 func (tx *transaction) GetMaxFee() *big.Int {
+	if fee.IsGasMetered(tx.GetBody().GetGasLimit()) {
+		return fee.MaxGasTxFee(tx.GetBody().GetGasLimit(), tx.GetBody().GetGasPriceBigInt())
+	}
+	if tx.GetBody().GetType() == TxType_BATCH {
+		return tx.GetBody().opsMaxFee()
+	}
 	return fee.MaxPayloadTxFee(len(tx.GetBody().GetPayload()))
 }
 
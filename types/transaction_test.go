@@ -2,9 +2,11 @@ package types
 
 import (
 	"encoding/json"
+	"math/big"
 	"strconv"
 	"testing"
 
+	"github.com/aergoio/aergo/fee"
 	crypto "github.com/libp2p/go-libp2p-crypto"
 	peer "github.com/libp2p/go-libp2p-peer"
 	"github.com/stretchr/testify/assert"
@@ -111,6 +113,124 @@ func TestGovernanceTypeTransaction(t *testing.T) {
 	assert.Error(t, err, "invalid name length in update")
 }
 
+func TestGasLimitTransaction(t *testing.T) {
+	const testSender = "AmPNYHyzyh9zweLwDyuoiUuTVCdrdksxkRWDjVJS76WQLExa2Jr4"
+	account, err := DecodeAddress(testSender)
+	assert.NoError(t, err, "should success to decode test address")
+	chainid := []byte("chainid")
+	tx := &Tx{
+		Body: &TxBody{
+			Account:     account,
+			Recipient:   account,
+			Amount:      new(big.Int).SetUint64(1).Bytes(),
+			GasPrice:    fee.MinGasPrice().Bytes(),
+			ChainIdHash: chainid,
+		},
+	}
+	transaction := NewTransaction(tx)
+
+	transaction.GetTx().GetBody().GasLimit = fee.MaxGasLimit + 1
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	err = transaction.Validate(chainid)
+	assert.EqualError(t, err, ErrTxInvalidGasLimit.Error(), "too large gas limit")
+
+	transaction.GetTx().GetBody().GasLimit = fee.TxBaseGas - 1
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	err = transaction.Validate(chainid)
+	assert.EqualError(t, err, ErrTxGasLimitTooLow.Error(), "lower than intrinsic gas")
+
+	transaction.GetTx().GetBody().GasLimit = fee.TxBaseGas
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	err = transaction.Validate(chainid)
+	assert.NoError(t, err, "should success")
+	assert.Equal(t, fee.MaxGasTxFee(fee.TxBaseGas, fee.MinGasPrice()), transaction.GetMaxFee(), "max fee")
+
+	transaction.GetTx().GetBody().GasPrice = new(big.Int).Sub(fee.MinGasPrice(), big.NewInt(1)).Bytes()
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	err = transaction.Validate(chainid)
+	assert.EqualError(t, err, ErrTxGasPriceTooLow.Error(), "lower than min gas price")
+
+	transaction.GetTx().GetBody().GasPrice = nil
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	err = transaction.Validate(chainid)
+	assert.EqualError(t, err, ErrTxGasPriceTooLow.Error(), "zero gas price")
+
+	transaction.GetTx().GetBody().GasLimit = 0
+	assert.Equal(t, fee.MaxPayloadTxFee(0), transaction.GetMaxFee(), "payload based max fee")
+}
+
+func TestFeeDelegationTransaction(t *testing.T) {
+	const testSender = "AmPNYHyzyh9zweLwDyuoiUuTVCdrdksxkRWDjVJS76WQLExa2Jr4"
+	account, err := DecodeAddress(testSender)
+	assert.NoError(t, err, "should success to decode test address")
+	payer := append([]byte{0x03}, account[1:]...)
+	chainid := []byte("chainid")
+	tx := &Tx{
+		Body: &TxBody{
+			Account:     account,
+			Recipient:   account,
+			Amount:      new(big.Int).SetUint64(1).Bytes(),
+			ChainIdHash: chainid,
+			Type:        TxType_FEE_DELEGATION,
+			FeePayer:    payer,
+			PayerSign:   []byte("sign"),
+		},
+	}
+	transaction := NewTransaction(tx)
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.NoError(t, transaction.Validate(chainid), "should success")
+
+	transaction.GetTx().GetBody().FeePayer = account
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.EqualError(t, transaction.Validate(chainid), ErrTxInvalidFeePayer.Error(), "sender as fee payer")
+
+	transaction.GetTx().GetBody().FeePayer = payer
+	transaction.GetTx().GetBody().Type = TxType_NORMAL
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.EqualError(t, transaction.Validate(chainid), ErrTxInvalidFeePayer.Error(), "fee payer of normal tx")
+	transaction.GetTx().GetBody().Type = TxType_FEE_DELEGATION
+
+	// the sender pays only the amount
+	sender := &State{Nonce: 0, Balance: new(big.Int).SetUint64(1).Bytes()}
+	assert.NoError(t, transaction.ValidateWithSenderState(sender))
+	assert.EqualError(t, transaction.ValidateWithFeePayerState(sender), ErrInsufficientFeePayerBalance.Error())
+	assert.NoError(t, transaction.ValidateWithFeePayerState(&State{Balance: transaction.GetMaxFee().Bytes()}))
+}
+
+func TestRedeployTransaction(t *testing.T) {
+	const testSender = "AmPNYHyzyh9zweLwDyuoiUuTVCdrdksxkRWDjVJS76WQLExa2Jr4"
+	account, err := DecodeAddress(testSender)
+	assert.NoError(t, err, "should success to decode test address")
+	contract := append([]byte{0x0C}, account[1:]...)
+	chainid := []byte("chainid")
+	transaction := NewTransaction(&Tx{
+		Body: &TxBody{
+			Account:     account,
+			Recipient:   contract,
+			Payload:     []byte("code"),
+			ChainIdHash: chainid,
+			Type:        TxType_REDEPLOY,
+		},
+	})
+	body := transaction.GetTx().GetBody()
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.NoError(t, transaction.Validate(chainid), "should success")
+
+	body.Amount = new(big.Int).SetUint64(1).Bytes()
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.EqualError(t, transaction.Validate(chainid), ErrTxInvalidAmount.Error(), "redeploy with amount")
+
+	body.Amount = nil
+	body.Payload = nil
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.EqualError(t, transaction.Validate(chainid), ErrTxInvalidPayload.Error(), "redeploy without code")
+
+	body.Payload = []byte("code")
+	body.Recipient = nil
+	transaction.GetTx().Hash = transaction.CalculateTxHash()
+	assert.EqualError(t, transaction.Validate(chainid), ErrTxInvalidRecipient.Error(), "redeploy without contract")
+}
+
 func buildVoteBPPayloadEx(count int, err int) []byte {
 	var ci CallInfo
 	ci.Name = VoteBP